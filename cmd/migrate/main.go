@@ -0,0 +1,21 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/demkowo/users/internal/app"
+)
+
+func main() {
+	log.Println("--- migrate/main() ---")
+
+	if len(os.Args) != 2 {
+		log.Fatalf("usage: %s <migration>", os.Args[0])
+	}
+
+	if err := app.Migrate(context.Background(), os.Args[1]); err != nil {
+		log.Fatal(err)
+	}
+}
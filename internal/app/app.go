@@ -1,11 +1,16 @@
 package app
 
 import (
+	"context"
 	"database/sql"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/demkowo/users/internal/config"
 	handler "github.com/demkowo/users/internal/handlers/gin"
+	"github.com/demkowo/users/internal/repositories/filesystem"
 	"github.com/demkowo/users/internal/repositories/postgres"
 	service "github.com/demkowo/users/internal/services"
 	"github.com/gin-gonic/gin"
@@ -13,7 +18,31 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-const portNumber = ":5000"
+const (
+	portNumber                 = ":5000"
+	defaultNicknameGracePeriod = 30 * 24 * time.Hour
+	defaultImportBatchSize     = 100
+	defaultImportMaxRows       = 10000
+	defaultEventsHistory       = 1000
+	defaultOutboxPollInterval  = time.Second
+	defaultOutboxBatchSize     = 100
+	defaultOutboxRetryBackoff  = time.Second
+	defaultOutboxMaxBackoff    = 10 * time.Minute
+	defaultWebhookPollInterval = time.Second
+	defaultWebhookBatchSize    = 20
+	defaultWebhookTimeout      = 10 * time.Second
+	defaultWebhookMaxAttempts  = 8
+	defaultWebhookRetryBackoff = 30 * time.Second
+	defaultWebhookMaxBackoff   = time.Hour
+	defaultBlobDir             = "data/blobs"
+	defaultAvatarMaxSize       = 5 << 20
+	defaultAvatarMaxDimension  = 4096
+	defaultAvatarBaseURL       = "/api/v1/avatars/"
+	defaultNicknameMinLength   = 3
+	defaultNicknameMaxLength   = 32
+	defaultNearbyMaxRadiusKm   = 500
+	defaultClubRequestTTL      = 14 * 24 * time.Hour
+)
 
 var (
 	conf         = config.Values.Get()
@@ -24,6 +53,30 @@ var (
 func init() {
 	conf.UseCache = false
 	conf.InProduction = false
+	conf.NicknameGracePeriod = durationFromEnv("NICKNAME_GRACE_PERIOD", defaultNicknameGracePeriod)
+	conf.ImportBatchSize = intFromEnv("IMPORT_BATCH_SIZE", defaultImportBatchSize)
+	conf.ImportMaxRows = intFromEnv("IMPORT_MAX_ROWS", defaultImportMaxRows)
+	conf.EventsHistory = intFromEnv("EVENTS_HISTORY", defaultEventsHistory)
+	conf.OutboxPollInterval = durationFromEnv("OUTBOX_POLL_INTERVAL", defaultOutboxPollInterval)
+	conf.OutboxBatchSize = intFromEnv("OUTBOX_BATCH_SIZE", defaultOutboxBatchSize)
+	conf.OutboxRetryBackoff = durationFromEnv("OUTBOX_RETRY_BACKOFF", defaultOutboxRetryBackoff)
+	conf.OutboxMaxBackoff = durationFromEnv("OUTBOX_MAX_BACKOFF", defaultOutboxMaxBackoff)
+	conf.WebhookPollInterval = durationFromEnv("WEBHOOK_POLL_INTERVAL", defaultWebhookPollInterval)
+	conf.WebhookBatchSize = intFromEnv("WEBHOOK_BATCH_SIZE", defaultWebhookBatchSize)
+	conf.WebhookTimeout = durationFromEnv("WEBHOOK_TIMEOUT", defaultWebhookTimeout)
+	conf.WebhookMaxAttempts = intFromEnv("WEBHOOK_MAX_ATTEMPTS", defaultWebhookMaxAttempts)
+	conf.WebhookRetryBackoff = durationFromEnv("WEBHOOK_RETRY_BACKOFF", defaultWebhookRetryBackoff)
+	conf.WebhookMaxBackoff = durationFromEnv("WEBHOOK_MAX_BACKOFF", defaultWebhookMaxBackoff)
+	conf.BlobDir = stringFromEnv("BLOB_DIR", defaultBlobDir)
+	conf.AvatarMaxSize = intFromEnv("AVATAR_MAX_SIZE", defaultAvatarMaxSize)
+	conf.AvatarMaxDimension = intFromEnv("AVATAR_MAX_DIMENSION", defaultAvatarMaxDimension)
+	conf.AvatarBaseURL = stringFromEnv("AVATAR_BASE_URL", defaultAvatarBaseURL)
+	conf.NicknameMinLength = intFromEnv("NICKNAME_MIN_LENGTH", defaultNicknameMinLength)
+	conf.NicknameMaxLength = intFromEnv("NICKNAME_MAX_LENGTH", defaultNicknameMaxLength)
+	conf.NicknamePattern = stringFromEnv("NICKNAME_PATTERN", service.DefaultNicknamePattern)
+	conf.NicknameReserved = reservedNicknamesFromEnv()
+	conf.NearbyMaxRadiusKm = intFromEnv("NEARBY_MAX_RADIUS_KM", defaultNearbyMaxRadiusKm)
+	conf.ClubRequestTTL = durationFromEnv("CLUB_REQUEST_TTL", defaultClubRequestTTL)
 	config.Values.Set(*conf)
 }
 
@@ -36,15 +89,132 @@ func Start() {
 	}
 	defer db.Close()
 
+	events := service.NewEvents(service.EventsConfig{
+		History: conf.EventsHistory,
+	})
+
+	router.Use(handler.AuditActor())
+	router.Use(handler.Locale())
+
+	webhooksRepo := postgres.NewWebhooks(db)
+	webhooksService := service.NewWebhooks(webhooksRepo, service.WebhooksConfig{
+		PollInterval: conf.WebhookPollInterval,
+		BatchSize:    int32(conf.WebhookBatchSize),
+		Timeout:      conf.WebhookTimeout,
+		MaxAttempts:  int32(conf.WebhookMaxAttempts),
+		RetryBackoff: conf.WebhookRetryBackoff,
+		MaxBackoff:   conf.WebhookMaxBackoff,
+	})
+	webhooksHandler := handler.NewWebhooks(webhooksService)
+	addWebhookRoutes(webhooksHandler)
+	go webhooksService.Run(context.Background())
+
 	usersRepo := postgres.NewUsers(db)
-	usersService := service.NewUsers(usersRepo)
+	blobStore := filesystem.NewBlobStore(conf.BlobDir)
+	usersService := service.NewUsers(usersRepo, blobStore, events, service.UsersConfig{
+		NicknameGracePeriod: conf.NicknameGracePeriod,
+		ImportBatchSize:     conf.ImportBatchSize,
+		ImportMaxRows:       conf.ImportMaxRows,
+		AvatarMaxSize:       int64(conf.AvatarMaxSize),
+		AvatarMaxDimension:  conf.AvatarMaxDimension,
+		AvatarBaseURL:       conf.AvatarBaseURL,
+		NearbyMaxRadiusKm:   float64(conf.NearbyMaxRadiusKm),
+		Nickname: service.NicknamePolicy{
+			MinLength: conf.NicknameMinLength,
+			MaxLength: conf.NicknameMaxLength,
+			Pattern:   conf.NicknamePattern,
+			Reserved:  conf.NicknameReserved,
+		},
+	})
 	usersHandler := handler.NewUser(usersService)
 	addUserRoutes(usersHandler)
 
-	go pbServerStart(usersService)
+	clubsRepo := postgres.NewClubs(db)
+	clubsService := service.NewClubs(clubsRepo, events, service.ClubsConfig{
+		RequestTTL: conf.ClubRequestTTL,
+	})
+	clubsHandler := handler.NewClubs(clubsService)
+	addClubRoutes(clubsHandler)
+
+	outboxRelay := service.NewOutbox(postgres.NewOutbox(db), service.NewPublishers(service.NewLogPublisher(), webhooksService), service.OutboxConfig{
+		PollInterval: conf.OutboxPollInterval,
+		BatchSize:    int32(conf.OutboxBatchSize),
+		RetryBackoff: conf.OutboxRetryBackoff,
+		MaxBackoff:   conf.OutboxMaxBackoff,
+	})
+	go outboxRelay.Run(context.Background())
+
+	go pbServerStart(usersService, clubsService, webhooksService)
 
 	log.Infof("Starting server on %s", portNumber)
 	if err := router.Run(portNumber); err != nil {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// reservedNicknamesFromEnv reads NICKNAME_RESERVED and its _FILE; nil keeps the defaults.
+func reservedNicknamesFromEnv() []string {
+	list, file := os.Getenv("NICKNAME_RESERVED"), os.Getenv("NICKNAME_RESERVED_FILE")
+	if list == "" && file == "" {
+		return service.DefaultReservedNicknames
+	}
+
+	reserved := []string{}
+	for _, n := range strings.Split(list, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			reserved = append(reserved, n)
+		}
+	}
+
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			log.Panicf("failed to read NICKNAME_RESERVED_FILE: %v", err)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			line, _, _ = strings.Cut(line, "#")
+			if line = strings.TrimSpace(line); line != "" {
+				reserved = append(reserved, line)
+			}
+		}
+	}
+
+	return reserved
+}
+
+func stringFromEnv(key string, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Warnf("invalid %s value %q, using %s", key, v, fallback)
+		return fallback
+	}
+
+	return d
+}
+
+func intFromEnv(key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil || i <= 0 {
+		log.Warnf("invalid %s value %q, using %d", key, v, fallback)
+		return fallback
+	}
+
+	return i
+}
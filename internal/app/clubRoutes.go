@@ -0,0 +1,26 @@
+package app
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	handler "github.com/demkowo/users/internal/handlers/gin"
+)
+
+func addClubRoutes(h handler.Clubs) {
+	log.Println("--- Setting Club Routes ---")
+
+	router.POST("/api/v1/clubs/add", h.Add)
+	router.PUT("/api/v1/clubs/edit/:club_id", h.Rename)
+	router.DELETE("/api/v1/clubs/delete/:club_id", h.Delete)
+	router.GET("/api/v1/clubs/get/:club_id", h.GetById)
+	router.GET("/api/v1/clubs/list", h.List)
+	router.GET("/api/v1/clubs/:club_id/members", h.ListMembers)
+	router.PUT("/api/v1/clubs/:club_id/members/:user_id/role", h.SetMemberRole)
+	router.PUT("/api/v1/clubs/:club_id/join-policy", h.SetJoinPolicy)
+	router.POST("/api/v1/clubs/:club_id/requests", h.RequestToJoin)
+	router.POST("/api/v1/clubs/:club_id/invitations", h.Invite)
+	router.GET("/api/v1/clubs/:club_id/requests", h.ListRequests)
+	router.PUT("/api/v1/clubs/:club_id/requests/:request_id/accept", h.AcceptRequest)
+	router.PUT("/api/v1/clubs/:club_id/requests/:request_id/reject", h.RejectRequest)
+	router.GET("/api/v1/users/:user_id/club-requests", h.ListUserRequests)
+}
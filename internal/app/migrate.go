@@ -0,0 +1,81 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/demkowo/users/internal/repositories/postgres"
+	log "github.com/sirupsen/logrus"
+)
+
+// migrations are the data migrations Migrate can run, by name.
+var migrations = map[string]func(ctx context.Context, db *sql.DB) error{
+	"cities":    migrateCities,
+	"countries": migrateCountries,
+	"nicknames": migrateNicknames,
+}
+
+// Migrate runs the named data migration against the database in
+// DB_CONNECTION.
+func Migrate(ctx context.Context, name string) error {
+	log.Trace()
+
+	migrate, ok := migrations[name]
+	if !ok {
+		return fmt.Errorf("unknown migration %q", name)
+	}
+
+	db, err := sql.Open("postgres", dbConnection)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return migrate(ctx, db)
+}
+
+// migrateNicknames fills in the nickname keys and skeletons of older rows.
+func migrateNicknames(ctx context.Context, db *sql.DB) error {
+	filled, conflicts, err := postgres.NewUsers(db).BackfillNicknameKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %v", err.Error, err.Causes)
+	}
+
+	log.Infof("Filled in %d nickname keys", filled)
+	for _, u := range conflicts {
+		log.Warnf("User %s: nickname %q clashes with another user's and must be changed", u.ID, u.Nickname)
+	}
+
+	return nil
+}
+
+// migrateCountries maps the free-text countries of older rows to alpha-2 codes.
+func migrateCountries(ctx context.Context, db *sql.DB) error {
+	changed, unmapped, err := postgres.NewUsers(db).NormalizeCountries(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %v", err.Error, err.Causes)
+	}
+
+	log.Infof("Normalized the country of %d users", changed)
+	for _, country := range unmapped {
+		log.Warnf("Country %q matches no ISO 3166-1 country and must be fixed by hand", country)
+	}
+
+	return nil
+}
+
+// migrateCities resolves the cities of older rows; it needs migrated countries.
+func migrateCities(ctx context.Context, db *sql.DB) error {
+	resolved, unresolved, err := postgres.NewUsers(db).BackfillCityIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %v", err.Error, err.Causes)
+	}
+
+	log.Infof("Resolved the city of %d users", resolved)
+	for _, place := range unresolved {
+		log.Warnf("City %q is not in the gazetteer; its users won't be found nearby", place)
+	}
+
+	return nil
+}
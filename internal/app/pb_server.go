@@ -12,7 +12,7 @@ import (
 
 const pbPortNumber = ":50000"
 
-func pbServerStart(serv service.Users) {
+func pbServerStart(usersService service.Users, clubsService service.Clubs, webhooksService service.Webhooks) {
 
 	lis, err := net.Listen("tcp", pbPortNumber)
 	if err != nil {
@@ -21,8 +21,13 @@ func pbServerStart(serv service.Users) {
 
 	log.Println("gRPC server listen on", pbPortNumber)
 
-	s := grpc.NewServer()
-	pb.RegisterUsersServer(s, &handler.UsersServer{Service: serv})
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(handler.AuditActorUnaryInterceptor),
+		grpc.ChainStreamInterceptor(handler.AuditActorStreamInterceptor),
+	)
+	pb.RegisterUsersServer(s, &handler.UsersServer{Service: usersService})
+	pb.RegisterClubsServer(s, &handler.ClubsServer{Service: clubsService})
+	pb.RegisterWebhooksServer(s, &handler.WebhooksServer{Service: webhooksService})
 
 	if err := s.Serve(lis); err != nil {
 		log.Fatal("failed to start gRPC server", err)
@@ -10,11 +10,28 @@ func addUserRoutes(h handler.Users) {
 	log.Println("--- Setting User Routes ---")
 
 	router.POST("/api/v1/users/add", h.Add)
+	router.POST("/api/v1/users/import", h.Import)
+	router.POST("/api/v1/users/:user_id/avatar", h.UploadAvatar)
 	router.PUT("/api/v1/users/edit/:user_id", h.Update)
+	router.PATCH("/api/v1/users/:user_id", h.Patch)
 	router.PUT("/api/v1/users/edit-img/:user_id", h.UpdateImg)
+	router.PUT("/api/v1/users/edit-nickname/:user_id", h.ChangeNickname)
 	router.DELETE("/api/v1/users/delete/:user_id", h.Delete)
+	router.PUT("/api/v1/users/restore/:user_id", h.Restore)
+	router.DELETE("/api/v1/users/purge/:user_id", h.Purge)
+	router.PUT("/api/v1/users/:user_id/following/:followee_id", h.Follow)
+	router.DELETE("/api/v1/users/:user_id/following/:followee_id", h.Unfollow)
 	router.GET("/api/v1/users/get/:user_id", h.GetById)
 	router.GET("/api/v1/users/get-avatar/:nickname", h.GetAvatarByNickname)
+	router.GET("/api/v1/users/get-by-nickname/:nickname", h.GetByNickname)
+	router.GET("/api/v1/users/events", h.Events)
+	router.GET("/api/v1/users/export", h.Export)
 	router.GET("/api/v1/users/find", h.Find)
 	router.GET("/api/v1/users/list", h.List)
+	router.GET("/api/v1/users/nearby", h.FindNearby)
+	router.GET("/api/v1/users/search", h.Search)
+	router.GET("/api/v1/users/:user_id/audit", h.ListAudit)
+	router.GET("/api/v1/users/:user_id/followers", h.ListFollowers)
+	router.GET("/api/v1/users/:user_id/following", h.ListFollowing)
+	router.GET("/api/v1/avatars/*key", h.ServeAvatar)
 }
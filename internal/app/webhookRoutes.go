@@ -0,0 +1,19 @@
+package app
+
+import (
+	log "github.com/sirupsen/logrus"
+
+	handler "github.com/demkowo/users/internal/handlers/gin"
+)
+
+func addWebhookRoutes(h handler.Webhooks) {
+	log.Println("--- Setting Webhook Routes ---")
+
+	router.POST("/api/v1/webhooks/add", h.Add)
+	router.PUT("/api/v1/webhooks/edit/:webhook_id", h.Update)
+	router.DELETE("/api/v1/webhooks/delete/:webhook_id", h.Delete)
+	router.GET("/api/v1/webhooks/get/:webhook_id", h.GetById)
+	router.GET("/api/v1/webhooks/list", h.List)
+	router.GET("/api/v1/webhooks/:webhook_id/deliveries", h.ListDeliveries)
+	router.POST("/api/v1/webhooks/:webhook_id/deliveries/:delivery_id/redeliver", h.Redeliver)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"html/template"
 	"log"
+	"time"
 )
 
 var Values ci = &conf{}
@@ -17,6 +18,31 @@ type conf struct {
 	TemplateCache map[string]*template.Template
 	InProduction  bool
 	Session       string
+
+	NicknameGracePeriod time.Duration
+	ImportBatchSize     int
+	ImportMaxRows       int
+	EventsHistory       int
+	OutboxPollInterval  time.Duration
+	OutboxBatchSize     int
+	OutboxRetryBackoff  time.Duration
+	OutboxMaxBackoff    time.Duration
+	WebhookPollInterval time.Duration
+	WebhookBatchSize    int
+	WebhookTimeout      time.Duration
+	WebhookMaxAttempts  int
+	WebhookRetryBackoff time.Duration
+	WebhookMaxBackoff   time.Duration
+	BlobDir             string
+	AvatarMaxSize       int
+	AvatarMaxDimension  int
+	AvatarBaseURL       string
+	NicknameMinLength   int
+	NicknameMaxLength   int
+	NicknamePattern     string
+	NicknameReserved    []string
+	NearbyMaxRadiusKm   int
+	ClubRequestTTL      time.Duration
 }
 
 func (m *conf) Get() *conf {
@@ -30,4 +56,28 @@ func (m *conf) Set(c conf) {
 	m.InProduction = c.InProduction
 	m.Session = c.Session
 	m.TemplateCache = c.TemplateCache
+	m.NicknameGracePeriod = c.NicknameGracePeriod
+	m.ImportBatchSize = c.ImportBatchSize
+	m.ImportMaxRows = c.ImportMaxRows
+	m.EventsHistory = c.EventsHistory
+	m.OutboxPollInterval = c.OutboxPollInterval
+	m.OutboxBatchSize = c.OutboxBatchSize
+	m.OutboxRetryBackoff = c.OutboxRetryBackoff
+	m.OutboxMaxBackoff = c.OutboxMaxBackoff
+	m.WebhookPollInterval = c.WebhookPollInterval
+	m.WebhookBatchSize = c.WebhookBatchSize
+	m.WebhookTimeout = c.WebhookTimeout
+	m.WebhookMaxAttempts = c.WebhookMaxAttempts
+	m.WebhookRetryBackoff = c.WebhookRetryBackoff
+	m.WebhookMaxBackoff = c.WebhookMaxBackoff
+	m.BlobDir = c.BlobDir
+	m.AvatarMaxSize = c.AvatarMaxSize
+	m.AvatarMaxDimension = c.AvatarMaxDimension
+	m.AvatarBaseURL = c.AvatarBaseURL
+	m.NicknameMinLength = c.NicknameMinLength
+	m.NicknameMaxLength = c.NicknameMaxLength
+	m.NicknamePattern = c.NicknamePattern
+	m.NicknameReserved = c.NicknameReserved
+	m.NearbyMaxRadiusKm = c.NearbyMaxRadiusKm
+	m.ClubRequestTTL = c.ClubRequestTTL
 }
@@ -20,13 +20,28 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	Users_Add_FullMethodName                 = "/users.Users/Add"
+	Users_ChangeNickname_FullMethodName      = "/users.Users/ChangeNickname"
 	Users_Delete_FullMethodName              = "/users.Users/Delete"
+	Users_ExportUsers_FullMethodName         = "/users.Users/ExportUsers"
 	Users_Find_FullMethodName                = "/users.Users/Find"
+	Users_FindNearbyUsers_FullMethodName     = "/users.Users/FindNearbyUsers"
+	Users_Follow_FullMethodName              = "/users.Users/Follow"
 	Users_GetAvatarByNickname_FullMethodName = "/users.Users/GetAvatarByNickname"
 	Users_GetById_FullMethodName             = "/users.Users/GetById"
+	Users_GetByNickname_FullMethodName       = "/users.Users/GetByNickname"
+	Users_ImportUsers_FullMethodName         = "/users.Users/ImportUsers"
 	Users_List_FullMethodName                = "/users.Users/List"
+	Users_ListAuditEntries_FullMethodName    = "/users.Users/ListAuditEntries"
+	Users_ListFollowers_FullMethodName       = "/users.Users/ListFollowers"
+	Users_ListFollowing_FullMethodName       = "/users.Users/ListFollowing"
+	Users_Purge_FullMethodName               = "/users.Users/Purge"
+	Users_Restore_FullMethodName             = "/users.Users/Restore"
+	Users_Search_FullMethodName              = "/users.Users/Search"
+	Users_Unfollow_FullMethodName            = "/users.Users/Unfollow"
 	Users_Update_FullMethodName              = "/users.Users/Update"
 	Users_UpdateImg_FullMethodName           = "/users.Users/UpdateImg"
+	Users_UploadAvatar_FullMethodName        = "/users.Users/UploadAvatar"
+	Users_Watch_FullMethodName               = "/users.Users/Watch"
 )
 
 // UsersClient is the client API for Users service.
@@ -34,13 +49,28 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type UsersClient interface {
 	Add(ctx context.Context, in *AddUserRequest, opts ...grpc.CallOption) (*AddUserResponse, error)
+	ChangeNickname(ctx context.Context, in *ChangeNicknameRequest, opts ...grpc.CallOption) (*ChangeNicknameResponse, error)
 	Delete(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error)
+	ExportUsers(ctx context.Context, in *ExportUsersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportUsersResponse], error)
 	Find(ctx context.Context, in *FindUsersRequest, opts ...grpc.CallOption) (*FindUsersResponse, error)
+	FindNearbyUsers(ctx context.Context, in *FindNearbyUsersRequest, opts ...grpc.CallOption) (*FindNearbyUsersResponse, error)
+	Follow(ctx context.Context, in *FollowUserRequest, opts ...grpc.CallOption) (*FollowUserResponse, error)
 	GetAvatarByNickname(ctx context.Context, in *GetAvatarByNicknameRequest, opts ...grpc.CallOption) (*GetAvatarByNicknameResponse, error)
 	GetById(ctx context.Context, in *GetByIdRequest, opts ...grpc.CallOption) (*GetByIdResponse, error)
+	GetByNickname(ctx context.Context, in *GetByNicknameRequest, opts ...grpc.CallOption) (*GetByNicknameResponse, error)
+	ImportUsers(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportUsersRequest, ImportUsersResponse], error)
 	List(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error)
+	ListAuditEntries(ctx context.Context, in *ListAuditEntriesRequest, opts ...grpc.CallOption) (*ListAuditEntriesResponse, error)
+	ListFollowers(ctx context.Context, in *ListFollowersRequest, opts ...grpc.CallOption) (*ListFollowersResponse, error)
+	ListFollowing(ctx context.Context, in *ListFollowingRequest, opts ...grpc.CallOption) (*ListFollowingResponse, error)
+	Purge(ctx context.Context, in *PurgeUserRequest, opts ...grpc.CallOption) (*PurgeUserResponse, error)
+	Restore(ctx context.Context, in *RestoreUserRequest, opts ...grpc.CallOption) (*RestoreUserResponse, error)
+	Search(ctx context.Context, in *SearchUsersRequest, opts ...grpc.CallOption) (*SearchUsersResponse, error)
+	Unfollow(ctx context.Context, in *UnfollowUserRequest, opts ...grpc.CallOption) (*UnfollowUserResponse, error)
 	Update(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*UpdateUserResponse, error)
 	UpdateImg(ctx context.Context, in *UpdateImgRequest, opts ...grpc.CallOption) (*UpdateImgResponse, error)
+	UploadAvatar(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadAvatarRequest, UploadAvatarResponse], error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchResponse], error)
 }
 
 type usersClient struct {
@@ -61,6 +91,16 @@ func (c *usersClient) Add(ctx context.Context, in *AddUserRequest, opts ...grpc.
 	return out, nil
 }
 
+func (c *usersClient) ChangeNickname(ctx context.Context, in *ChangeNicknameRequest, opts ...grpc.CallOption) (*ChangeNicknameResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangeNicknameResponse)
+	err := c.cc.Invoke(ctx, Users_ChangeNickname_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *usersClient) Delete(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*DeleteUserResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeleteUserResponse)
@@ -71,6 +111,25 @@ func (c *usersClient) Delete(ctx context.Context, in *DeleteUserRequest, opts ..
 	return out, nil
 }
 
+func (c *usersClient) ExportUsers(ctx context.Context, in *ExportUsersRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportUsersResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Users_ServiceDesc.Streams[0], Users_ExportUsers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportUsersRequest, ExportUsersResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Users_ExportUsersClient = grpc.ServerStreamingClient[ExportUsersResponse]
+
 func (c *usersClient) Find(ctx context.Context, in *FindUsersRequest, opts ...grpc.CallOption) (*FindUsersResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(FindUsersResponse)
@@ -81,6 +140,26 @@ func (c *usersClient) Find(ctx context.Context, in *FindUsersRequest, opts ...gr
 	return out, nil
 }
 
+func (c *usersClient) FindNearbyUsers(ctx context.Context, in *FindNearbyUsersRequest, opts ...grpc.CallOption) (*FindNearbyUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindNearbyUsersResponse)
+	err := c.cc.Invoke(ctx, Users_FindNearbyUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) Follow(ctx context.Context, in *FollowUserRequest, opts ...grpc.CallOption) (*FollowUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FollowUserResponse)
+	err := c.cc.Invoke(ctx, Users_Follow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *usersClient) GetAvatarByNickname(ctx context.Context, in *GetAvatarByNicknameRequest, opts ...grpc.CallOption) (*GetAvatarByNicknameResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetAvatarByNicknameResponse)
@@ -101,6 +180,29 @@ func (c *usersClient) GetById(ctx context.Context, in *GetByIdRequest, opts ...g
 	return out, nil
 }
 
+func (c *usersClient) GetByNickname(ctx context.Context, in *GetByNicknameRequest, opts ...grpc.CallOption) (*GetByNicknameResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetByNicknameResponse)
+	err := c.cc.Invoke(ctx, Users_GetByNickname_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) ImportUsers(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportUsersRequest, ImportUsersResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Users_ServiceDesc.Streams[1], Users_ImportUsers_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportUsersRequest, ImportUsersResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Users_ImportUsersClient = grpc.ClientStreamingClient[ImportUsersRequest, ImportUsersResponse]
+
 func (c *usersClient) List(ctx context.Context, in *ListUsersRequest, opts ...grpc.CallOption) (*ListUsersResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListUsersResponse)
@@ -111,6 +213,76 @@ func (c *usersClient) List(ctx context.Context, in *ListUsersRequest, opts ...gr
 	return out, nil
 }
 
+func (c *usersClient) ListAuditEntries(ctx context.Context, in *ListAuditEntriesRequest, opts ...grpc.CallOption) (*ListAuditEntriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditEntriesResponse)
+	err := c.cc.Invoke(ctx, Users_ListAuditEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) ListFollowers(ctx context.Context, in *ListFollowersRequest, opts ...grpc.CallOption) (*ListFollowersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFollowersResponse)
+	err := c.cc.Invoke(ctx, Users_ListFollowers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) ListFollowing(ctx context.Context, in *ListFollowingRequest, opts ...grpc.CallOption) (*ListFollowingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFollowingResponse)
+	err := c.cc.Invoke(ctx, Users_ListFollowing_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) Purge(ctx context.Context, in *PurgeUserRequest, opts ...grpc.CallOption) (*PurgeUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeUserResponse)
+	err := c.cc.Invoke(ctx, Users_Purge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) Restore(ctx context.Context, in *RestoreUserRequest, opts ...grpc.CallOption) (*RestoreUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreUserResponse)
+	err := c.cc.Invoke(ctx, Users_Restore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) Search(ctx context.Context, in *SearchUsersRequest, opts ...grpc.CallOption) (*SearchUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchUsersResponse)
+	err := c.cc.Invoke(ctx, Users_Search_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersClient) Unfollow(ctx context.Context, in *UnfollowUserRequest, opts ...grpc.CallOption) (*UnfollowUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnfollowUserResponse)
+	err := c.cc.Invoke(ctx, Users_Unfollow_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *usersClient) Update(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*UpdateUserResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(UpdateUserResponse)
@@ -131,18 +303,65 @@ func (c *usersClient) UpdateImg(ctx context.Context, in *UpdateImgRequest, opts
 	return out, nil
 }
 
+func (c *usersClient) UploadAvatar(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadAvatarRequest, UploadAvatarResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Users_ServiceDesc.Streams[2], Users_UploadAvatar_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadAvatarRequest, UploadAvatarResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Users_UploadAvatarClient = grpc.ClientStreamingClient[UploadAvatarRequest, UploadAvatarResponse]
+
+func (c *usersClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Users_ServiceDesc.Streams[3], Users_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Users_WatchClient = grpc.ServerStreamingClient[WatchResponse]
+
 // UsersServer is the server API for Users service.
 // All implementations must embed UnimplementedUsersServer
 // for forward compatibility.
 type UsersServer interface {
 	Add(context.Context, *AddUserRequest) (*AddUserResponse, error)
+	ChangeNickname(context.Context, *ChangeNicknameRequest) (*ChangeNicknameResponse, error)
 	Delete(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error)
+	ExportUsers(*ExportUsersRequest, grpc.ServerStreamingServer[ExportUsersResponse]) error
 	Find(context.Context, *FindUsersRequest) (*FindUsersResponse, error)
+	FindNearbyUsers(context.Context, *FindNearbyUsersRequest) (*FindNearbyUsersResponse, error)
+	Follow(context.Context, *FollowUserRequest) (*FollowUserResponse, error)
 	GetAvatarByNickname(context.Context, *GetAvatarByNicknameRequest) (*GetAvatarByNicknameResponse, error)
 	GetById(context.Context, *GetByIdRequest) (*GetByIdResponse, error)
+	GetByNickname(context.Context, *GetByNicknameRequest) (*GetByNicknameResponse, error)
+	ImportUsers(grpc.ClientStreamingServer[ImportUsersRequest, ImportUsersResponse]) error
 	List(context.Context, *ListUsersRequest) (*ListUsersResponse, error)
+	ListAuditEntries(context.Context, *ListAuditEntriesRequest) (*ListAuditEntriesResponse, error)
+	ListFollowers(context.Context, *ListFollowersRequest) (*ListFollowersResponse, error)
+	ListFollowing(context.Context, *ListFollowingRequest) (*ListFollowingResponse, error)
+	Purge(context.Context, *PurgeUserRequest) (*PurgeUserResponse, error)
+	Restore(context.Context, *RestoreUserRequest) (*RestoreUserResponse, error)
+	Search(context.Context, *SearchUsersRequest) (*SearchUsersResponse, error)
+	Unfollow(context.Context, *UnfollowUserRequest) (*UnfollowUserResponse, error)
 	Update(context.Context, *UpdateUserRequest) (*UpdateUserResponse, error)
 	UpdateImg(context.Context, *UpdateImgRequest) (*UpdateImgResponse, error)
+	UploadAvatar(grpc.ClientStreamingServer[UploadAvatarRequest, UploadAvatarResponse]) error
+	Watch(*WatchRequest, grpc.ServerStreamingServer[WatchResponse]) error
 	mustEmbedUnimplementedUsersServer()
 }
 
@@ -156,27 +375,72 @@ type UnimplementedUsersServer struct{}
 func (UnimplementedUsersServer) Add(context.Context, *AddUserRequest) (*AddUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Add not implemented")
 }
+func (UnimplementedUsersServer) ChangeNickname(context.Context, *ChangeNicknameRequest) (*ChangeNicknameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeNickname not implemented")
+}
 func (UnimplementedUsersServer) Delete(context.Context, *DeleteUserRequest) (*DeleteUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedUsersServer) ExportUsers(*ExportUsersRequest, grpc.ServerStreamingServer[ExportUsersResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportUsers not implemented")
+}
 func (UnimplementedUsersServer) Find(context.Context, *FindUsersRequest) (*FindUsersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Find not implemented")
 }
+func (UnimplementedUsersServer) FindNearbyUsers(context.Context, *FindNearbyUsersRequest) (*FindNearbyUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindNearbyUsers not implemented")
+}
+func (UnimplementedUsersServer) Follow(context.Context, *FollowUserRequest) (*FollowUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Follow not implemented")
+}
 func (UnimplementedUsersServer) GetAvatarByNickname(context.Context, *GetAvatarByNicknameRequest) (*GetAvatarByNicknameResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAvatarByNickname not implemented")
 }
 func (UnimplementedUsersServer) GetById(context.Context, *GetByIdRequest) (*GetByIdResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetById not implemented")
 }
+func (UnimplementedUsersServer) GetByNickname(context.Context, *GetByNicknameRequest) (*GetByNicknameResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetByNickname not implemented")
+}
+func (UnimplementedUsersServer) ImportUsers(grpc.ClientStreamingServer[ImportUsersRequest, ImportUsersResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ImportUsers not implemented")
+}
 func (UnimplementedUsersServer) List(context.Context, *ListUsersRequest) (*ListUsersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
 }
+func (UnimplementedUsersServer) ListAuditEntries(context.Context, *ListAuditEntriesRequest) (*ListAuditEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditEntries not implemented")
+}
+func (UnimplementedUsersServer) ListFollowers(context.Context, *ListFollowersRequest) (*ListFollowersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFollowers not implemented")
+}
+func (UnimplementedUsersServer) ListFollowing(context.Context, *ListFollowingRequest) (*ListFollowingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFollowing not implemented")
+}
+func (UnimplementedUsersServer) Purge(context.Context, *PurgeUserRequest) (*PurgeUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Purge not implemented")
+}
+func (UnimplementedUsersServer) Restore(context.Context, *RestoreUserRequest) (*RestoreUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Restore not implemented")
+}
+func (UnimplementedUsersServer) Search(context.Context, *SearchUsersRequest) (*SearchUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedUsersServer) Unfollow(context.Context, *UnfollowUserRequest) (*UnfollowUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Unfollow not implemented")
+}
 func (UnimplementedUsersServer) Update(context.Context, *UpdateUserRequest) (*UpdateUserResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
 }
 func (UnimplementedUsersServer) UpdateImg(context.Context, *UpdateImgRequest) (*UpdateImgResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateImg not implemented")
 }
+func (UnimplementedUsersServer) UploadAvatar(grpc.ClientStreamingServer[UploadAvatarRequest, UploadAvatarResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadAvatar not implemented")
+}
+func (UnimplementedUsersServer) Watch(*WatchRequest, grpc.ServerStreamingServer[WatchResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
 func (UnimplementedUsersServer) mustEmbedUnimplementedUsersServer() {}
 func (UnimplementedUsersServer) testEmbeddedByValue()               {}
 
@@ -216,6 +480,24 @@ func _Users_Add_Handler(srv interface{}, ctx context.Context, dec func(interface
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Users_ChangeNickname_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeNicknameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).ChangeNickname(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Users_ChangeNickname_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).ChangeNickname(ctx, req.(*ChangeNicknameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Users_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteUserRequest)
 	if err := dec(in); err != nil {
@@ -234,6 +516,17 @@ func _Users_Delete_Handler(srv interface{}, ctx context.Context, dec func(interf
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Users_ExportUsers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportUsersRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UsersServer).ExportUsers(m, &grpc.GenericServerStream[ExportUsersRequest, ExportUsersResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Users_ExportUsersServer = grpc.ServerStreamingServer[ExportUsersResponse]
+
 func _Users_Find_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(FindUsersRequest)
 	if err := dec(in); err != nil {
@@ -252,6 +545,42 @@ func _Users_Find_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Users_FindNearbyUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindNearbyUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).FindNearbyUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Users_FindNearbyUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).FindNearbyUsers(ctx, req.(*FindNearbyUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_Follow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FollowUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).Follow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Users_Follow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).Follow(ctx, req.(*FollowUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Users_GetAvatarByNickname_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetAvatarByNicknameRequest)
 	if err := dec(in); err != nil {
@@ -288,6 +617,31 @@ func _Users_GetById_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Users_GetByNickname_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetByNicknameRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).GetByNickname(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Users_GetByNickname_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).GetByNickname(ctx, req.(*GetByNicknameRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_ImportUsers_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(UsersServer).ImportUsers(&grpc.GenericServerStream[ImportUsersRequest, ImportUsersResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Users_ImportUsersServer = grpc.ClientStreamingServer[ImportUsersRequest, ImportUsersResponse]
+
 func _Users_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListUsersRequest)
 	if err := dec(in); err != nil {
@@ -306,80 +660,1215 @@ func _Users_List_Handler(srv interface{}, ctx context.Context, dec func(interfac
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Users_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateUserRequest)
+func _Users_ListAuditEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEntriesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UsersServer).Update(ctx, in)
+		return srv.(UsersServer).ListAuditEntries(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Users_Update_FullMethodName,
+		FullMethod: Users_ListAuditEntries_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UsersServer).Update(ctx, req.(*UpdateUserRequest))
+		return srv.(UsersServer).ListAuditEntries(ctx, req.(*ListAuditEntriesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Users_UpdateImg_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateImgRequest)
+func _Users_ListFollowers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFollowersRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(UsersServer).UpdateImg(ctx, in)
+		return srv.(UsersServer).ListFollowers(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: Users_UpdateImg_FullMethodName,
+		FullMethod: Users_ListFollowers_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UsersServer).UpdateImg(ctx, req.(*UpdateImgRequest))
+		return srv.(UsersServer).ListFollowers(ctx, req.(*ListFollowersRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-// Users_ServiceDesc is the grpc.ServiceDesc for Users service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var Users_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "users.Users",
-	HandlerType: (*UsersServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Add",
-			Handler:    _Users_Add_Handler,
-		},
-		{
-			MethodName: "Delete",
-			Handler:    _Users_Delete_Handler,
-		},
-		{
-			MethodName: "Find",
-			Handler:    _Users_Find_Handler,
-		},
-		{
-			MethodName: "GetAvatarByNickname",
-			Handler:    _Users_GetAvatarByNickname_Handler,
-		},
-		{
-			MethodName: "GetById",
-			Handler:    _Users_GetById_Handler,
-		},
-		{
-			MethodName: "List",
-			Handler:    _Users_List_Handler,
-		},
-		{
-			MethodName: "Update",
-			Handler:    _Users_Update_Handler,
-		},
-		{
-			MethodName: "UpdateImg",
-			Handler:    _Users_UpdateImg_Handler,
+func _Users_ListFollowing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFollowingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).ListFollowing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Users_ListFollowing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).ListFollowing(ctx, req.(*ListFollowingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_Purge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).Purge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Users_Purge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).Purge(ctx, req.(*PurgeUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_Restore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).Restore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Users_Restore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).Restore(ctx, req.(*RestoreUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Users_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).Search(ctx, req.(*SearchUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_Unfollow_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnfollowUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).Unfollow(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Users_Unfollow_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).Unfollow(ctx, req.(*UnfollowUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Users_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).Update(ctx, req.(*UpdateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_UpdateImg_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateImgRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersServer).UpdateImg(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Users_UpdateImg_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersServer).UpdateImg(ctx, req.(*UpdateImgRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Users_UploadAvatar_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(UsersServer).UploadAvatar(&grpc.GenericServerStream[UploadAvatarRequest, UploadAvatarResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Users_UploadAvatarServer = grpc.ClientStreamingServer[UploadAvatarRequest, UploadAvatarResponse]
+
+func _Users_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(UsersServer).Watch(m, &grpc.GenericServerStream[WatchRequest, WatchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Users_WatchServer = grpc.ServerStreamingServer[WatchResponse]
+
+// Users_ServiceDesc is the grpc.ServiceDesc for Users service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Users_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "users.Users",
+	HandlerType: (*UsersServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Add",
+			Handler:    _Users_Add_Handler,
+		},
+		{
+			MethodName: "ChangeNickname",
+			Handler:    _Users_ChangeNickname_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Users_Delete_Handler,
+		},
+		{
+			MethodName: "Find",
+			Handler:    _Users_Find_Handler,
+		},
+		{
+			MethodName: "FindNearbyUsers",
+			Handler:    _Users_FindNearbyUsers_Handler,
+		},
+		{
+			MethodName: "Follow",
+			Handler:    _Users_Follow_Handler,
+		},
+		{
+			MethodName: "GetAvatarByNickname",
+			Handler:    _Users_GetAvatarByNickname_Handler,
+		},
+		{
+			MethodName: "GetById",
+			Handler:    _Users_GetById_Handler,
+		},
+		{
+			MethodName: "GetByNickname",
+			Handler:    _Users_GetByNickname_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Users_List_Handler,
+		},
+		{
+			MethodName: "ListAuditEntries",
+			Handler:    _Users_ListAuditEntries_Handler,
+		},
+		{
+			MethodName: "ListFollowers",
+			Handler:    _Users_ListFollowers_Handler,
+		},
+		{
+			MethodName: "ListFollowing",
+			Handler:    _Users_ListFollowing_Handler,
+		},
+		{
+			MethodName: "Purge",
+			Handler:    _Users_Purge_Handler,
+		},
+		{
+			MethodName: "Restore",
+			Handler:    _Users_Restore_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _Users_Search_Handler,
+		},
+		{
+			MethodName: "Unfollow",
+			Handler:    _Users_Unfollow_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _Users_Update_Handler,
+		},
+		{
+			MethodName: "UpdateImg",
+			Handler:    _Users_UpdateImg_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ExportUsers",
+			Handler:       _Users_ExportUsers_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ImportUsers",
+			Handler:       _Users_ImportUsers_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "UploadAvatar",
+			Handler:       _Users_UploadAvatar_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _Users_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "users.proto",
+}
+
+const (
+	Clubs_AcceptRequest_FullMethodName    = "/users.Clubs/AcceptRequest"
+	Clubs_Add_FullMethodName              = "/users.Clubs/Add"
+	Clubs_Delete_FullMethodName           = "/users.Clubs/Delete"
+	Clubs_GetById_FullMethodName          = "/users.Clubs/GetById"
+	Clubs_Invite_FullMethodName           = "/users.Clubs/Invite"
+	Clubs_List_FullMethodName             = "/users.Clubs/List"
+	Clubs_ListClubMembers_FullMethodName  = "/users.Clubs/ListClubMembers"
+	Clubs_ListRequests_FullMethodName     = "/users.Clubs/ListRequests"
+	Clubs_ListUserRequests_FullMethodName = "/users.Clubs/ListUserRequests"
+	Clubs_RejectRequest_FullMethodName    = "/users.Clubs/RejectRequest"
+	Clubs_Rename_FullMethodName           = "/users.Clubs/Rename"
+	Clubs_RequestToJoin_FullMethodName    = "/users.Clubs/RequestToJoin"
+	Clubs_SetJoinPolicy_FullMethodName    = "/users.Clubs/SetJoinPolicy"
+	Clubs_SetMemberRole_FullMethodName    = "/users.Clubs/SetMemberRole"
+)
+
+// ClubsClient is the client API for Clubs service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ClubsClient interface {
+	AcceptRequest(ctx context.Context, in *AcceptClubRequestRequest, opts ...grpc.CallOption) (*AcceptClubRequestResponse, error)
+	Add(ctx context.Context, in *AddClubRequest, opts ...grpc.CallOption) (*AddClubResponse, error)
+	Delete(ctx context.Context, in *DeleteClubRequest, opts ...grpc.CallOption) (*DeleteClubResponse, error)
+	GetById(ctx context.Context, in *GetClubByIdRequest, opts ...grpc.CallOption) (*GetClubByIdResponse, error)
+	Invite(ctx context.Context, in *InviteToClubRequest, opts ...grpc.CallOption) (*InviteToClubResponse, error)
+	List(ctx context.Context, in *ListClubsRequest, opts ...grpc.CallOption) (*ListClubsResponse, error)
+	ListClubMembers(ctx context.Context, in *ListClubMembersRequest, opts ...grpc.CallOption) (*ListClubMembersResponse, error)
+	ListRequests(ctx context.Context, in *ListClubRequestsRequest, opts ...grpc.CallOption) (*ListClubRequestsResponse, error)
+	ListUserRequests(ctx context.Context, in *ListUserClubRequestsRequest, opts ...grpc.CallOption) (*ListUserClubRequestsResponse, error)
+	RejectRequest(ctx context.Context, in *RejectClubRequestRequest, opts ...grpc.CallOption) (*RejectClubRequestResponse, error)
+	Rename(ctx context.Context, in *RenameClubRequest, opts ...grpc.CallOption) (*RenameClubResponse, error)
+	RequestToJoin(ctx context.Context, in *RequestToJoinClubRequest, opts ...grpc.CallOption) (*RequestToJoinClubResponse, error)
+	SetJoinPolicy(ctx context.Context, in *SetClubJoinPolicyRequest, opts ...grpc.CallOption) (*SetClubJoinPolicyResponse, error)
+	SetMemberRole(ctx context.Context, in *SetClubMemberRoleRequest, opts ...grpc.CallOption) (*SetClubMemberRoleResponse, error)
+}
+
+type clubsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewClubsClient(cc grpc.ClientConnInterface) ClubsClient {
+	return &clubsClient{cc}
+}
+
+func (c *clubsClient) AcceptRequest(ctx context.Context, in *AcceptClubRequestRequest, opts ...grpc.CallOption) (*AcceptClubRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcceptClubRequestResponse)
+	err := c.cc.Invoke(ctx, Clubs_AcceptRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clubsClient) Add(ctx context.Context, in *AddClubRequest, opts ...grpc.CallOption) (*AddClubResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddClubResponse)
+	err := c.cc.Invoke(ctx, Clubs_Add_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clubsClient) Delete(ctx context.Context, in *DeleteClubRequest, opts ...grpc.CallOption) (*DeleteClubResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteClubResponse)
+	err := c.cc.Invoke(ctx, Clubs_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clubsClient) GetById(ctx context.Context, in *GetClubByIdRequest, opts ...grpc.CallOption) (*GetClubByIdResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetClubByIdResponse)
+	err := c.cc.Invoke(ctx, Clubs_GetById_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clubsClient) Invite(ctx context.Context, in *InviteToClubRequest, opts ...grpc.CallOption) (*InviteToClubResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InviteToClubResponse)
+	err := c.cc.Invoke(ctx, Clubs_Invite_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clubsClient) List(ctx context.Context, in *ListClubsRequest, opts ...grpc.CallOption) (*ListClubsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListClubsResponse)
+	err := c.cc.Invoke(ctx, Clubs_List_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clubsClient) ListClubMembers(ctx context.Context, in *ListClubMembersRequest, opts ...grpc.CallOption) (*ListClubMembersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListClubMembersResponse)
+	err := c.cc.Invoke(ctx, Clubs_ListClubMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clubsClient) ListRequests(ctx context.Context, in *ListClubRequestsRequest, opts ...grpc.CallOption) (*ListClubRequestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListClubRequestsResponse)
+	err := c.cc.Invoke(ctx, Clubs_ListRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clubsClient) ListUserRequests(ctx context.Context, in *ListUserClubRequestsRequest, opts ...grpc.CallOption) (*ListUserClubRequestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListUserClubRequestsResponse)
+	err := c.cc.Invoke(ctx, Clubs_ListUserRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clubsClient) RejectRequest(ctx context.Context, in *RejectClubRequestRequest, opts ...grpc.CallOption) (*RejectClubRequestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RejectClubRequestResponse)
+	err := c.cc.Invoke(ctx, Clubs_RejectRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clubsClient) Rename(ctx context.Context, in *RenameClubRequest, opts ...grpc.CallOption) (*RenameClubResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenameClubResponse)
+	err := c.cc.Invoke(ctx, Clubs_Rename_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clubsClient) RequestToJoin(ctx context.Context, in *RequestToJoinClubRequest, opts ...grpc.CallOption) (*RequestToJoinClubResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestToJoinClubResponse)
+	err := c.cc.Invoke(ctx, Clubs_RequestToJoin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clubsClient) SetJoinPolicy(ctx context.Context, in *SetClubJoinPolicyRequest, opts ...grpc.CallOption) (*SetClubJoinPolicyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetClubJoinPolicyResponse)
+	err := c.cc.Invoke(ctx, Clubs_SetJoinPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clubsClient) SetMemberRole(ctx context.Context, in *SetClubMemberRoleRequest, opts ...grpc.CallOption) (*SetClubMemberRoleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetClubMemberRoleResponse)
+	err := c.cc.Invoke(ctx, Clubs_SetMemberRole_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ClubsServer is the server API for Clubs service.
+// All implementations must embed UnimplementedClubsServer
+// for forward compatibility.
+type ClubsServer interface {
+	AcceptRequest(context.Context, *AcceptClubRequestRequest) (*AcceptClubRequestResponse, error)
+	Add(context.Context, *AddClubRequest) (*AddClubResponse, error)
+	Delete(context.Context, *DeleteClubRequest) (*DeleteClubResponse, error)
+	GetById(context.Context, *GetClubByIdRequest) (*GetClubByIdResponse, error)
+	Invite(context.Context, *InviteToClubRequest) (*InviteToClubResponse, error)
+	List(context.Context, *ListClubsRequest) (*ListClubsResponse, error)
+	ListClubMembers(context.Context, *ListClubMembersRequest) (*ListClubMembersResponse, error)
+	ListRequests(context.Context, *ListClubRequestsRequest) (*ListClubRequestsResponse, error)
+	ListUserRequests(context.Context, *ListUserClubRequestsRequest) (*ListUserClubRequestsResponse, error)
+	RejectRequest(context.Context, *RejectClubRequestRequest) (*RejectClubRequestResponse, error)
+	Rename(context.Context, *RenameClubRequest) (*RenameClubResponse, error)
+	RequestToJoin(context.Context, *RequestToJoinClubRequest) (*RequestToJoinClubResponse, error)
+	SetJoinPolicy(context.Context, *SetClubJoinPolicyRequest) (*SetClubJoinPolicyResponse, error)
+	SetMemberRole(context.Context, *SetClubMemberRoleRequest) (*SetClubMemberRoleResponse, error)
+	mustEmbedUnimplementedClubsServer()
+}
+
+// UnimplementedClubsServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedClubsServer struct{}
+
+func (UnimplementedClubsServer) AcceptRequest(context.Context, *AcceptClubRequestRequest) (*AcceptClubRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcceptRequest not implemented")
+}
+func (UnimplementedClubsServer) Add(context.Context, *AddClubRequest) (*AddClubResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Add not implemented")
+}
+func (UnimplementedClubsServer) Delete(context.Context, *DeleteClubRequest) (*DeleteClubResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedClubsServer) GetById(context.Context, *GetClubByIdRequest) (*GetClubByIdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetById not implemented")
+}
+func (UnimplementedClubsServer) Invite(context.Context, *InviteToClubRequest) (*InviteToClubResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Invite not implemented")
+}
+func (UnimplementedClubsServer) List(context.Context, *ListClubsRequest) (*ListClubsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedClubsServer) ListClubMembers(context.Context, *ListClubMembersRequest) (*ListClubMembersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListClubMembers not implemented")
+}
+func (UnimplementedClubsServer) ListRequests(context.Context, *ListClubRequestsRequest) (*ListClubRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRequests not implemented")
+}
+func (UnimplementedClubsServer) ListUserRequests(context.Context, *ListUserClubRequestsRequest) (*ListUserClubRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUserRequests not implemented")
+}
+func (UnimplementedClubsServer) RejectRequest(context.Context, *RejectClubRequestRequest) (*RejectClubRequestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectRequest not implemented")
+}
+func (UnimplementedClubsServer) Rename(context.Context, *RenameClubRequest) (*RenameClubResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Rename not implemented")
+}
+func (UnimplementedClubsServer) RequestToJoin(context.Context, *RequestToJoinClubRequest) (*RequestToJoinClubResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestToJoin not implemented")
+}
+func (UnimplementedClubsServer) SetJoinPolicy(context.Context, *SetClubJoinPolicyRequest) (*SetClubJoinPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetJoinPolicy not implemented")
+}
+func (UnimplementedClubsServer) SetMemberRole(context.Context, *SetClubMemberRoleRequest) (*SetClubMemberRoleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMemberRole not implemented")
+}
+func (UnimplementedClubsServer) mustEmbedUnimplementedClubsServer() {}
+func (UnimplementedClubsServer) testEmbeddedByValue()               {}
+
+// UnsafeClubsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ClubsServer will
+// result in compilation errors.
+type UnsafeClubsServer interface {
+	mustEmbedUnimplementedClubsServer()
+}
+
+func RegisterClubsServer(s grpc.ServiceRegistrar, srv ClubsServer) {
+	// If the following call pancis, it indicates UnimplementedClubsServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Clubs_ServiceDesc, srv)
+}
+
+func _Clubs_AcceptRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptClubRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClubsServer).AcceptRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clubs_AcceptRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClubsServer).AcceptRequest(ctx, req.(*AcceptClubRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clubs_Add_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddClubRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClubsServer).Add(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clubs_Add_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClubsServer).Add(ctx, req.(*AddClubRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clubs_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteClubRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClubsServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clubs_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClubsServer).Delete(ctx, req.(*DeleteClubRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clubs_GetById_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClubByIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClubsServer).GetById(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clubs_GetById_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClubsServer).GetById(ctx, req.(*GetClubByIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clubs_Invite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteToClubRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClubsServer).Invite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clubs_Invite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClubsServer).Invite(ctx, req.(*InviteToClubRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clubs_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListClubsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClubsServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clubs_List_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClubsServer).List(ctx, req.(*ListClubsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clubs_ListClubMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListClubMembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClubsServer).ListClubMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clubs_ListClubMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClubsServer).ListClubMembers(ctx, req.(*ListClubMembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clubs_ListRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListClubRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClubsServer).ListRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clubs_ListRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClubsServer).ListRequests(ctx, req.(*ListClubRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clubs_ListUserRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUserClubRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClubsServer).ListUserRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clubs_ListUserRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClubsServer).ListUserRequests(ctx, req.(*ListUserClubRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clubs_RejectRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectClubRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClubsServer).RejectRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clubs_RejectRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClubsServer).RejectRequest(ctx, req.(*RejectClubRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clubs_Rename_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameClubRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClubsServer).Rename(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clubs_Rename_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClubsServer).Rename(ctx, req.(*RenameClubRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clubs_RequestToJoin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestToJoinClubRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClubsServer).RequestToJoin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clubs_RequestToJoin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClubsServer).RequestToJoin(ctx, req.(*RequestToJoinClubRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clubs_SetJoinPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetClubJoinPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClubsServer).SetJoinPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clubs_SetJoinPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClubsServer).SetJoinPolicy(ctx, req.(*SetClubJoinPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Clubs_SetMemberRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetClubMemberRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClubsServer).SetMemberRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Clubs_SetMemberRole_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClubsServer).SetMemberRole(ctx, req.(*SetClubMemberRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Clubs_ServiceDesc is the grpc.ServiceDesc for Clubs service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Clubs_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "users.Clubs",
+	HandlerType: (*ClubsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AcceptRequest",
+			Handler:    _Clubs_AcceptRequest_Handler,
+		},
+		{
+			MethodName: "Add",
+			Handler:    _Clubs_Add_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Clubs_Delete_Handler,
+		},
+		{
+			MethodName: "GetById",
+			Handler:    _Clubs_GetById_Handler,
+		},
+		{
+			MethodName: "Invite",
+			Handler:    _Clubs_Invite_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Clubs_List_Handler,
+		},
+		{
+			MethodName: "ListClubMembers",
+			Handler:    _Clubs_ListClubMembers_Handler,
+		},
+		{
+			MethodName: "ListRequests",
+			Handler:    _Clubs_ListRequests_Handler,
+		},
+		{
+			MethodName: "ListUserRequests",
+			Handler:    _Clubs_ListUserRequests_Handler,
+		},
+		{
+			MethodName: "RejectRequest",
+			Handler:    _Clubs_RejectRequest_Handler,
+		},
+		{
+			MethodName: "Rename",
+			Handler:    _Clubs_Rename_Handler,
+		},
+		{
+			MethodName: "RequestToJoin",
+			Handler:    _Clubs_RequestToJoin_Handler,
+		},
+		{
+			MethodName: "SetJoinPolicy",
+			Handler:    _Clubs_SetJoinPolicy_Handler,
+		},
+		{
+			MethodName: "SetMemberRole",
+			Handler:    _Clubs_SetMemberRole_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "users.proto",
+}
+
+const (
+	Webhooks_Add_FullMethodName            = "/users.Webhooks/Add"
+	Webhooks_Delete_FullMethodName         = "/users.Webhooks/Delete"
+	Webhooks_GetById_FullMethodName        = "/users.Webhooks/GetById"
+	Webhooks_List_FullMethodName           = "/users.Webhooks/List"
+	Webhooks_ListDeliveries_FullMethodName = "/users.Webhooks/ListDeliveries"
+	Webhooks_Redeliver_FullMethodName      = "/users.Webhooks/Redeliver"
+	Webhooks_Update_FullMethodName         = "/users.Webhooks/Update"
+)
+
+// WebhooksClient is the client API for Webhooks service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type WebhooksClient interface {
+	Add(ctx context.Context, in *AddWebhookRequest, opts ...grpc.CallOption) (*AddWebhookResponse, error)
+	Delete(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error)
+	GetById(ctx context.Context, in *GetWebhookByIdRequest, opts ...grpc.CallOption) (*GetWebhookByIdResponse, error)
+	List(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error)
+	ListDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
+	Redeliver(ctx context.Context, in *RedeliverWebhookRequest, opts ...grpc.CallOption) (*RedeliverWebhookResponse, error)
+	Update(ctx context.Context, in *UpdateWebhookRequest, opts ...grpc.CallOption) (*UpdateWebhookResponse, error)
+}
+
+type webhooksClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWebhooksClient(cc grpc.ClientConnInterface) WebhooksClient {
+	return &webhooksClient{cc}
+}
+
+func (c *webhooksClient) Add(ctx context.Context, in *AddWebhookRequest, opts ...grpc.CallOption) (*AddWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddWebhookResponse)
+	err := c.cc.Invoke(ctx, Webhooks_Add_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhooksClient) Delete(ctx context.Context, in *DeleteWebhookRequest, opts ...grpc.CallOption) (*DeleteWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteWebhookResponse)
+	err := c.cc.Invoke(ctx, Webhooks_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhooksClient) GetById(ctx context.Context, in *GetWebhookByIdRequest, opts ...grpc.CallOption) (*GetWebhookByIdResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetWebhookByIdResponse)
+	err := c.cc.Invoke(ctx, Webhooks_GetById_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhooksClient) List(ctx context.Context, in *ListWebhooksRequest, opts ...grpc.CallOption) (*ListWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhooksResponse)
+	err := c.cc.Invoke(ctx, Webhooks_List_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhooksClient) ListDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, Webhooks_ListDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhooksClient) Redeliver(ctx context.Context, in *RedeliverWebhookRequest, opts ...grpc.CallOption) (*RedeliverWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RedeliverWebhookResponse)
+	err := c.cc.Invoke(ctx, Webhooks_Redeliver_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhooksClient) Update(ctx context.Context, in *UpdateWebhookRequest, opts ...grpc.CallOption) (*UpdateWebhookResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateWebhookResponse)
+	err := c.cc.Invoke(ctx, Webhooks_Update_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WebhooksServer is the server API for Webhooks service.
+// All implementations must embed UnimplementedWebhooksServer
+// for forward compatibility.
+type WebhooksServer interface {
+	Add(context.Context, *AddWebhookRequest) (*AddWebhookResponse, error)
+	Delete(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error)
+	GetById(context.Context, *GetWebhookByIdRequest) (*GetWebhookByIdResponse, error)
+	List(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error)
+	ListDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
+	Redeliver(context.Context, *RedeliverWebhookRequest) (*RedeliverWebhookResponse, error)
+	Update(context.Context, *UpdateWebhookRequest) (*UpdateWebhookResponse, error)
+	mustEmbedUnimplementedWebhooksServer()
+}
+
+// UnimplementedWebhooksServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWebhooksServer struct{}
+
+func (UnimplementedWebhooksServer) Add(context.Context, *AddWebhookRequest) (*AddWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Add not implemented")
+}
+func (UnimplementedWebhooksServer) Delete(context.Context, *DeleteWebhookRequest) (*DeleteWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedWebhooksServer) GetById(context.Context, *GetWebhookByIdRequest) (*GetWebhookByIdResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetById not implemented")
+}
+func (UnimplementedWebhooksServer) List(context.Context, *ListWebhooksRequest) (*ListWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedWebhooksServer) ListDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeliveries not implemented")
+}
+func (UnimplementedWebhooksServer) Redeliver(context.Context, *RedeliverWebhookRequest) (*RedeliverWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Redeliver not implemented")
+}
+func (UnimplementedWebhooksServer) Update(context.Context, *UpdateWebhookRequest) (*UpdateWebhookResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (UnimplementedWebhooksServer) mustEmbedUnimplementedWebhooksServer() {}
+func (UnimplementedWebhooksServer) testEmbeddedByValue()                  {}
+
+// UnsafeWebhooksServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WebhooksServer will
+// result in compilation errors.
+type UnsafeWebhooksServer interface {
+	mustEmbedUnimplementedWebhooksServer()
+}
+
+func RegisterWebhooksServer(s grpc.ServiceRegistrar, srv WebhooksServer) {
+	// If the following call pancis, it indicates UnimplementedWebhooksServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Webhooks_ServiceDesc, srv)
+}
+
+func _Webhooks_Add_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhooksServer).Add(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Webhooks_Add_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhooksServer).Add(ctx, req.(*AddWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Webhooks_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhooksServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Webhooks_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhooksServer).Delete(ctx, req.(*DeleteWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Webhooks_GetById_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWebhookByIdRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhooksServer).GetById(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Webhooks_GetById_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhooksServer).GetById(ctx, req.(*GetWebhookByIdRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Webhooks_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhooksServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Webhooks_List_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhooksServer).List(ctx, req.(*ListWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Webhooks_ListDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhooksServer).ListDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Webhooks_ListDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhooksServer).ListDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Webhooks_Redeliver_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedeliverWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhooksServer).Redeliver(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Webhooks_Redeliver_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhooksServer).Redeliver(ctx, req.(*RedeliverWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Webhooks_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateWebhookRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhooksServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Webhooks_Update_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhooksServer).Update(ctx, req.(*UpdateWebhookRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Webhooks_ServiceDesc is the grpc.ServiceDesc for Webhooks service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Webhooks_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "users.Webhooks",
+	HandlerType: (*WebhooksServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Add",
+			Handler:    _Webhooks_Add_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Webhooks_Delete_Handler,
+		},
+		{
+			MethodName: "GetById",
+			Handler:    _Webhooks_GetById_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Webhooks_List_Handler,
+		},
+		{
+			MethodName: "ListDeliveries",
+			Handler:    _Webhooks_ListDeliveries_Handler,
+		},
+		{
+			MethodName: "Redeliver",
+			Handler:    _Webhooks_Redeliver_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _Webhooks_Update_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"strings"
+
+	model "github.com/demkowo/users/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// auditActorHeader names the caller on whose behalf a request is made.
+const auditActorHeader = "X-Actor"
+
+// AuditActor is a middleware that puts the caller of the request and its
+// client IP into its context, so changes made by the request are attributed to
+// them.
+func AuditActor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		addr := c.ClientIP()
+		id := strings.TrimSpace(c.GetHeader(auditActorHeader))
+		if id == "" {
+			id = addr
+		}
+
+		ctx := model.WithAuditActor(c.Request.Context(), model.NewAuditActor(id, addr, model.AuditTransportGin))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
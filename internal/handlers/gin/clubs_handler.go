@@ -0,0 +1,393 @@
+package handler
+
+import (
+	"net/http"
+
+	model "github.com/demkowo/users/internal/models"
+	service "github.com/demkowo/users/internal/services"
+	"github.com/demkowo/utils/resp"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+type Clubs interface {
+	AcceptRequest(*gin.Context)
+	Add(*gin.Context)
+	Delete(*gin.Context)
+	GetById(*gin.Context)
+	Invite(*gin.Context)
+	List(*gin.Context)
+	ListMembers(*gin.Context)
+	ListRequests(*gin.Context)
+	ListUserRequests(*gin.Context)
+	RejectRequest(*gin.Context)
+	Rename(*gin.Context)
+	RequestToJoin(*gin.Context)
+	SetJoinPolicy(*gin.Context)
+	SetMemberRole(*gin.Context)
+}
+
+type clubs struct {
+	service service.Clubs
+}
+
+func NewClubs(service service.Clubs) Clubs {
+	log.Trace()
+	return &clubs{
+		service: service,
+	}
+}
+
+func (h *clubs) AcceptRequest(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var clubID, requestID uuid.UUID
+	if !help.ParseUUID(c, "club_id", c.Param("club_id"), &clubID) {
+		return
+	}
+	if !help.ParseUUID(c, "request_id", c.Param("request_id"), &requestID) {
+		return
+	}
+
+	request, err := h.service.AcceptRequest(ctx, clubID, requestID)
+	if err != nil {
+		log.Errorf("Failed to accept club request: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "club request accepted successfully", []interface{}{request}).JSON())
+}
+
+func (h *clubs) Add(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+	var input struct {
+		Name       string `json:"name" binding:"required"`
+		JoinPolicy string `json:"join_policy"`
+	}
+
+	if !help.BindJSON(c, &input) {
+		return
+	}
+
+	club := &model.Club{
+		ID:         uuid.New(),
+		Name:       input.Name,
+		JoinPolicy: input.JoinPolicy,
+	}
+
+	if err := h.service.Add(ctx, club); err != nil {
+		log.Errorf("Failed to create club: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "club added successfully", []interface{}{club}).JSON())
+}
+
+func (h *clubs) Delete(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "club_id", c.Param("club_id"), &id) {
+		return
+	}
+
+	if err := h.service.Delete(ctx, id); err != nil {
+		log.Errorf("Failed to delete club: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "club deleted successfully", nil).JSON())
+}
+
+func (h *clubs) GetById(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "club_id", c.Param("club_id"), &id) {
+		return
+	}
+
+	club, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		log.Error(err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "club fetched successfully", []interface{}{club}).JSON())
+}
+
+func (h *clubs) Invite(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var clubID uuid.UUID
+	if !help.ParseUUID(c, "club_id", c.Param("club_id"), &clubID) {
+		return
+	}
+
+	var input struct {
+		UserID string `json:"user_id" binding:"required"`
+	}
+
+	if !help.BindJSON(c, &input) {
+		return
+	}
+
+	var userID uuid.UUID
+	if !help.ParseUUID(c, "user_id", input.UserID, &userID) {
+		return
+	}
+
+	request, err := h.service.Invite(ctx, clubID, userID)
+	if err != nil {
+		log.Errorf("Failed to invite user: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "user invited successfully", []interface{}{request}).JSON())
+}
+
+func (h *clubs) List(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+	limit, offset := parsePagination(c)
+
+	clubs, total, err := h.service.List(ctx, limit, offset)
+	if err != nil {
+		log.Error(err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "clubs fetched successfully", []interface{}{gin.H{
+		"clubs": clubs,
+		"total": total,
+	}}).JSON())
+}
+
+func (h *clubs) ListMembers(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "club_id", c.Param("club_id"), &id) {
+		return
+	}
+
+	limit, offset := parsePagination(c)
+
+	members, total, err := h.service.ListMembers(ctx, id, limit, offset)
+	if err != nil {
+		log.Error(err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "club members fetched successfully", []interface{}{gin.H{
+		"users": members,
+		"total": total,
+	}}).JSON())
+}
+
+func (h *clubs) ListRequests(c *gin.Context) {
+	log.Trace()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "club_id", c.Param("club_id"), &id) {
+		return
+	}
+
+	h.listRequests(c, model.ClubRequestFilter{ClubID: id})
+}
+
+func (h *clubs) ListUserRequests(c *gin.Context) {
+	log.Trace()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "user_id", c.Param("user_id"), &id) {
+		return
+	}
+
+	h.listRequests(c, model.ClubRequestFilter{UserID: id})
+}
+
+func (h *clubs) RejectRequest(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var clubID, requestID uuid.UUID
+	if !help.ParseUUID(c, "club_id", c.Param("club_id"), &clubID) {
+		return
+	}
+	if !help.ParseUUID(c, "request_id", c.Param("request_id"), &requestID) {
+		return
+	}
+
+	request, err := h.service.RejectRequest(ctx, clubID, requestID)
+	if err != nil {
+		log.Errorf("Failed to reject club request: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "club request rejected successfully", []interface{}{request}).JSON())
+}
+
+func (h *clubs) Rename(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "club_id", c.Param("club_id"), &id) {
+		return
+	}
+
+	var input struct {
+		Name string `json:"name" binding:"required"`
+	}
+
+	if !help.BindJSON(c, &input) {
+		return
+	}
+
+	club, err := h.service.Rename(ctx, id, input.Name)
+	if err != nil {
+		log.Errorf("Failed to rename club: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "club renamed successfully", []interface{}{club}).JSON())
+}
+
+func (h *clubs) RequestToJoin(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var clubID uuid.UUID
+	if !help.ParseUUID(c, "club_id", c.Param("club_id"), &clubID) {
+		return
+	}
+
+	var input struct {
+		UserID string `json:"user_id" binding:"required"`
+	}
+
+	if !help.BindJSON(c, &input) {
+		return
+	}
+
+	var userID uuid.UUID
+	if !help.ParseUUID(c, "user_id", input.UserID, &userID) {
+		return
+	}
+
+	request, err := h.service.RequestToJoin(ctx, clubID, userID)
+	if err != nil {
+		log.Errorf("Failed to request to join club: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "join request sent successfully", []interface{}{request}).JSON())
+}
+
+func (h *clubs) SetJoinPolicy(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "club_id", c.Param("club_id"), &id) {
+		return
+	}
+
+	var input struct {
+		JoinPolicy string `json:"join_policy" binding:"required"`
+	}
+
+	if !help.BindJSON(c, &input) {
+		return
+	}
+
+	club, err := h.service.SetJoinPolicy(ctx, id, input.JoinPolicy)
+	if err != nil {
+		log.Errorf("Failed to change club join policy: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "club join policy changed successfully", []interface{}{club}).JSON())
+}
+
+func (h *clubs) SetMemberRole(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var clubID, userID uuid.UUID
+	if !help.ParseUUID(c, "club_id", c.Param("club_id"), &clubID) {
+		return
+	}
+	if !help.ParseUUID(c, "user_id", c.Param("user_id"), &userID) {
+		return
+	}
+
+	var input struct {
+		Role string `json:"role" binding:"required"`
+	}
+
+	if !help.BindJSON(c, &input) {
+		return
+	}
+
+	member, err := h.service.SetMemberRole(ctx, clubID, userID, input.Role)
+	if err != nil {
+		log.Errorf("Failed to change member role: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "member role changed successfully", []interface{}{member}).JSON())
+}
+
+// listRequests narrows filter down by the kind and status query parameters.
+func (h *clubs) listRequests(c *gin.Context, filter model.ClubRequestFilter) {
+	ctx := c.Request.Context()
+	limit, offset := parsePagination(c)
+
+	filter.Kind = c.Query("kind")
+	filter.Status = c.Query("status")
+
+	requests, total, err := h.service.ListRequests(ctx, filter, limit, offset)
+	if err != nil {
+		log.Error(err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "club requests fetched successfully", []interface{}{gin.H{
+		"requests": requests,
+		"total":    total,
+	}}).JSON())
+}
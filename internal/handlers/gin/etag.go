@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/demkowo/utils/resp"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// setETag exposes the user version as a strong ETag.
+func setETag(c *gin.Context, version int32) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(int(version))))
+}
+
+// parseIfMatch returns the strong version ETags of If-Match, or nil for none or "*".
+func parseIfMatch(c *gin.Context) ([]int32, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil, true
+	}
+
+	versions := []int32{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		weak := strings.HasPrefix(tag, "W/")
+		tag = strings.TrimPrefix(tag, "W/")
+		if unquoted, err := strconv.Unquote(tag); err == nil {
+			tag = unquoted
+		}
+
+		version, err := strconv.ParseInt(tag, 10, 32)
+		if err != nil || version <= 0 {
+			c.JSON(resp.Error(http.StatusBadRequest, "invalid If-Match header", []interface{}{"If-Match must be an ETag returned by the API"}).JSON())
+			return nil, false
+		}
+		if !weak && !slices.Contains(versions, int32(version)) {
+			versions = append(versions, int32(version))
+		}
+	}
+
+	return versions, true
+}
+
+// ifMatchVersion returns the version If-Match makes the update conditional on, or 0.
+func (h *users) ifMatchVersion(c *gin.Context, id uuid.UUID) (int32, bool) {
+	versions, ok := parseIfMatch(c)
+	if !ok {
+		return 0, false
+	}
+
+	switch {
+	case versions == nil:
+		return 0, true
+	case len(versions) == 1:
+		return versions[0], true
+	case len(versions) > 1:
+		user, err := h.service.GetByID(c.Request.Context(), id)
+		if err != nil {
+			c.JSON(err.JSON())
+			return 0, false
+		}
+		if slices.Contains(versions, user.Version) {
+			return user.Version, true
+		}
+	}
+
+	c.JSON(resp.Error(http.StatusPreconditionFailed, "failed to update user", []interface{}{"If-Match matches no version of the user"}).JSON())
+	return 0, false
+}
+
+// preconditionError turns the version conflict of a conditional update into a 412.
+func preconditionError(err *resp.Err, version int32) *resp.Err {
+	if version == 0 || err.Code != http.StatusConflict {
+		return err
+	}
+	return resp.Error(http.StatusPreconditionFailed, err.Error, err.Causes)
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	model "github.com/demkowo/users/internal/models"
+	service "github.com/demkowo/users/internal/services"
+	"github.com/demkowo/utils/resp"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestSetETag(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	setETag(c, 42)
+
+	if got := w.Header().Get("ETag"); got != `"42"` {
+		t.Errorf("ETag = %s, want \"42\"", got)
+	}
+}
+
+func TestParseIfMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   []int32
+		wantOK bool
+	}{
+		{name: "missing", header: "", want: nil, wantOK: true},
+		{name: "any", header: "*", want: nil, wantOK: true},
+		{name: "any with spaces", header: " * ", want: nil, wantOK: true},
+		{name: "strong", header: `"3"`, want: []int32{3}, wantOK: true},
+		{name: "strong with spaces", header: ` "3" `, want: []int32{3}, wantOK: true},
+		{name: "unquoted", header: `3`, want: []int32{3}, wantOK: true},
+		{name: "weak", header: `W/"3"`, want: []int32{}, wantOK: true},
+		{name: "list", header: `"3", "5"`, want: []int32{3, 5}, wantOK: true},
+		{name: "list without spaces", header: `"3","5"`, want: []int32{3, 5}, wantOK: true},
+		{name: "list with duplicates", header: `"3", "3"`, want: []int32{3}, wantOK: true},
+		{name: "list with a weak tag", header: `W/"2", "3"`, want: []int32{3}, wantOK: true},
+		{name: "list of weak tags", header: `W/"2", W/"3"`, want: []int32{}, wantOK: true},
+		{name: "not a version", header: `"abc"`, wantOK: false},
+		{name: "zero", header: `"0"`, wantOK: false},
+		{name: "negative", header: `"-1"`, wantOK: false},
+		{name: "too large", header: `"99999999999"`, wantOK: false},
+		{name: "empty tag", header: `""`, wantOK: false},
+		{name: "trailing comma", header: `"3",`, wantOK: false},
+		{name: "any in a list", header: `*, "3"`, wantOK: false},
+		{name: "list with a bad tag", header: `"3", "abc"`, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+			c.Request.Header.Set("If-Match", tt.header)
+
+			got, ok := parseIfMatch(c)
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %t, want %t", ok, tt.wantOK)
+			}
+			if !ok {
+				if w.Code != http.StatusBadRequest {
+					t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("versions = %#v, want %#v", got, tt.want)
+			}
+			if w.Body.Len() != 0 {
+				t.Errorf("response written: %s", w.Body)
+			}
+		})
+	}
+}
+
+// fakeUsersService keeps a single user and updates it like the users service
+// does, bumping its version and refusing stale expected versions. Calling any
+// other method panics on the nil embedded interface.
+type fakeUsersService struct {
+	service.Users
+
+	user model.User
+	// conflict makes every update fail with a conflict, whatever the version.
+	conflict bool
+	gets     int
+	updates  int
+}
+
+func (s *fakeUsersService) GetByID(ctx context.Context, id uuid.UUID) (*model.User, *resp.Err) {
+	s.gets++
+	if id != s.user.ID {
+		return nil, resp.Error(http.StatusNotFound, "failed to get user", []interface{}{"user not found"})
+	}
+	u := s.user
+	return &u, nil
+}
+
+func (s *fakeUsersService) Update(ctx context.Context, user *model.User) *resp.Err {
+	if err := s.update(user.Version); err != nil {
+		return err
+	}
+	s.user.Country = user.Country
+	*user = s.user
+	return nil
+}
+
+func (s *fakeUsersService) Patch(ctx context.Context, user *model.User, fields []string) *resp.Err {
+	if err := s.update(user.Version); err != nil {
+		return err
+	}
+	s.user.Country = user.Country
+	*user = s.user
+	return nil
+}
+
+func (s *fakeUsersService) update(version int32) *resp.Err {
+	if s.conflict || version != 0 && version != s.user.Version {
+		return resp.Error(http.StatusConflict, "failed to update user", []interface{}{
+			fmt.Sprintf("user has been modified concurrently, current version is %d", s.user.Version),
+		})
+	}
+	s.updates++
+	s.user.Version++
+	return nil
+}
+
+func TestConditionalUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		ifMatch  string
+		conflict bool
+
+		wantCode int
+		wantETag string
+		wantGets int
+	}{
+		{name: "unconditional", wantCode: http.StatusOK, wantETag: `"4"`},
+		{name: "any version", ifMatch: "*", wantCode: http.StatusOK, wantETag: `"4"`},
+		{name: "current version", ifMatch: `"3"`, wantCode: http.StatusOK, wantETag: `"4"`},
+		{name: "stale version", ifMatch: `"2"`, wantCode: http.StatusPreconditionFailed},
+		{name: "newer version", ifMatch: `"7"`, wantCode: http.StatusPreconditionFailed},
+		{name: "weak current version", ifMatch: `W/"3"`, wantCode: http.StatusPreconditionFailed},
+		{name: "list with the current version", ifMatch: `"2", "3"`, wantCode: http.StatusOK, wantETag: `"4"`, wantGets: 1},
+		{name: "list without the current version", ifMatch: `"1", "2"`, wantCode: http.StatusPreconditionFailed, wantGets: 1},
+		{name: "list with a single strong tag", ifMatch: `W/"2", "3"`, wantCode: http.StatusOK, wantETag: `"4"`},
+		{name: "malformed", ifMatch: `"three"`, wantCode: http.StatusBadRequest},
+		{name: "unconditional conflict", conflict: true, wantCode: http.StatusConflict},
+		{name: "conditional conflict", ifMatch: `"3"`, conflict: true, wantCode: http.StatusPreconditionFailed},
+	}
+
+	for _, method := range []string{http.MethodPut, http.MethodPatch} {
+		for _, tt := range tests {
+			t.Run(method+" "+tt.name, func(t *testing.T) {
+				id := uuid.New()
+				svc := &fakeUsersService{user: model.User{ID: id, Nickname: "john", Version: 3}, conflict: tt.conflict}
+				h := NewUser(svc)
+				r := gin.New()
+				r.PUT("/users/:user_id", h.Update)
+				r.PATCH("/users/:user_id", h.Patch)
+
+				req := httptest.NewRequest(method, "/users/"+id.String(), strings.NewReader(`{"country":"PL"}`))
+				req.Header.Set("Content-Type", "application/json")
+				if tt.ifMatch != "" {
+					req.Header.Set("If-Match", tt.ifMatch)
+				}
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+
+				if w.Code != tt.wantCode {
+					t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantCode, w.Body)
+				}
+				if got := w.Header().Get("ETag"); got != tt.wantETag {
+					t.Errorf("ETag = %q, want %q", got, tt.wantETag)
+				}
+				if svc.gets != tt.wantGets {
+					t.Errorf("user fetched %d times, want %d", svc.gets, tt.wantGets)
+				}
+
+				wantUpdates, wantVersion := 0, int32(3)
+				if tt.wantCode == http.StatusOK {
+					wantUpdates, wantVersion = 1, 4
+				}
+				if svc.updates != wantUpdates || svc.user.Version != wantVersion {
+					t.Errorf("%d updates to version %d, want %d to version %d", svc.updates, svc.user.Version, wantUpdates, wantVersion)
+				}
+			})
+		}
+	}
+}
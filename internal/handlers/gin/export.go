@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	model "github.com/demkowo/users/internal/models"
+)
+
+// exportFlushRows is how many exported rows are buffered between flushes.
+const exportFlushRows = 100
+
+var csvExportHeader = []string{"id", "nickname", "img", "country", "city", "clubs", "created", "updated", "version"}
+
+type userEncoder interface {
+	Encode(model.User) error
+	Flush() error
+}
+
+// newUserEncoder returns the encoder and content type of format, or false.
+func newUserEncoder(format string, w io.Writer) (userEncoder, string, bool) {
+	switch format {
+	case formatCSV:
+		return &csvUserEncoder{w: csv.NewWriter(w)}, "text/csv; charset=utf-8", true
+	case formatNDJSON:
+		return &ndjsonUserEncoder{enc: json.NewEncoder(w)}, "application/x-ndjson", true
+	}
+
+	return nil, "", false
+}
+
+type csvUserEncoder struct {
+	w      *csv.Writer
+	header bool
+}
+
+func (e *csvUserEncoder) Encode(u model.User) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	clubs := make([]string, 0, len(u.Clubs))
+	for _, c := range u.Clubs {
+		clubs = append(clubs, c.Name)
+	}
+
+	return e.w.Write([]string{
+		u.ID.String(),
+		u.Nickname,
+		u.Img,
+		u.Country,
+		u.City,
+		strings.Join(clubs, csvClubsSeparator),
+		u.Created.Format(time.RFC3339),
+		u.Updated.Format(time.RFC3339),
+		strconv.Itoa(int(u.Version)),
+	})
+}
+
+// Flush writes out the buffered rows, and the header of an empty export.
+func (e *csvUserEncoder) Flush() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvUserEncoder) writeHeader() error {
+	if e.header {
+		return nil
+	}
+
+	e.header = true
+	return e.w.Write(csvExportHeader)
+}
+
+type ndjsonUserEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonUserEncoder) Encode(u model.User) error {
+	return e.enc.Encode(u)
+}
+
+func (e *ndjsonUserEncoder) Flush() error {
+	return nil
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	model "github.com/demkowo/users/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+
+	// csvClubsSeparator separates club names within the CSV "clubs" column, both
+	// on import and on export.
+	csvClubsSeparator = ";"
+)
+
+// importFormat is the format query parameter, or else the request Content-Type.
+func importFormat(c *gin.Context) string {
+	if format := c.Query("format"); format != "" {
+		return strings.ToLower(format)
+	}
+
+	switch c.ContentType() {
+	case "text/csv":
+		return formatCSV
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return formatNDJSON
+	}
+
+	return ""
+}
+
+// readCSVUsers reads users from CSV whose header names the columns.
+func readCSVUsers(r io.Reader) ([]model.User, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv header is missing")
+		}
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// spreadsheet exports often start with a UTF-8 BOM
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["nickname"]; !ok {
+		return nil, errors.New(`csv header must contain a "nickname" column`)
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok {
+			return record[i]
+		}
+		return ""
+	}
+
+	var users []model.User
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var clubs []string
+		if value := field(record, "clubs"); value != "" {
+			clubs = strings.Split(value, csvClubsSeparator)
+		}
+
+		users = append(users, newImportUser(
+			field(record, "nickname"),
+			field(record, "img"),
+			field(record, "country"),
+			field(record, "city"),
+			clubs,
+		))
+	}
+
+	return users, nil
+}
+
+// readNDJSONUsers reads users from newline-delimited JSON, skipping blank lines.
+func readNDJSONUsers(r io.Reader) ([]model.User, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var users []model.User
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var input struct {
+			Nickname string   `json:"nickname"`
+			Img      string   `json:"img"`
+			Country  string   `json:"country"`
+			City     string   `json:"city"`
+			Clubs    []string `json:"clubs"`
+		}
+		if err := json.Unmarshal([]byte(text), &input); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		users = append(users, newImportUser(input.Nickname, input.Img, input.Country, input.City, input.Clubs))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func newImportUser(nickname, img, country, city string, clubNames []string) model.User {
+	var clubs []model.Club
+	for _, name := range clubNames {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		clubs = append(clubs, model.Club{
+			ID:   uuid.New(),
+			Name: name,
+		})
+	}
+
+	return model.User{
+		ID:       uuid.New(),
+		Nickname: nickname,
+		Img:      strings.TrimSpace(img),
+		Country:  country,
+		City:     city,
+		Clubs:    clubs,
+	}
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	model "github.com/demkowo/users/internal/models"
+)
+
+// importRow is the part of an imported user the readers are responsible for.
+type importRow struct {
+	Nickname, Img, Country, City string
+	Clubs                        []string
+}
+
+func importRows(us []model.User) []importRow {
+	rows := make([]importRow, len(us))
+	for i, u := range us {
+		rows[i] = importRow{Nickname: u.Nickname, Img: u.Img, Country: u.Country, City: u.City}
+		for _, c := range u.Clubs {
+			rows[i].Clubs = append(rows[i].Clubs, c.Name)
+		}
+	}
+	return rows
+}
+
+func TestReadCSVUsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []importRow
+		wantErr string
+	}{
+		{
+			name:  "all columns",
+			input: "nickname,img,country,city,clubs\njohn,https://example.com/a.png,PL,Warsaw,Chess;Go\n",
+			want:  []importRow{{Nickname: "john", Img: "https://example.com/a.png", Country: "PL", City: "Warsaw", Clubs: []string{"Chess", "Go"}}},
+		},
+		{
+			name:  "BOM and mixed case header",
+			input: "\ufeffNickname, City\njohn,Warsaw\n",
+			want:  []importRow{{Nickname: "john", City: "Warsaw"}},
+		},
+		{
+			name:  "columns in any order",
+			input: "city,nickname\nWarsaw,john\nKrakow,anna\n",
+			want:  []importRow{{Nickname: "john", City: "Warsaw"}, {Nickname: "anna", City: "Krakow"}},
+		},
+		{
+			name:  "blank club names are dropped",
+			input: "nickname,clubs\njohn,Chess; ;;Go\n",
+			want:  []importRow{{Nickname: "john", Clubs: []string{"Chess", "Go"}}},
+		},
+		{
+			name:  "duplicate rows are kept for the service to report",
+			input: "nickname\njohn\njohn\n",
+			want:  []importRow{{Nickname: "john"}, {Nickname: "john"}},
+		},
+		{
+			name:  "header only",
+			input: "nickname\n",
+			want:  []importRow{},
+		},
+		{
+			name:    "empty input",
+			input:   "",
+			wantErr: "csv header is missing",
+		},
+		{
+			name:    "missing nickname column",
+			input:   "name,city\njohn,Warsaw\n",
+			wantErr: `csv header must contain a "nickname" column`,
+		},
+		{
+			name:    "row with too many fields",
+			input:   "nickname,city\njohn,Warsaw,extra\n",
+			wantErr: "wrong number of fields",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			us, err := readCSVUsers(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := importRows(us); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("users = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadNDJSONUsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []importRow
+		wantErr string
+	}{
+		{
+			name:  "all fields",
+			input: `{"nickname":"john","img":" https://example.com/a.png ","country":"PL","city":"Warsaw","clubs":["Chess"," Go "]}` + "\n",
+			want:  []importRow{{Nickname: "john", Img: "https://example.com/a.png", Country: "PL", City: "Warsaw", Clubs: []string{"Chess", "Go"}}},
+		},
+		{
+			name:  "blank lines are skipped",
+			input: "\n{\"nickname\":\"john\"}\n   \n{\"nickname\":\"anna\"}",
+			want:  []importRow{{Nickname: "john"}, {Nickname: "anna"}},
+		},
+		{
+			name:  "unknown fields are ignored",
+			input: `{"nickname":"john","age":30}`,
+			want:  []importRow{{Nickname: "john"}},
+		},
+		{
+			name:  "empty input",
+			input: "",
+			want:  nil,
+		},
+		{
+			name:    "malformed line",
+			input:   "{\"nickname\":\"john\"}\n{\"nickname\":\n",
+			wantErr: "line 2:",
+		},
+		{
+			name:    "wrong field type",
+			input:   `{"nickname":"john","clubs":"Chess"}`,
+			wantErr: "line 1:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			us, err := readNDJSONUsers(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []importRow
+			if us != nil {
+				got = importRows(us)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("users = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
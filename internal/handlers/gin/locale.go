@@ -0,0 +1,19 @@
+package handler
+
+import (
+	model "github.com/demkowo/users/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Locale is a middleware that puts the language asked for in the
+// Accept-Language header into the context of the request, so country names
+// are returned in it. Responses vary by that header, which caches must know.
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := model.ParseLocale(c.GetHeader("Accept-Language"))
+
+		c.Writer.Header().Add("Vary", "Accept-Language")
+		c.Request = c.Request.WithContext(model.WithLocale(c.Request.Context(), locale))
+		c.Next()
+	}
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func parsePagination(c *gin.Context) (limit, offset int32) {
+	limit = 10
+
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = int32(l)
+	}
+
+	if o, err := strconv.Atoi(c.Query("offset")); err == nil && o >= 0 {
+		offset = int32(o)
+	}
+
+	return limit, offset
+}
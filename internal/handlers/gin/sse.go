@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	model "github.com/demkowo/users/internal/models"
+)
+
+// sseHeartbeat keeps proxies from timing idle event streams out.
+const sseHeartbeat = 15 * time.Second
+
+func writeSSEEvent(w io.Writer, event model.UserEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+func writeSSEHeartbeat(w io.Writer) error {
+	_, err := io.WriteString(w, ": heartbeat\n\n")
+	return err
+}
@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	model "github.com/demkowo/users/internal/models"
 	service "github.com/demkowo/users/internal/services"
@@ -17,15 +20,35 @@ var (
 	help = helper.NewHelper()
 )
 
+// avatarFormField is the multipart field carrying an uploaded avatar.
+const avatarFormField = "avatar"
+
 type Users interface {
 	Add(*gin.Context)
+	ChangeNickname(*gin.Context)
 	Delete(*gin.Context)
+	Events(*gin.Context)
+	Export(*gin.Context)
 	Find(*gin.Context)
+	FindNearby(*gin.Context)
+	Follow(*gin.Context)
 	GetAvatarByNickname(*gin.Context)
 	GetById(*gin.Context)
+	GetByNickname(*gin.Context)
+	Import(*gin.Context)
 	List(*gin.Context)
+	ListAudit(*gin.Context)
+	ListFollowers(*gin.Context)
+	ListFollowing(*gin.Context)
+	Patch(*gin.Context)
+	Purge(*gin.Context)
+	Restore(*gin.Context)
+	Search(*gin.Context)
+	ServeAvatar(*gin.Context)
+	Unfollow(*gin.Context)
 	Update(*gin.Context)
 	UpdateImg(*gin.Context)
+	UploadAvatar(*gin.Context)
 }
 
 type users struct {
@@ -81,6 +104,34 @@ func (h *users) Add(c *gin.Context) {
 	c.JSON(resp.New(http.StatusOK, "user added succesfully", []interface{}{user}).JSON())
 }
 
+func (h *users) ChangeNickname(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "user_id", c.Param("user_id"), &id) {
+		return
+	}
+
+	var input struct {
+		Nickname string `json:"nickname" binding:"required"`
+	}
+
+	if !help.BindJSON(c, &input) {
+		return
+	}
+
+	user, err := h.service.ChangeNickname(ctx, id, input.Nickname)
+	if err != nil {
+		log.Errorf("Failed to change nickname: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "nickname changed successfully", []interface{}{user}).JSON())
+}
+
 func (h *users) Delete(c *gin.Context) {
 	log.Trace()
 
@@ -95,6 +146,115 @@ func (h *users) Delete(c *gin.Context) {
 	c.JSON(resp.New(http.StatusOK, "user deleted successfully", nil).JSON())
 }
 
+func (h *users) Events(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	lastID := c.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = c.Query("last_event_id")
+	}
+
+	var afterID uint64
+	if lastID != "" {
+		id, err := strconv.ParseUint(lastID, 10, 64)
+		if err != nil {
+			c.JSON(resp.Error(http.StatusBadRequest, "invalid last event id", []interface{}{"last event id must be a positive number"}).JSON())
+			return
+		}
+		afterID = id
+	}
+
+	events, err := h.service.Watch(ctx, afterID)
+	if err != nil {
+		log.Errorf("Failed to watch users: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeSSEEvent(c.Writer, event); err != nil {
+				log.Errorf("Failed to send user event: %v", err)
+				return
+			}
+		case <-heartbeat.C:
+			if err := writeSSEHeartbeat(c.Writer); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+func (h *users) Export(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	filter, ok := parseUserFilter(c)
+	if !ok {
+		return
+	}
+
+	format := strings.ToLower(c.DefaultQuery("format", formatCSV))
+	enc, contentType, ok := newUserEncoder(format, c.Writer)
+	if !ok {
+		c.JSON(resp.Error(http.StatusBadRequest, "failed to export users", []interface{}{"format must be csv or ndjson"}).JSON())
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", `attachment; filename="users.`+format+`"`)
+
+	rows := 0
+	err := h.service.Export(ctx, filter, func(u model.User) error {
+		if err := enc.Encode(u); err != nil {
+			return err
+		}
+
+		rows++
+		if rows%exportFlushRows == 0 {
+			if err := enc.Flush(); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		log.Errorf("Failed to export users: %v", err)
+		// once rows went out the status is sent, so the export is just cut short
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			c.JSON(err.JSON())
+		}
+		return
+	}
+
+	if err := enc.Flush(); err != nil {
+		log.Errorf("Failed to export users: %v", err)
+		return
+	}
+	c.Writer.Flush()
+}
+
 func (h *users) Find(c *gin.Context) {
 	log.Trace()
 
@@ -109,11 +269,100 @@ func (h *users) Find(c *gin.Context) {
 	c.JSON(resp.New(http.StatusOK, "users found successfully", []interface{}{users}).JSON())
 }
 
+func (h *users) FindNearby(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+	limit, offset := parsePagination(c)
+
+	var (
+		coords [3]float64
+		errs   []interface{}
+	)
+	for i, param := range []string{"lat", "lon", "radius_km"} {
+		v := c.Query(param)
+		if v == "" {
+			errs = append(errs, model.FieldError{Field: param, Message: param + " is required"})
+			continue
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			errs = append(errs, model.FieldError{Field: param, Message: param + " must be a number"})
+			continue
+		}
+		coords[i] = f
+	}
+	if errs != nil {
+		c.JSON(resp.Error(http.StatusBadRequest, "invalid query parameter", errs).JSON())
+		return
+	}
+
+	users, total, err := h.service.FindNearby(ctx, coords[0], coords[1], coords[2], limit, offset)
+	if err != nil {
+		log.Error(err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "users fetched successfully", []interface{}{gin.H{
+		"users": users,
+		"total": total,
+	}}).JSON())
+}
+
+func (h *users) Follow(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var userID, followeeID uuid.UUID
+	if !help.ParseUUID(c, "user_id", c.Param("user_id"), &userID) {
+		return
+	}
+	if !help.ParseUUID(c, "followee_id", c.Param("followee_id"), &followeeID) {
+		return
+	}
+
+	follow, err := h.service.Follow(ctx, userID, followeeID)
+	if err != nil {
+		log.Errorf("Failed to follow user: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "user followed successfully", []interface{}{follow}).JSON())
+}
+
 func (h *users) GetAvatarByNickname(c *gin.Context) {
 	log.Trace()
 
 	ctx := c.Request.Context()
 	nickname := c.Param("nickname")
+
+	if s, ok := c.GetQuery("size"); ok {
+		size, e := strconv.Atoi(s)
+		if e != nil {
+			log.Error(e)
+			c.JSON(resp.Error(http.StatusBadRequest, "failed to get avatar", []interface{}{"size must be a number"}).JSON())
+			return
+		}
+
+		rc, contentType, err := h.service.OpenAvatarByNickname(ctx, nickname, size)
+		if err != nil {
+			log.Error(err)
+			c.JSON(err.JSON())
+			return
+		}
+		defer rc.Close()
+
+		// The nickname may point at another avatar later, so revalidate soon.
+		c.DataFromReader(http.StatusOK, -1, contentType, rc, map[string]string{
+			"Cache-Control":          "public, max-age=300",
+			"X-Content-Type-Options": "nosniff",
+		})
+		return
+	}
+
 	avatar, err := h.service.GetAvatarByNickname(ctx, nickname)
 	if err != nil {
 		log.Error(err)
@@ -147,33 +396,78 @@ func (h *users) GetById(c *gin.Context) {
 		return
 	}
 
+	setETag(c, user.Version)
 	c.JSON(resp.New(http.StatusOK, "user fetched successfully", []interface{}{user}).JSON())
 }
 
-func (h *users) List(c *gin.Context) {
+func (h *users) GetByNickname(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+	nickname := c.Param("nickname")
+	user, err := h.service.GetByNickname(ctx, nickname)
+	if err != nil {
+		log.Error(err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "user fetched successfully", []interface{}{user}).JSON())
+}
+
+func (h *users) Import(c *gin.Context) {
 	log.Trace()
 
 	ctx := c.Request.Context()
-	limitStr := c.Query("limit")
-	offsetStr := c.Query("offset")
 
 	var (
-		limit  int32 = 10
-		offset int32 = 0
+		rows []model.User
+		err  error
 	)
+	switch importFormat(c) {
+	case formatCSV:
+		rows, err = readCSVUsers(c.Request.Body)
+	case formatNDJSON:
+		rows, err = readNDJSONUsers(c.Request.Body)
+	default:
+		c.JSON(resp.Error(http.StatusBadRequest, "failed to import users", []interface{}{"format must be csv or ndjson"}).JSON())
+		return
+	}
+	if err != nil {
+		log.Errorf("Failed to read import rows: %v", err)
+		c.JSON(resp.Error(http.StatusBadRequest, "failed to import users", []interface{}{err.Error()}).JSON())
+		return
+	}
 
-	if limitStr != "" {
-		l, err := strconv.Atoi(limitStr)
-		if err == nil && l > 0 {
-			limit = int32(l)
-		}
+	report, e := h.service.Import(ctx, rows)
+	if e != nil {
+		log.Errorf("Failed to import users: %v", e)
+		c.JSON(e.JSON())
+		return
 	}
 
-	if offsetStr != "" {
-		o, err := strconv.Atoi(offsetStr)
-		if err == nil && o >= 0 {
-			offset = int32(o)
+	c.JSON(resp.New(http.StatusOK, "users imported successfully", []interface{}{report}).JSON())
+}
+
+func (h *users) List(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+	limit, offset := parsePagination(c)
+
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		users, next, err := h.service.ListPage(ctx, limit, cursor)
+		if err != nil {
+			log.Error(err)
+			c.JSON(err.JSON())
+			return
 		}
+
+		c.JSON(resp.New(http.StatusOK, "users fetched successfully", []interface{}{gin.H{
+			"users":       users,
+			"next_cursor": next,
+		}}).JSON())
+		return
 	}
 
 	users, e := h.service.List(ctx, limit, offset)
@@ -186,6 +480,251 @@ func (h *users) List(c *gin.Context) {
 	c.JSON(resp.New(http.StatusOK, "users fetched successfully", []interface{}{users}).JSON())
 }
 
+func (h *users) ListAudit(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "user_id", c.Param("user_id"), &id) {
+		return
+	}
+
+	limit, offset := parsePagination(c)
+
+	entries, total, err := h.service.ListAuditEntries(ctx, id, limit, offset)
+	if err != nil {
+		log.Error(err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "audit entries fetched successfully", []interface{}{gin.H{
+		"entries": entries,
+		"total":   total,
+	}}).JSON())
+}
+
+func (h *users) ListFollowers(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "user_id", c.Param("user_id"), &id) {
+		return
+	}
+
+	limit, offset := parsePagination(c)
+
+	users, total, err := h.service.ListFollowers(ctx, id, limit, offset)
+	if err != nil {
+		log.Error(err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "followers fetched successfully", []interface{}{gin.H{
+		"users": users,
+		"total": total,
+	}}).JSON())
+}
+
+func (h *users) ListFollowing(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "user_id", c.Param("user_id"), &id) {
+		return
+	}
+
+	limit, offset := parsePagination(c)
+
+	users, total, err := h.service.ListFollowing(ctx, id, limit, offset)
+	if err != nil {
+		log.Error(err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "followed users fetched successfully", []interface{}{gin.H{
+		"users": users,
+		"total": total,
+	}}).JSON())
+}
+
+func (h *users) Patch(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "user_id", c.Param("user_id"), &id) {
+		return
+	}
+
+	var patch map[string]json.RawMessage
+	if err := json.NewDecoder(c.Request.Body).Decode(&patch); err != nil {
+		c.JSON(resp.Error(http.StatusBadRequest, "failed to update user", []interface{}{"body must be a JSON object", err.Error()}).JSON())
+		return
+	}
+
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		return
+	}
+
+	user := &model.User{ID: id, Version: version}
+	fields := make([]string, 0, len(patch))
+	var invalid []interface{}
+
+	for key, raw := range patch {
+		fields = append(fields, key)
+		switch key {
+		case model.UserFieldCountry:
+			if err := json.Unmarshal(raw, &user.Country); err != nil {
+				invalid = append(invalid, "country must be a string or null")
+			}
+		case model.UserFieldCity:
+			if err := json.Unmarshal(raw, &user.City); err != nil {
+				invalid = append(invalid, "city must be a string or null")
+			}
+		case model.UserFieldClubs:
+			var names []string
+			if err := json.Unmarshal(raw, &names); err != nil {
+				invalid = append(invalid, "clubs must be an array of strings or null")
+			}
+			for _, name := range names {
+				user.Clubs = append(user.Clubs, model.Club{
+					ID:   uuid.New(),
+					Name: name,
+				})
+			}
+		}
+	}
+
+	if len(invalid) > 0 {
+		c.JSON(resp.Error(http.StatusBadRequest, "failed to update user", invalid).JSON())
+		return
+	}
+
+	if err := h.service.Patch(ctx, user, fields); err != nil {
+		log.Errorf("Failed to patch user: %v", err)
+		c.JSON(preconditionError(err, version).JSON())
+		return
+	}
+
+	setETag(c, user.Version)
+	c.JSON(resp.New(http.StatusOK, "user updated succesfully", []interface{}{user}).JSON())
+}
+
+func (h *users) Purge(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "user_id", c.Param("user_id"), &id) {
+		return
+	}
+
+	if err := h.service.Purge(ctx, id); err != nil {
+		log.Errorf("Failed to purge user: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "user purged successfully", nil).JSON())
+}
+
+func (h *users) Restore(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "user_id", c.Param("user_id"), &id) {
+		return
+	}
+
+	user, err := h.service.Restore(ctx, id)
+	if err != nil {
+		log.Errorf("Failed to restore user: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "user restored successfully", []interface{}{user}).JSON())
+}
+
+func (h *users) Search(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+	limit, offset := parsePagination(c)
+
+	filter, ok := parseUserFilter(c)
+	if !ok {
+		return
+	}
+
+	users, total, err := h.service.Search(ctx, filter, limit, offset)
+	if err != nil {
+		log.Error(err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "users fetched successfully", []interface{}{gin.H{
+		"users": users,
+		"total": total,
+	}}).JSON())
+}
+
+func (h *users) ServeAvatar(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	rc, contentType, err := h.service.OpenAvatar(ctx, key)
+	if err != nil {
+		log.Error(err)
+		c.JSON(err.JSON())
+		return
+	}
+	defer rc.Close()
+
+	c.DataFromReader(http.StatusOK, -1, contentType, rc, map[string]string{
+		"Cache-Control":          "public, max-age=31536000, immutable",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (h *users) Unfollow(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var userID, followeeID uuid.UUID
+	if !help.ParseUUID(c, "user_id", c.Param("user_id"), &userID) {
+		return
+	}
+	if !help.ParseUUID(c, "followee_id", c.Param("followee_id"), &followeeID) {
+		return
+	}
+
+	if err := h.service.Unfollow(ctx, userID, followeeID); err != nil {
+		log.Errorf("Failed to unfollow user: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "user unfollowed successfully", nil).JSON())
+}
+
 func (h *users) Update(c *gin.Context) {
 	log.Trace()
 
@@ -196,6 +735,11 @@ func (h *users) Update(c *gin.Context) {
 		return
 	}
 
+	version, ok := h.ifMatchVersion(c, id)
+	if !ok {
+		return
+	}
+
 	var input struct {
 		Country string   `json:"country"`
 		City    string   `json:"city"`
@@ -219,14 +763,16 @@ func (h *users) Update(c *gin.Context) {
 		Country: input.Country,
 		City:    input.City,
 		Clubs:   clubs,
+		Version: version,
 	}
 
 	if err := h.service.Update(ctx, user); err != nil {
 		log.Errorf("Failed to update user: %v", err)
-		c.JSON(err.JSON())
+		c.JSON(preconditionError(err, version).JSON())
 		return
 	}
 
+	setETag(c, user.Version)
 	c.JSON(resp.New(http.StatusOK, "user updated succesfully", nil).JSON())
 }
 
@@ -256,3 +802,77 @@ func (h *users) UpdateImg(c *gin.Context) {
 
 	c.JSON(resp.New(http.StatusOK, "user image updated succesfully", nil).JSON())
 }
+
+func (h *users) UploadAvatar(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "user_id", c.Param("user_id"), &id) {
+		return
+	}
+
+	mr, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(resp.Error(http.StatusBadRequest, "failed to upload avatar", []interface{}{"request must be multipart/form-data"}).JSON())
+		return
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			c.JSON(resp.Error(http.StatusBadRequest, "failed to upload avatar", []interface{}{model.FieldError{Field: avatarFormField, Message: "avatar file is required"}}).JSON())
+			return
+		}
+		if part.FormName() != avatarFormField {
+			part.Close()
+			continue
+		}
+
+		user, e := h.service.UploadAvatar(ctx, id, part.Header.Get("Content-Type"), part)
+		part.Close()
+		if e != nil {
+			log.Errorf("Failed to upload avatar: %v", e)
+			c.JSON(e.JSON())
+			return
+		}
+
+		setETag(c, user.Version)
+		c.JSON(resp.New(http.StatusOK, "avatar uploaded successfully", []interface{}{user}).JSON())
+		return
+	}
+}
+
+func parseUserFilter(c *gin.Context) (model.UserFilter, bool) {
+	filter := model.UserFilter{
+		Country:        c.Query("country"),
+		City:           c.Query("city"),
+		ClubName:       c.Query("club"),
+		NicknamePrefix: c.Query("nickname"),
+	}
+
+	if clubID := c.Query("club_id"); clubID != "" {
+		if !help.ParseUUID(c, "club_id", clubID, &filter.ClubID) {
+			return filter, false
+		}
+	}
+
+	for param, dst := range map[string]*time.Time{
+		"created_from": &filter.CreatedFrom,
+		"created_to":   &filter.CreatedTo,
+	} {
+		v := c.Query(param)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(resp.Error(http.StatusBadRequest, "invalid query parameter", []interface{}{param + " must be an RFC 3339 timestamp"}).JSON())
+			return filter, false
+		}
+		*dst = t
+	}
+
+	return filter, true
+}
@@ -0,0 +1,209 @@
+package handler
+
+import (
+	"net/http"
+
+	model "github.com/demkowo/users/internal/models"
+	service "github.com/demkowo/users/internal/services"
+	"github.com/demkowo/utils/resp"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+type Webhooks interface {
+	Add(*gin.Context)
+	Delete(*gin.Context)
+	GetById(*gin.Context)
+	List(*gin.Context)
+	ListDeliveries(*gin.Context)
+	Redeliver(*gin.Context)
+	Update(*gin.Context)
+}
+
+type webhooks struct {
+	service service.Webhooks
+}
+
+func NewWebhooks(service service.Webhooks) Webhooks {
+	log.Trace()
+	return &webhooks{
+		service: service,
+	}
+}
+
+// webhookInput is the body of the add and edit requests.
+type webhookInput struct {
+	URL        string   `json:"url" binding:"required"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"`
+	Active     *bool    `json:"active"`
+}
+
+func (in webhookInput) toWebhook(id uuid.UUID) *model.Webhook {
+	active := true
+	if in.Active != nil {
+		active = *in.Active
+	}
+
+	return &model.Webhook{
+		ID:         id,
+		URL:        in.URL,
+		EventTypes: in.EventTypes,
+		Secret:     in.Secret,
+		Active:     active,
+	}
+}
+
+func (h *webhooks) Add(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+	var input webhookInput
+
+	if !help.BindJSON(c, &input) {
+		return
+	}
+
+	webhook := input.toWebhook(uuid.New())
+
+	if err := h.service.Add(ctx, webhook); err != nil {
+		log.Errorf("Failed to add webhook: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "webhook added successfully", []interface{}{webhook}).JSON())
+}
+
+func (h *webhooks) Delete(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "webhook_id", c.Param("webhook_id"), &id) {
+		return
+	}
+
+	if err := h.service.Delete(ctx, id); err != nil {
+		log.Errorf("Failed to delete webhook: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "webhook deleted successfully", nil).JSON())
+}
+
+func (h *webhooks) GetById(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "webhook_id", c.Param("webhook_id"), &id) {
+		return
+	}
+
+	webhook, err := h.service.GetByID(ctx, id)
+	if err != nil {
+		log.Error(err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "webhook fetched successfully", []interface{}{webhook}).JSON())
+}
+
+func (h *webhooks) List(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+	limit, offset := parsePagination(c)
+
+	webhooks, total, err := h.service.List(ctx, limit, offset)
+	if err != nil {
+		log.Error(err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "webhooks fetched successfully", []interface{}{gin.H{
+		"webhooks": webhooks,
+		"total":    total,
+	}}).JSON())
+}
+
+func (h *webhooks) ListDeliveries(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "webhook_id", c.Param("webhook_id"), &id) {
+		return
+	}
+
+	limit, offset := parsePagination(c)
+
+	deliveries, total, err := h.service.ListDeliveries(ctx, id, limit, offset)
+	if err != nil {
+		log.Error(err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "webhook deliveries fetched successfully", []interface{}{gin.H{
+		"deliveries": deliveries,
+		"total":      total,
+	}}).JSON())
+}
+
+func (h *webhooks) Redeliver(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var webhookID, deliveryID uuid.UUID
+	if !help.ParseUUID(c, "webhook_id", c.Param("webhook_id"), &webhookID) {
+		return
+	}
+	if !help.ParseUUID(c, "delivery_id", c.Param("delivery_id"), &deliveryID) {
+		return
+	}
+
+	delivery, err := h.service.Redeliver(ctx, webhookID, deliveryID)
+	if err != nil {
+		log.Errorf("Failed to redeliver webhook: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "webhook delivery queued successfully", []interface{}{delivery}).JSON())
+}
+
+func (h *webhooks) Update(c *gin.Context) {
+	log.Trace()
+
+	ctx := c.Request.Context()
+
+	var id uuid.UUID
+	if !help.ParseUUID(c, "webhook_id", c.Param("webhook_id"), &id) {
+		return
+	}
+
+	var input webhookInput
+	if !help.BindJSON(c, &input) {
+		return
+	}
+
+	webhook := input.toWebhook(id)
+
+	if err := h.service.Update(ctx, webhook); err != nil {
+		log.Errorf("Failed to update webhook: %v", err)
+		c.JSON(err.JSON())
+		return
+	}
+
+	c.JSON(resp.New(http.StatusOK, "webhook updated successfully", []interface{}{webhook}).JSON())
+}
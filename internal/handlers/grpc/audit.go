@@ -0,0 +1,101 @@
+package pb_handler
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	pb "github.com/demkowo/users/internal/generated"
+	model "github.com/demkowo/users/internal/models"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// auditActorKey is the metadata key naming the caller on whose behalf a call is made.
+const auditActorKey = "x-actor"
+
+// AuditActorUnaryInterceptor puts the caller of a unary call and its peer
+// address into its context, so changes made by the call are attributed to them.
+func AuditActorUnaryInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return handler(withAuditActor(ctx), req)
+}
+
+// AuditActorStreamInterceptor is the streaming counterpart of
+// AuditActorUnaryInterceptor.
+func AuditActorStreamInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &auditStream{ServerStream: ss, ctx: withAuditActor(ss.Context())})
+}
+
+type auditStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *auditStream) Context() context.Context {
+	return s.ctx
+}
+
+func withAuditActor(ctx context.Context) context.Context {
+	var addr string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		addr = p.Addr.String()
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			addr = host
+		}
+	}
+
+	var id string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(auditActorKey); len(v) > 0 {
+			id = strings.TrimSpace(v[0])
+		}
+	}
+	if id == "" {
+		id = addr
+	}
+
+	return model.WithAuditActor(ctx, model.NewAuditActor(id, addr, model.AuditTransportGRPC))
+}
+
+func toProtoAuditEntry(a *model.AuditEntry) (*pb.AuditEntry, error) {
+	changes := make(map[string]*pb.AuditChange, len(a.Changes))
+	for field, c := range a.Changes {
+		from, err := auditValue(c.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := auditValue(c.To)
+		if err != nil {
+			return nil, err
+		}
+		changes[field] = &pb.AuditChange{From: from, To: to}
+	}
+
+	return &pb.AuditEntry{
+		Id:        a.ID.String(),
+		UserId:    a.UserID.String(),
+		Actor:     a.Actor,
+		ActorAddr: a.ActorAddr,
+		Transport: a.Transport,
+		Operation: a.Operation,
+		Before:    toProtoUser(a.Before),
+		After:     toProtoUser(a.After),
+		Changes:   changes,
+		Created:   timestamppb.New(a.Created),
+	}, nil
+}
+
+// auditValue converts a changed value from the audit log JSON into a protobuf Value.
+func auditValue(v interface{}) (*structpb.Value, error) {
+	if names, ok := v.([]string); ok {
+		list := make([]interface{}, len(names))
+		for i, n := range names {
+			list[i] = n
+		}
+		v = list
+	}
+	return structpb.NewValue(v)
+}
@@ -0,0 +1,437 @@
+package pb_handler
+
+import (
+	"context"
+
+	pb "github.com/demkowo/users/internal/generated"
+	model "github.com/demkowo/users/internal/models"
+	service "github.com/demkowo/users/internal/services"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ClubsServer struct {
+	Service service.Clubs
+	pb.ClubsServer
+}
+
+func (h *ClubsServer) AcceptRequest(ctx context.Context, req *pb.AcceptClubRequestRequest) (*pb.AcceptClubRequestResponse, error) {
+	log.Trace("Accept club request via gRPC")
+
+	clubID, err := uuid.Parse(req.GetClubId())
+	if err != nil {
+		log.Errorf("Invalid club ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid club id")
+	}
+
+	requestID, err := uuid.Parse(req.GetRequestId())
+	if err != nil {
+		log.Errorf("Invalid request ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid request id")
+	}
+
+	request, e := h.Service.AcceptRequest(ctx, clubID, requestID)
+	if e != nil {
+		log.Errorf("Failed to accept club request: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.AcceptClubRequestResponse{
+		Request: toProtoClubRequest(request),
+	}, nil
+}
+
+func (h *ClubsServer) Add(ctx context.Context, req *pb.AddClubRequest) (*pb.AddClubResponse, error) {
+	log.Trace("Add club via gRPC")
+
+	club := &model.Club{
+		ID:         uuid.New(),
+		Name:       req.GetName(),
+		JoinPolicy: req.GetJoinPolicy(),
+	}
+
+	if err := h.Service.Add(ctx, club); err != nil {
+		log.Errorf("Failed to add club: %v", err)
+		return nil, toGRPCError(err)
+	}
+
+	return &pb.AddClubResponse{
+		Club: toProtoClub(club),
+	}, nil
+}
+
+func (h *ClubsServer) Delete(ctx context.Context, req *pb.DeleteClubRequest) (*pb.DeleteClubResponse, error) {
+	log.Trace("Delete club via gRPC")
+
+	clubID, err := uuid.Parse(req.GetClubId())
+	if err != nil {
+		log.Errorf("Invalid club ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid club id")
+	}
+
+	if e := h.Service.Delete(ctx, clubID); e != nil {
+		log.Errorf("Failed to delete club: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.DeleteClubResponse{}, nil
+}
+
+func (h *ClubsServer) GetById(ctx context.Context, req *pb.GetClubByIdRequest) (*pb.GetClubByIdResponse, error) {
+	log.Trace("Get club by ID via gRPC")
+
+	clubID, err := uuid.Parse(req.GetClubId())
+	if err != nil {
+		log.Errorf("Invalid club ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid club id")
+	}
+
+	club, e := h.Service.GetByID(ctx, clubID)
+	if e != nil {
+		log.Errorf("Failed to get club: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.GetClubByIdResponse{
+		Club: toProtoClub(club),
+	}, nil
+}
+
+func (h *ClubsServer) Invite(ctx context.Context, req *pb.InviteToClubRequest) (*pb.InviteToClubResponse, error) {
+	log.Trace("Invite user to club via gRPC")
+
+	clubID, err := uuid.Parse(req.GetClubId())
+	if err != nil {
+		log.Errorf("Invalid club ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid club id")
+	}
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		log.Errorf("Invalid user ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	request, e := h.Service.Invite(ctx, clubID, userID)
+	if e != nil {
+		log.Errorf("Failed to invite user: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.InviteToClubResponse{
+		Request: toProtoClubRequest(request),
+	}, nil
+}
+
+func (h *ClubsServer) List(ctx context.Context, req *pb.ListClubsRequest) (*pb.ListClubsResponse, error) {
+	log.Trace("List clubs via gRPC")
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = 10
+	}
+
+	found, total, e := h.Service.List(ctx, limit, req.GetOffset())
+	if e != nil {
+		log.Errorf("Failed to list clubs: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.ListClubsResponse{
+		Clubs: toProtoClubs(found),
+		Total: total,
+	}, nil
+}
+
+func (h *ClubsServer) ListClubMembers(ctx context.Context, req *pb.ListClubMembersRequest) (*pb.ListClubMembersResponse, error) {
+	log.Trace("List club members via gRPC")
+
+	ctx = withLocale(ctx, req.GetLocale())
+
+	clubID, err := uuid.Parse(req.GetClubId())
+	if err != nil {
+		log.Errorf("Invalid club ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid club id")
+	}
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = 10
+	}
+
+	members, total, e := h.Service.ListMembers(ctx, clubID, limit, req.GetOffset())
+	if e != nil {
+		log.Errorf("Failed to list club members: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	users := make([]model.User, len(members))
+	for i, m := range members {
+		users[i] = m.User
+	}
+
+	return &pb.ListClubMembersResponse{
+		Users:   toProtoUsers(users),
+		Total:   total,
+		Members: toProtoClubMembers(members),
+	}, nil
+}
+
+func (h *ClubsServer) ListRequests(ctx context.Context, req *pb.ListClubRequestsRequest) (*pb.ListClubRequestsResponse, error) {
+	log.Trace("List club requests via gRPC")
+
+	id, err := uuid.Parse(req.GetClubId())
+	if err != nil {
+		log.Errorf("Invalid club ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid club id")
+	}
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = 10
+	}
+
+	found, total, e := h.Service.ListRequests(ctx, model.ClubRequestFilter{
+		ClubID: id,
+		Kind:   req.GetKind(),
+		Status: req.GetStatus(),
+	}, limit, req.GetOffset())
+	if e != nil {
+		log.Errorf("Failed to list club requests: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.ListClubRequestsResponse{
+		Requests: toProtoClubRequests(found),
+		Total:    total,
+	}, nil
+}
+
+func (h *ClubsServer) ListUserRequests(ctx context.Context, req *pb.ListUserClubRequestsRequest) (*pb.ListUserClubRequestsResponse, error) {
+	log.Trace("List user club requests via gRPC")
+
+	id, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		log.Errorf("Invalid user ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = 10
+	}
+
+	found, total, e := h.Service.ListRequests(ctx, model.ClubRequestFilter{
+		UserID: id,
+		Kind:   req.GetKind(),
+		Status: req.GetStatus(),
+	}, limit, req.GetOffset())
+	if e != nil {
+		log.Errorf("Failed to list club requests: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.ListUserClubRequestsResponse{
+		Requests: toProtoClubRequests(found),
+		Total:    total,
+	}, nil
+}
+
+func (h *ClubsServer) RejectRequest(ctx context.Context, req *pb.RejectClubRequestRequest) (*pb.RejectClubRequestResponse, error) {
+	log.Trace("Reject club request via gRPC")
+
+	clubID, err := uuid.Parse(req.GetClubId())
+	if err != nil {
+		log.Errorf("Invalid club ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid club id")
+	}
+
+	requestID, err := uuid.Parse(req.GetRequestId())
+	if err != nil {
+		log.Errorf("Invalid request ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid request id")
+	}
+
+	request, e := h.Service.RejectRequest(ctx, clubID, requestID)
+	if e != nil {
+		log.Errorf("Failed to reject club request: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.RejectClubRequestResponse{
+		Request: toProtoClubRequest(request),
+	}, nil
+}
+
+func (h *ClubsServer) Rename(ctx context.Context, req *pb.RenameClubRequest) (*pb.RenameClubResponse, error) {
+	log.Trace("Rename club via gRPC")
+
+	clubID, err := uuid.Parse(req.GetClubId())
+	if err != nil {
+		log.Errorf("Invalid club ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid club id")
+	}
+
+	club, e := h.Service.Rename(ctx, clubID, req.GetName())
+	if e != nil {
+		log.Errorf("Failed to rename club: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.RenameClubResponse{
+		Club: toProtoClub(club),
+	}, nil
+}
+
+func (h *ClubsServer) RequestToJoin(ctx context.Context, req *pb.RequestToJoinClubRequest) (*pb.RequestToJoinClubResponse, error) {
+	log.Trace("Request to join club via gRPC")
+
+	clubID, err := uuid.Parse(req.GetClubId())
+	if err != nil {
+		log.Errorf("Invalid club ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid club id")
+	}
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		log.Errorf("Invalid user ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	request, e := h.Service.RequestToJoin(ctx, clubID, userID)
+	if e != nil {
+		log.Errorf("Failed to request to join club: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.RequestToJoinClubResponse{
+		Request: toProtoClubRequest(request),
+	}, nil
+}
+
+func (h *ClubsServer) SetJoinPolicy(ctx context.Context, req *pb.SetClubJoinPolicyRequest) (*pb.SetClubJoinPolicyResponse, error) {
+	log.Trace("Set club join policy via gRPC")
+
+	clubID, err := uuid.Parse(req.GetClubId())
+	if err != nil {
+		log.Errorf("Invalid club ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid club id")
+	}
+
+	club, e := h.Service.SetJoinPolicy(ctx, clubID, req.GetJoinPolicy())
+	if e != nil {
+		log.Errorf("Failed to change club join policy: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.SetClubJoinPolicyResponse{
+		Club: toProtoClub(club),
+	}, nil
+}
+
+func (h *ClubsServer) SetMemberRole(ctx context.Context, req *pb.SetClubMemberRoleRequest) (*pb.SetClubMemberRoleResponse, error) {
+	log.Trace("Set club member role via gRPC")
+
+	clubID, err := uuid.Parse(req.GetClubId())
+	if err != nil {
+		log.Errorf("Invalid club ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid club id")
+	}
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		log.Errorf("Invalid user ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	member, e := h.Service.SetMemberRole(ctx, clubID, userID, req.GetRole())
+	if e != nil {
+		log.Errorf("Failed to change member role: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.SetClubMemberRoleResponse{
+		Member: toProtoClubMember(member),
+	}, nil
+}
+
+func toProtoClub(c *model.Club) *pb.Club {
+	if c == nil {
+		return nil
+	}
+
+	club := &pb.Club{
+		Id:         c.ID.String(),
+		Name:       c.Name,
+		Role:       c.Role,
+		JoinPolicy: c.JoinPolicy,
+	}
+	if c.Joined != nil {
+		club.Joined = timestamppb.New(*c.Joined)
+	}
+	return club
+}
+
+func toProtoClubMember(m *model.ClubMember) *pb.ClubMember {
+	if m == nil {
+		return nil
+	}
+
+	return &pb.ClubMember{
+		User:   toProtoUser(&m.User),
+		Role:   m.Role,
+		Joined: timestamppb.New(m.Joined),
+	}
+}
+
+func toProtoClubMembers(ms []model.ClubMember) []*pb.ClubMember {
+	res := make([]*pb.ClubMember, 0, len(ms))
+	for _, m := range ms {
+		member := m
+		res = append(res, toProtoClubMember(&member))
+	}
+	return res
+}
+
+func toProtoClubs(cs []model.Club) []*pb.Club {
+	res := make([]*pb.Club, 0, len(cs))
+	for _, c := range cs {
+		club := c
+		res = append(res, toProtoClub(&club))
+	}
+	return res
+}
+
+func toProtoClubRequest(r *model.ClubRequest) *pb.ClubRequest {
+	if r == nil {
+		return nil
+	}
+
+	request := &pb.ClubRequest{
+		Id:        r.ID.String(),
+		ClubId:    r.ClubID.String(),
+		UserId:    r.UserID.String(),
+		Kind:      r.Kind,
+		Status:    r.Status,
+		CreatedBy: r.CreatedBy,
+		DecidedBy: r.DecidedBy,
+		Created:   timestamppb.New(r.Created),
+		Expires:   timestamppb.New(r.Expires),
+	}
+	if r.Decided != nil {
+		request.Decided = timestamppb.New(*r.Decided)
+	}
+	return request
+}
+
+func toProtoClubRequests(rs []model.ClubRequest) []*pb.ClubRequest {
+	res := make([]*pb.ClubRequest, 0, len(rs))
+	for _, r := range rs {
+		request := r
+		res = append(res, toProtoClubRequest(&request))
+	}
+	return res
+}
@@ -0,0 +1,12 @@
+package pb_handler
+
+import (
+	"context"
+
+	model "github.com/demkowo/users/internal/models"
+)
+
+// withLocale puts the language of the locale field of a request into ctx.
+func withLocale(ctx context.Context, locale string) context.Context {
+	return model.WithLocale(ctx, model.ParseLocale(locale))
+}
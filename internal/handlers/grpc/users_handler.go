@@ -2,7 +2,9 @@ package pb_handler
 
 import (
 	"context"
-	"fmt"
+	"errors"
+	"io"
+	"net/http"
 	"time"
 
 	pb "github.com/demkowo/users/internal/generated"
@@ -11,6 +13,7 @@ import (
 	"github.com/demkowo/utils/resp"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -53,6 +56,26 @@ func (h *UsersServer) Add(ctx context.Context, req *pb.AddUserRequest) (*pb.AddU
 	}, nil
 }
 
+func (h *UsersServer) ChangeNickname(ctx context.Context, req *pb.ChangeNicknameRequest) (*pb.ChangeNicknameResponse, error) {
+	log.Trace("Change nickname via gRPC")
+
+	uid, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		log.Errorf("Invalid user ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	user, e := h.Service.ChangeNickname(ctx, uid, req.GetNickname())
+	if e != nil {
+		log.Errorf("Failed to change nickname: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.ChangeNicknameResponse{
+		User: toProtoUser(user),
+	}, nil
+}
+
 func (h *UsersServer) Delete(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
 	log.Trace("Delete user via gRPC")
 
@@ -64,9 +87,31 @@ func (h *UsersServer) Delete(ctx context.Context, req *pb.DeleteUserRequest) (*p
 	return &pb.DeleteUserResponse{}, nil
 }
 
+func (h *UsersServer) ExportUsers(req *pb.ExportUsersRequest, stream pb.Users_ExportUsersServer) error {
+	log.Trace("Export users via gRPC")
+
+	filter, err := toUserFilter(req)
+	if err != nil {
+		log.Errorf("Invalid export filter: %v", err)
+		return err
+	}
+
+	e := h.Service.Export(stream.Context(), filter, func(u model.User) error {
+		return stream.Send(&pb.ExportUsersResponse{User: toProtoUser(&u)})
+	})
+	if e != nil {
+		log.Errorf("Failed to export users: %v", e)
+		return toGRPCError(e)
+	}
+
+	return nil
+}
+
 func (h *UsersServer) Find(ctx context.Context, req *pb.FindUsersRequest) (*pb.FindUsersResponse, error) {
 	log.Trace("Find all users via gRPC")
 
+	ctx = withLocale(ctx, req.GetLocale())
+
 	usersFound, err := h.Service.Find(ctx)
 	if err != nil {
 		log.Errorf("Failed to find users: %v", err)
@@ -78,6 +123,67 @@ func (h *UsersServer) Find(ctx context.Context, req *pb.FindUsersRequest) (*pb.F
 	}, nil
 }
 
+func (h *UsersServer) FindNearbyUsers(ctx context.Context, req *pb.FindNearbyUsersRequest) (*pb.FindNearbyUsersResponse, error) {
+	log.Trace("Find nearby users via gRPC")
+
+	ctx = withLocale(ctx, req.GetLocale())
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = 10
+	}
+
+	found, total, err := h.Service.FindNearby(ctx, req.GetLat(), req.GetLon(), req.GetRadiusKm(), limit, req.GetOffset())
+	if err != nil {
+		log.Errorf("Failed to find nearby users: %v", err)
+		return nil, toGRPCError(err)
+	}
+
+	users := make([]*pb.NearbyUser, 0, len(found))
+	for _, u := range found {
+		user := u.User
+		users = append(users, &pb.NearbyUser{
+			User:       toProtoUser(&user),
+			DistanceKm: u.DistanceKm,
+		})
+	}
+
+	return &pb.FindNearbyUsersResponse{
+		Users: users,
+		Total: total,
+	}, nil
+}
+
+func (h *UsersServer) Follow(ctx context.Context, req *pb.FollowUserRequest) (*pb.FollowUserResponse, error) {
+	log.Trace("Follow user via gRPC")
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		log.Errorf("Invalid user ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	followeeID, err := uuid.Parse(req.GetFolloweeId())
+	if err != nil {
+		log.Errorf("Invalid followee ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid followee id")
+	}
+
+	follow, e := h.Service.Follow(ctx, userID, followeeID)
+	if e != nil {
+		log.Errorf("Failed to follow user: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.FollowUserResponse{
+		Follow: &pb.Follow{
+			FollowerId: follow.FollowerID.String(),
+			FolloweeId: follow.FolloweeID.String(),
+			Since:      timestamppb.New(follow.Since),
+		},
+	}, nil
+}
+
 func (h *UsersServer) GetAvatarByNickname(ctx context.Context, req *pb.GetAvatarByNicknameRequest) (*pb.GetAvatarByNicknameResponse, error) {
 	log.Trace("GetAvatarByNickname via gRPC")
 
@@ -87,12 +193,43 @@ func (h *UsersServer) GetAvatarByNickname(ctx context.Context, req *pb.GetAvatar
 		return nil, toGRPCError(err)
 	}
 
-	return &pb.GetAvatarByNicknameResponse{Avatar: avatar}, nil
+	variants := make(map[int32]string, len(avatar.Variants))
+	for size, url := range avatar.Variants {
+		variants[int32(size)] = url
+	}
+
+	res := &pb.GetAvatarByNicknameResponse{
+		Avatar:    avatar.Img,
+		Variants:  variants,
+		Url:       avatar.URL,
+		Generated: avatar.Generated,
+	}
+
+	if req.GetSize() != 0 {
+		rc, contentType, err := h.Service.OpenAvatarByNickname(ctx, req.GetNickname(), int(req.GetSize()))
+		if err != nil {
+			log.Errorf("Failed to get avatar: %v", err)
+			return nil, toGRPCError(err)
+		}
+		defer rc.Close()
+
+		image, e := io.ReadAll(rc)
+		if e != nil {
+			log.Errorf("Failed to read avatar: %v", e)
+			return nil, status.Error(codes.Internal, "failed to read avatar")
+		}
+		res.Image = image
+		res.ContentType = contentType
+	}
+
+	return res, nil
 }
 
 func (h *UsersServer) GetById(ctx context.Context, req *pb.GetByIdRequest) (*pb.GetByIdResponse, error) {
 	log.Trace("GetById via gRPC")
 
+	ctx = withLocale(ctx, req.GetLocale())
+
 	userID, err := uuid.Parse(req.GetUserId())
 	if err != nil {
 		log.Errorf("Invalid user ID: %v", err)
@@ -110,12 +247,106 @@ func (h *UsersServer) GetById(ctx context.Context, req *pb.GetByIdRequest) (*pb.
 	}, nil
 }
 
+func (h *UsersServer) GetByNickname(ctx context.Context, req *pb.GetByNicknameRequest) (*pb.GetByNicknameResponse, error) {
+	log.Trace("GetByNickname via gRPC")
+
+	ctx = withLocale(ctx, req.GetLocale())
+
+	user, err := h.Service.GetByNickname(ctx, req.GetNickname())
+	if err != nil {
+		log.Errorf("Failed to get user by nickname: %v", err)
+		return nil, toGRPCError(err)
+	}
+
+	return &pb.GetByNicknameResponse{
+		User: toProtoUser(user),
+	}, nil
+}
+
+func (h *UsersServer) ImportUsers(stream pb.Users_ImportUsersServer) error {
+	log.Trace("Import users via gRPC")
+
+	var rows []model.User
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Errorf("Failed to receive import row: %v", err)
+			return err
+		}
+
+		var clubs []model.Club
+		for _, clubName := range req.GetClubs() {
+			clubs = append(clubs, model.Club{
+				ID:   uuid.New(),
+				Name: clubName,
+			})
+		}
+
+		rows = append(rows, model.User{
+			ID:       uuid.New(),
+			Nickname: req.GetNickname(),
+			Img:      req.GetImg(),
+			Country:  req.GetCountry(),
+			City:     req.GetCity(),
+			Clubs:    clubs,
+		})
+	}
+
+	report, e := h.Service.Import(stream.Context(), rows)
+	if e != nil {
+		log.Errorf("Failed to import users: %v", e)
+		return toGRPCError(e)
+	}
+
+	results := make([]*pb.ImportUserResult, 0, len(report.Results))
+	for _, r := range report.Results {
+		result := &pb.ImportUserResult{
+			Row:      int32(r.Row),
+			Nickname: r.Nickname,
+			Error:    r.Error,
+		}
+		if r.UserID != uuid.Nil {
+			result.UserId = r.UserID.String()
+		}
+		results = append(results, result)
+	}
+
+	return stream.SendAndClose(&pb.ImportUsersResponse{
+		Total:    int32(report.Total),
+		Imported: int32(report.Imported),
+		Failed:   int32(report.Failed),
+		Results:  results,
+	})
+}
+
 func (h *UsersServer) List(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
 	log.Trace("List users via gRPC")
 
+	ctx = withLocale(ctx, req.GetLocale())
+
 	limit := req.GetLimit()
 	offset := req.GetOffset()
 
+	if req.GetPageToken() != "" || offset == 0 {
+		if limit <= 0 {
+			limit = 10
+		}
+
+		found, next, e := h.Service.ListPage(ctx, limit, req.GetPageToken())
+		if e != nil {
+			log.Errorf("Failed to list users: %v", e)
+			return nil, toGRPCError(e)
+		}
+
+		return &pb.ListUsersResponse{
+			Users:         toProtoUsers(found),
+			NextPageToken: next,
+		}, nil
+	}
+
 	found, e := h.Service.List(ctx, limit, offset)
 	if e != nil {
 		log.Errorf("Failed to list users: %v", e)
@@ -127,14 +358,189 @@ func (h *UsersServer) List(ctx context.Context, req *pb.ListUsersRequest) (*pb.L
 	}, nil
 }
 
+func (h *UsersServer) ListAuditEntries(ctx context.Context, req *pb.ListAuditEntriesRequest) (*pb.ListAuditEntriesResponse, error) {
+	log.Trace("List audit entries via gRPC")
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		log.Errorf("Invalid user ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = 10
+	}
+
+	found, total, e := h.Service.ListAuditEntries(ctx, userID, limit, req.GetOffset())
+	if e != nil {
+		log.Errorf("Failed to list audit entries: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	entries := make([]*pb.AuditEntry, 0, len(found))
+	for i := range found {
+		entry, err := toProtoAuditEntry(&found[i])
+		if err != nil {
+			log.Errorf("Failed to convert audit entry: %v", err)
+			return nil, status.Error(codes.Internal, "failed to list audit entries")
+		}
+		entries = append(entries, entry)
+	}
+
+	return &pb.ListAuditEntriesResponse{
+		Entries: entries,
+		Total:   total,
+	}, nil
+}
+
+func (h *UsersServer) ListFollowers(ctx context.Context, req *pb.ListFollowersRequest) (*pb.ListFollowersResponse, error) {
+	log.Trace("List followers via gRPC")
+
+	ctx = withLocale(ctx, req.GetLocale())
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		log.Errorf("Invalid user ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = 10
+	}
+
+	found, total, e := h.Service.ListFollowers(ctx, userID, limit, req.GetOffset())
+	if e != nil {
+		log.Errorf("Failed to list followers: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.ListFollowersResponse{
+		Users: toProtoFollowUsers(found),
+		Total: total,
+	}, nil
+}
+
+func (h *UsersServer) ListFollowing(ctx context.Context, req *pb.ListFollowingRequest) (*pb.ListFollowingResponse, error) {
+	log.Trace("List followed users via gRPC")
+
+	ctx = withLocale(ctx, req.GetLocale())
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		log.Errorf("Invalid user ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = 10
+	}
+
+	found, total, e := h.Service.ListFollowing(ctx, userID, limit, req.GetOffset())
+	if e != nil {
+		log.Errorf("Failed to list followed users: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.ListFollowingResponse{
+		Users: toProtoFollowUsers(found),
+		Total: total,
+	}, nil
+}
+
+func (h *UsersServer) Purge(ctx context.Context, req *pb.PurgeUserRequest) (*pb.PurgeUserResponse, error) {
+	log.Trace("Purge user via gRPC")
+
+	uid, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		log.Errorf("Invalid user ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	if e := h.Service.Purge(ctx, uid); e != nil {
+		log.Errorf("Failed to purge user: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.PurgeUserResponse{}, nil
+}
+
+func (h *UsersServer) Restore(ctx context.Context, req *pb.RestoreUserRequest) (*pb.RestoreUserResponse, error) {
+	log.Trace("Restore user via gRPC")
+
+	uid, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		log.Errorf("Invalid user ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	user, e := h.Service.Restore(ctx, uid)
+	if e != nil {
+		log.Errorf("Failed to restore user: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.RestoreUserResponse{
+		User: toProtoUser(user),
+	}, nil
+}
+
+func (h *UsersServer) Search(ctx context.Context, req *pb.SearchUsersRequest) (*pb.SearchUsersResponse, error) {
+	log.Trace("Search users via gRPC")
+
+	ctx = withLocale(ctx, req.GetLocale())
+
+	filter, err := toUserFilter(req)
+	if err != nil {
+		log.Errorf("Invalid search filter: %v", err)
+		return nil, err
+	}
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = 10
+	}
+
+	found, total, e := h.Service.Search(ctx, filter, limit, req.GetOffset())
+	if e != nil {
+		log.Errorf("Failed to search users: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.SearchUsersResponse{
+		Users: toProtoUsers(found),
+		Total: total,
+	}, nil
+}
+
+func (h *UsersServer) Unfollow(ctx context.Context, req *pb.UnfollowUserRequest) (*pb.UnfollowUserResponse, error) {
+	log.Trace("Unfollow user via gRPC")
+
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		log.Errorf("Invalid user ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	followeeID, err := uuid.Parse(req.GetFolloweeId())
+	if err != nil {
+		log.Errorf("Invalid followee ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid followee id")
+	}
+
+	if e := h.Service.Unfollow(ctx, userID, followeeID); e != nil {
+		log.Errorf("Failed to unfollow user: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.UnfollowUserResponse{}, nil
+}
+
 func (h *UsersServer) Update(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UpdateUserResponse, error) {
 	log.Trace("Update user via gRPC")
 
-	fmt.Println(req.City)
-	fmt.Println(req.Clubs)
-	fmt.Println(req.Country)
-	fmt.Println(req.UserId)
-
 	uid, err := uuid.Parse(req.GetUserId())
 	if err != nil {
 		log.Errorf("Invalid user ID: %v", err)
@@ -155,6 +561,16 @@ func (h *UsersServer) Update(ctx context.Context, req *pb.UpdateUserRequest) (*p
 		City:    req.GetCity(),
 		Clubs:   clubs,
 		Updated: time.Now(),
+		Version: req.GetExpectedVersion(),
+	}
+
+	if paths := req.GetUpdateMask().GetPaths(); len(paths) > 0 {
+		if e := h.Service.Patch(ctx, user, paths); e != nil {
+			log.Errorf("Failed to patch user: %v", e)
+			return nil, toGRPCError(e)
+		}
+
+		return &pb.UpdateUserResponse{User: toProtoUser(user)}, nil
 	}
 
 	if e := h.Service.Update(ctx, user); e != nil {
@@ -162,7 +578,7 @@ func (h *UsersServer) Update(ctx context.Context, req *pb.UpdateUserRequest) (*p
 		return nil, toGRPCError(e)
 	}
 
-	return &pb.UpdateUserResponse{}, nil
+	return &pb.UpdateUserResponse{User: toProtoUser(user)}, nil
 }
 
 func (h *UsersServer) UpdateImg(ctx context.Context, req *pb.UpdateImgRequest) (*pb.UpdateImgResponse, error) {
@@ -182,28 +598,167 @@ func (h *UsersServer) UpdateImg(ctx context.Context, req *pb.UpdateImgRequest) (
 	return &pb.UpdateImgResponse{}, nil
 }
 
+func (h *UsersServer) UploadAvatar(stream pb.Users_UploadAvatarServer) error {
+	log.Trace("Upload avatar via gRPC")
+
+	req, err := stream.Recv()
+	if err != nil {
+		log.Errorf("Failed to receive avatar info: %v", err)
+		return err
+	}
+
+	info := req.GetInfo()
+	if info == nil {
+		return status.Error(codes.InvalidArgument, "first message must carry the avatar info")
+	}
+
+	uid, err := uuid.Parse(info.GetUserId())
+	if err != nil {
+		log.Errorf("Invalid user ID: %v", err)
+		return status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	user, e := h.Service.UploadAvatar(stream.Context(), uid, info.GetContentType(), &avatarChunkReader{stream: stream})
+	if e != nil {
+		log.Errorf("Failed to upload avatar: %v", e)
+		return toGRPCError(e)
+	}
+
+	return stream.SendAndClose(&pb.UploadAvatarResponse{
+		User: toProtoUser(user),
+	})
+}
+
+// avatarChunkReader reads the image bytes of an UploadAvatar stream chunk by chunk.
+type avatarChunkReader struct {
+	stream pb.Users_UploadAvatarServer
+	buf    []byte
+}
+
+func (r *avatarChunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		if req.GetInfo() != nil {
+			return 0, errors.New("avatar info must only be sent first")
+		}
+		r.buf = req.GetChunk()
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (h *UsersServer) Watch(req *pb.WatchRequest, stream pb.Users_WatchServer) error {
+	log.Trace("Watch users via gRPC")
+
+	ctx := stream.Context()
+
+	events, e := h.Service.Watch(ctx, req.GetAfterEventId())
+	if e != nil {
+		log.Errorf("Failed to watch users: %v", e)
+		return toGRPCError(e)
+	}
+
+	for event := range events {
+		if err := stream.Send(&pb.WatchResponse{Event: toProtoUserEvent(event)}); err != nil {
+			log.Errorf("Failed to send user event: %v", err)
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return status.Error(codes.ResourceExhausted, "watcher fell behind, resume from the last received event id")
+}
+
+// userFilterRequest is implemented by the requests that carry user filters.
+type userFilterRequest interface {
+	GetCountry() string
+	GetCity() string
+	GetClubId() string
+	GetClubName() string
+	GetNicknamePrefix() string
+	GetCreatedFrom() *timestamppb.Timestamp
+	GetCreatedTo() *timestamppb.Timestamp
+}
+
+func toUserFilter(req userFilterRequest) (model.UserFilter, error) {
+	filter := model.UserFilter{
+		Country:        req.GetCountry(),
+		City:           req.GetCity(),
+		ClubName:       req.GetClubName(),
+		NicknamePrefix: req.GetNicknamePrefix(),
+	}
+
+	if req.GetClubId() != "" {
+		clubID, err := uuid.Parse(req.GetClubId())
+		if err != nil {
+			return filter, status.Error(codes.InvalidArgument, "invalid club id")
+		}
+		filter.ClubID = clubID
+	}
+	if req.GetCreatedFrom() != nil {
+		filter.CreatedFrom = req.GetCreatedFrom().AsTime()
+	}
+	if req.GetCreatedTo() != nil {
+		filter.CreatedTo = req.GetCreatedTo().AsTime()
+	}
+
+	return filter, nil
+}
+
 func toProtoUser(u *model.User) *pb.User {
 	if u == nil {
 		return nil
 	}
 	protoClubs := make([]*pb.Club, 0, len(u.Clubs))
 	for _, c := range u.Clubs {
-		protoClubs = append(protoClubs, &pb.Club{
-			Id:   c.ID.String(),
-			Name: c.Name,
-		})
+		club := c
+		protoClubs = append(protoClubs, toProtoClub(&club))
 	}
 
 	return &pb.User{
-		Id:       u.ID.String(),
-		Nickname: u.Nickname,
-		Img:      u.Img,
-		Country:  u.Country,
-		City:     u.City,
-		Clubs:    protoClubs,
-		Created:  timestamppb.New(u.Created),
-		Updated:  timestamppb.New(u.Updated),
-		Deleted:  u.Deleted,
+		Id:          u.ID.String(),
+		Nickname:    u.Nickname,
+		Img:         u.Img,
+		Country:     u.Country,
+		CountryName: u.CountryName,
+		City:        u.City,
+		CityId:      u.CityID,
+		Clubs:       protoClubs,
+		Created:     timestamppb.New(u.Created),
+		Updated:     timestamppb.New(u.Updated),
+		Deleted:     u.Deleted,
+		Version:     u.Version,
+		Followers:   u.Followers,
+		Following:   u.Following,
+	}
+}
+
+func toProtoFollowUsers(us []model.FollowUser) []*pb.FollowUser {
+	res := make([]*pb.FollowUser, 0, len(us))
+	for _, u := range us {
+		user := u.User
+		res = append(res, &pb.FollowUser{
+			User:  toProtoUser(&user),
+			Since: timestamppb.New(u.Since),
+		})
+	}
+	return res
+}
+
+func toProtoUserEvent(e model.UserEvent) *pb.UserEvent {
+	return &pb.UserEvent{
+		Id:     e.ID,
+		Type:   e.Type,
+		UserId: e.UserID.String(),
+		User:   toProtoUser(&e.User),
+		Time:   timestamppb.New(e.Time),
 	}
 }
 
@@ -216,10 +771,59 @@ func toProtoUsers(us []model.User) []*pb.User {
 	return res
 }
 
+// toGRPCError maps err to a status, with field causes as BadRequest violations.
 func toGRPCError(err *resp.Err) error {
 	if err == nil {
 		return nil
 	}
 
-	return status.Error(codes.Code(err.Code), err.Error)
+	st := status.New(httpToGRPCCode(err.Code), err.Error)
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, c := range err.Causes {
+		if fe, ok := c.(model.FieldError); ok {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       fe.Field,
+				Description: fe.Message,
+			})
+		}
+	}
+	if len(violations) > 0 {
+		if withDetails, e := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); e == nil {
+			st = withDetails
+		}
+	}
+
+	return st.Err()
+}
+
+func httpToGRPCCode(code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusGone:
+		return codes.OutOfRange
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusRequestEntityTooLarge:
+		return codes.ResourceExhausted
+	case http.StatusUnsupportedMediaType:
+		return codes.InvalidArgument
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
 }
@@ -0,0 +1,226 @@
+package pb_handler
+
+import (
+	"context"
+
+	pb "github.com/demkowo/users/internal/generated"
+	model "github.com/demkowo/users/internal/models"
+	service "github.com/demkowo/users/internal/services"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type WebhooksServer struct {
+	Service service.Webhooks
+	pb.WebhooksServer
+}
+
+func (h *WebhooksServer) Add(ctx context.Context, req *pb.AddWebhookRequest) (*pb.AddWebhookResponse, error) {
+	log.Trace("Add webhook via gRPC")
+
+	webhook := &model.Webhook{
+		ID:         uuid.New(),
+		URL:        req.GetUrl(),
+		EventTypes: req.GetEventTypes(),
+		Secret:     req.GetSecret(),
+		Active:     req.Active == nil || req.GetActive(),
+	}
+
+	if err := h.Service.Add(ctx, webhook); err != nil {
+		log.Errorf("Failed to add webhook: %v", err)
+		return nil, toGRPCError(err)
+	}
+
+	return &pb.AddWebhookResponse{
+		Webhook: toProtoWebhook(webhook),
+	}, nil
+}
+
+func (h *WebhooksServer) Delete(ctx context.Context, req *pb.DeleteWebhookRequest) (*pb.DeleteWebhookResponse, error) {
+	log.Trace("Delete webhook via gRPC")
+
+	webhookID, err := uuid.Parse(req.GetWebhookId())
+	if err != nil {
+		log.Errorf("Invalid webhook ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid webhook id")
+	}
+
+	if e := h.Service.Delete(ctx, webhookID); e != nil {
+		log.Errorf("Failed to delete webhook: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.DeleteWebhookResponse{}, nil
+}
+
+func (h *WebhooksServer) GetById(ctx context.Context, req *pb.GetWebhookByIdRequest) (*pb.GetWebhookByIdResponse, error) {
+	log.Trace("Get webhook by ID via gRPC")
+
+	webhookID, err := uuid.Parse(req.GetWebhookId())
+	if err != nil {
+		log.Errorf("Invalid webhook ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid webhook id")
+	}
+
+	webhook, e := h.Service.GetByID(ctx, webhookID)
+	if e != nil {
+		log.Errorf("Failed to get webhook: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.GetWebhookByIdResponse{
+		Webhook: toProtoWebhook(webhook),
+	}, nil
+}
+
+func (h *WebhooksServer) List(ctx context.Context, req *pb.ListWebhooksRequest) (*pb.ListWebhooksResponse, error) {
+	log.Trace("List webhooks via gRPC")
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = 10
+	}
+
+	found, total, e := h.Service.List(ctx, limit, req.GetOffset())
+	if e != nil {
+		log.Errorf("Failed to list webhooks: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	webhooks := make([]*pb.Webhook, 0, len(found))
+	for i := range found {
+		webhooks = append(webhooks, toProtoWebhook(&found[i]))
+	}
+
+	return &pb.ListWebhooksResponse{
+		Webhooks: webhooks,
+		Total:    total,
+	}, nil
+}
+
+func (h *WebhooksServer) ListDeliveries(ctx context.Context, req *pb.ListWebhookDeliveriesRequest) (*pb.ListWebhookDeliveriesResponse, error) {
+	log.Trace("List webhook deliveries via gRPC")
+
+	webhookID, err := uuid.Parse(req.GetWebhookId())
+	if err != nil {
+		log.Errorf("Invalid webhook ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid webhook id")
+	}
+
+	limit := req.GetLimit()
+	if limit <= 0 {
+		limit = 10
+	}
+
+	found, total, e := h.Service.ListDeliveries(ctx, webhookID, limit, req.GetOffset())
+	if e != nil {
+		log.Errorf("Failed to list webhook deliveries: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	deliveries := make([]*pb.WebhookDelivery, 0, len(found))
+	for i := range found {
+		deliveries = append(deliveries, toProtoWebhookDelivery(&found[i]))
+	}
+
+	return &pb.ListWebhookDeliveriesResponse{
+		Deliveries: deliveries,
+		Total:      total,
+	}, nil
+}
+
+func (h *WebhooksServer) Redeliver(ctx context.Context, req *pb.RedeliverWebhookRequest) (*pb.RedeliverWebhookResponse, error) {
+	log.Trace("Redeliver webhook via gRPC")
+
+	webhookID, err := uuid.Parse(req.GetWebhookId())
+	if err != nil {
+		log.Errorf("Invalid webhook ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid webhook id")
+	}
+
+	deliveryID, err := uuid.Parse(req.GetDeliveryId())
+	if err != nil {
+		log.Errorf("Invalid delivery ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid delivery id")
+	}
+
+	delivery, e := h.Service.Redeliver(ctx, webhookID, deliveryID)
+	if e != nil {
+		log.Errorf("Failed to redeliver webhook: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.RedeliverWebhookResponse{
+		Delivery: toProtoWebhookDelivery(delivery),
+	}, nil
+}
+
+func (h *WebhooksServer) Update(ctx context.Context, req *pb.UpdateWebhookRequest) (*pb.UpdateWebhookResponse, error) {
+	log.Trace("Update webhook via gRPC")
+
+	webhookID, err := uuid.Parse(req.GetWebhookId())
+	if err != nil {
+		log.Errorf("Invalid webhook ID: %v", err)
+		return nil, status.Error(codes.InvalidArgument, "invalid webhook id")
+	}
+
+	webhook := &model.Webhook{
+		ID:         webhookID,
+		URL:        req.GetUrl(),
+		EventTypes: req.GetEventTypes(),
+		Secret:     req.GetSecret(),
+		Active:     req.Active == nil || req.GetActive(),
+	}
+
+	if e := h.Service.Update(ctx, webhook); e != nil {
+		log.Errorf("Failed to update webhook: %v", e)
+		return nil, toGRPCError(e)
+	}
+
+	return &pb.UpdateWebhookResponse{
+		Webhook: toProtoWebhook(webhook),
+	}, nil
+}
+
+func toProtoWebhook(w *model.Webhook) *pb.Webhook {
+	if w == nil {
+		return nil
+	}
+
+	return &pb.Webhook{
+		Id:         w.ID.String(),
+		Url:        w.URL,
+		EventTypes: w.EventTypes,
+		Secret:     w.Secret,
+		Active:     w.Active,
+		Created:    timestamppb.New(w.Created),
+		Updated:    timestamppb.New(w.Updated),
+	}
+}
+
+func toProtoWebhookDelivery(d *model.WebhookDelivery) *pb.WebhookDelivery {
+	if d == nil {
+		return nil
+	}
+
+	delivery := &pb.WebhookDelivery{
+		Id:             d.ID.String(),
+		WebhookId:      d.WebhookID.String(),
+		EventType:      d.EventType,
+		Payload:        string(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttempt:    timestamppb.New(d.NextAttempt),
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		Created:        timestamppb.New(d.Created),
+	}
+	if d.Delivered != nil {
+		delivery.Delivered = timestamppb.New(*d.Delivered)
+	}
+
+	return delivery
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Transports recorded in the audit log.
+const (
+	AuditTransportGin      = "gin"
+	AuditTransportGRPC     = "grpc"
+	AuditTransportInternal = "internal"
+)
+
+// Operations recorded in the audit log.
+const (
+	AuditOpAdd            = "add"
+	AuditOpImport         = "import"
+	AuditOpChangeNickname = "change_nickname"
+	AuditOpUpdate         = "update"
+	AuditOpPatch          = "patch"
+	AuditOpUpdateImg      = "update_img"
+	AuditOpDelete         = "delete"
+	AuditOpRestore        = "restore"
+	AuditOpPurge          = "purge"
+	AuditOpJoinClub       = "join_club"
+	AuditOpSetClubRole    = "set_club_role"
+)
+
+// maxAuditActorLen is the size, in bytes, of the audit log actor columns.
+const maxAuditActorLen = 255
+
+// AuditActor is who made a change, from which address and through which
+// transport. The ID is claimed by the caller; the Addr is where the request
+// came from.
+type AuditActor struct {
+	ID        string
+	Addr      string
+	Transport string
+}
+
+// NewAuditActor returns the actor with the given ID and Addr made valid UTF-8
+// and cut to fit the audit log.
+func NewAuditActor(id, addr, transport string) AuditActor {
+	return AuditActor{
+		ID:        auditActorValue(id),
+		Addr:      auditActorValue(addr),
+		Transport: transport,
+	}
+}
+
+// auditActorValue makes s valid UTF-8 of at most maxAuditActorLen bytes.
+func auditActorValue(s string) string {
+	s = strings.ToValidUTF8(strings.TrimSpace(s), string(utf8.RuneError))
+	if len(s) <= maxAuditActorLen {
+		return s
+	}
+	n := maxAuditActorLen
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+type auditActorKey struct{}
+
+// WithAuditActor returns a copy of ctx carrying actor.
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActorFrom returns the actor carried by ctx. Changes made outside of a
+// request are attributed to the "system" actor of the internal transport.
+func AuditActorFrom(ctx context.Context) AuditActor {
+	if actor, ok := ctx.Value(auditActorKey{}).(AuditActor); ok {
+		return actor
+	}
+	return AuditActor{ID: "system", Transport: AuditTransportInternal}
+}
+
+// AuditChange is the old and new value of a changed field.
+type AuditChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// AuditEntry records a change of a user. Before is nil for a new user and
+// After for a purged one; Changes holds the fields that differ between them.
+type AuditEntry struct {
+	ID        uuid.UUID              `json:"id"`
+	UserID    uuid.UUID              `json:"user_id"`
+	Actor     string                 `json:"actor"`
+	ActorAddr string                 `json:"actor_addr"`
+	Transport string                 `json:"transport"`
+	Operation string                 `json:"operation"`
+	Before    *User                  `json:"before"`
+	After     *User                  `json:"after"`
+	Changes   map[string]AuditChange `json:"changes"`
+	Created   time.Time              `json:"created"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNewAuditActor(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{name: "short", id: "admin", want: "admin"},
+		{name: "padded", id: "  admin  ", want: "admin"},
+		{name: "empty", id: "", want: ""},
+		{name: "invalid UTF-8", id: "ad\xffmin", want: "ad�min"},
+		{name: "at the limit", id: strings.Repeat("a", 255), want: strings.Repeat("a", 255)},
+		{name: "over the limit", id: strings.Repeat("a", 300), want: strings.Repeat("a", 255)},
+		{name: "character across the limit", id: strings.Repeat("a", 254) + "ż", want: strings.Repeat("a", 254)},
+		{name: "characters over the limit", id: strings.Repeat("ż", 200), want: strings.Repeat("ż", 127)},
+		{name: "invalid UTF-8 over the limit", id: strings.Repeat("a\xff", 100), want: strings.Repeat("a�", 63) + "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewAuditActor(tt.id, tt.id, AuditTransportGin)
+			if got.ID != tt.want || got.Addr != tt.want {
+				t.Errorf("NewAuditActor(%q) = %q, %q, want %q", tt.id, got.ID, got.Addr, tt.want)
+			}
+			if !utf8.ValidString(got.ID) || len(got.ID) > maxAuditActorLen {
+				t.Errorf("ID %q is not valid UTF-8 of at most %d bytes", got.ID, maxAuditActorLen)
+			}
+			if got.Transport != AuditTransportGin {
+				t.Errorf("transport = %q, want %q", got.Transport, AuditTransportGin)
+			}
+		})
+	}
+}
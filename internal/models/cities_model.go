@@ -0,0 +1,161 @@
+package model
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// earthRadiusKm is the mean radius of the Earth.
+const earthRadiusKm = 6371.0088
+
+// City is a city of the gazetteer bundled in cities.csv. Users refer to it by
+// ID, which never changes.
+type City struct {
+	ID        string   `json:"id"`
+	Country   string   `json:"country"`
+	Name      string   `json:"name"`
+	Aliases   []string `json:"-"`
+	Latitude  float64  `json:"latitude"`
+	Longitude float64  `json:"longitude"`
+}
+
+// NearbyCity is a city and how far, in kilometers, it is from a point.
+type NearbyCity struct {
+	City
+	DistanceKm float64
+}
+
+// NearbyUser is a user found near a point and how far, in kilometers, their
+// city is from it.
+type NearbyUser struct {
+	User
+	DistanceKm float64 `json:"distance_km"`
+}
+
+//go:embed cities.csv
+var citiesCSV []byte
+
+var (
+	citiesOnce  sync.Once
+	cities      []City
+	cityIDs     map[string]*City
+	cityByNames map[string][]*City
+)
+
+// ParseCity returns the city of the gazetteer named name, in English, in the
+// local language or by another known name; names are compared like country
+// names (see ParseCountry). When country, an alpha-2 code, is given the city
+// must be in it; otherwise the name must belong to a single city.
+func ParseCity(name, country string) (City, bool) {
+	loadCities()
+
+	var found *City
+	for _, c := range cityByNames[placeNameKey(name)] {
+		if country != "" && c.Country != country {
+			continue
+		}
+		if found != nil {
+			return City{}, false
+		}
+		found = c
+	}
+	if found == nil {
+		return City{}, false
+	}
+
+	return *found, true
+}
+
+// CityByID returns the city of the gazetteer with the ID.
+func CityByID(id string) (City, bool) {
+	loadCities()
+
+	c, ok := cityIDs[id]
+	if !ok {
+		return City{}, false
+	}
+	return *c, true
+}
+
+// CitiesNear returns the cities of the gazetteer at most radiusKm kilometers
+// from the point, nearest first.
+func CitiesNear(lat, lon, radiusKm float64) []NearbyCity {
+	loadCities()
+
+	var near []NearbyCity
+	for _, c := range cities {
+		if d := DistanceKm(lat, lon, c.Latitude, c.Longitude); d <= radiusKm {
+			near = append(near, NearbyCity{City: c, DistanceKm: d})
+		}
+	}
+
+	sort.Slice(near, func(i, j int) bool {
+		if near[i].DistanceKm != near[j].DistanceKm {
+			return near[i].DistanceKm < near[j].DistanceKm
+		}
+		return near[i].ID < near[j].ID
+	})
+
+	return near
+}
+
+// DistanceKm returns the great-circle distance between two points, in
+// kilometers, by the haversine formula.
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := math.Pi / 180
+	dLat := (lat2 - lat1) * rad
+	dLon := (lon2 - lon1) * rad
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func loadCities() {
+	citiesOnce.Do(func() {
+		records, err := csv.NewReader(bytes.NewReader(citiesCSV)).ReadAll()
+		if err != nil {
+			panic("invalid cities.csv: " + err.Error())
+		}
+
+		cities = make([]City, 0, len(records)-1)
+		for _, r := range records[1:] {
+			lat, err := strconv.ParseFloat(r[4], 64)
+			if err != nil {
+				panic("invalid cities.csv: " + err.Error())
+			}
+			lon, err := strconv.ParseFloat(r[5], 64)
+			if err != nil {
+				panic("invalid cities.csv: " + err.Error())
+			}
+
+			c := City{ID: r[0], Country: r[1], Name: r[2], Latitude: lat, Longitude: lon}
+			if r[3] != "" {
+				c.Aliases = strings.Split(r[3], "|")
+			}
+			cities = append(cities, c)
+		}
+
+		cityIDs = make(map[string]*City, len(cities))
+		cityByNames = make(map[string][]*City)
+		for i := range cities {
+			c := &cities[i]
+			cityIDs[c.ID] = c
+
+			seen := make(map[string]bool, 1+len(c.Aliases))
+			for _, n := range append([]string{c.Name}, c.Aliases...) {
+				key := placeNameKey(n)
+				if key == "" || seen[key] {
+					continue
+				}
+				seen[key] = true
+				cityByNames[key] = append(cityByNames[key], c)
+			}
+		}
+	})
+}
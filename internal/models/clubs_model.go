@@ -0,0 +1,86 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Roles of club members. Owners and moderators curate the club. The first
+// member of a club becomes its owner, and a club with members always has at
+// least one.
+const (
+	ClubRoleMember    = "member"
+	ClubRoleModerator = "moderator"
+	ClubRoleOwner     = "owner"
+)
+
+// ClubRoles are the roles of club members, from the least to the most
+// privileged.
+var ClubRoles = []string{ClubRoleMember, ClubRoleModerator, ClubRoleOwner}
+
+// Join policies of clubs. Anyone can join an open club; an invite-only club
+// is only joined through an accepted invitation or join request.
+const (
+	ClubJoinOpen       = "open"
+	ClubJoinInviteOnly = "invite_only"
+)
+
+// ClubJoinPolicies are the join policies of clubs.
+var ClubJoinPolicies = []string{ClubJoinOpen, ClubJoinInviteOnly}
+
+// Kinds of club requests: a user asking to join a club, or a club inviting a
+// user.
+const (
+	ClubRequestJoin       = "join"
+	ClubRequestInvitation = "invitation"
+)
+
+// ClubRequestKinds are the kinds of club requests.
+var ClubRequestKinds = []string{ClubRequestJoin, ClubRequestInvitation}
+
+// Statuses of club requests. A request is pending until it is accepted or
+// rejected, or until it expires.
+const (
+	ClubRequestPending  = "pending"
+	ClubRequestAccepted = "accepted"
+	ClubRequestRejected = "rejected"
+	ClubRequestExpired  = "expired"
+)
+
+// ClubRequestStatuses are the statuses of club requests.
+var ClubRequestStatuses = []string{ClubRequestPending, ClubRequestAccepted, ClubRequestRejected, ClubRequestExpired}
+
+// ClubMember is a user listed as a member of a club, with their role in it
+// and when they joined.
+type ClubMember struct {
+	User
+	Role   string    `json:"role"`
+	Joined time.Time `json:"joined"`
+}
+
+// ClubRequest is a join request, which the club's owners accept or reject, or
+// an invitation, which the invited user accepts or rejects. Accepting either
+// makes the user a member of the club. CreatedBy and DecidedBy are the audit
+// actors (see AuditActor) who sent the request and who decided on it.
+type ClubRequest struct {
+	ID        uuid.UUID  `json:"id"`
+	ClubID    uuid.UUID  `json:"club_id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Kind      string     `json:"kind"`
+	Status    string     `json:"status"`
+	CreatedBy string     `json:"created_by"`
+	DecidedBy string     `json:"decided_by,omitempty"`
+	Created   time.Time  `json:"created"`
+	Expires   time.Time  `json:"expires"`
+	Decided   *time.Time `json:"decided,omitempty"`
+}
+
+// ClubRequestFilter narrows down club request listings. Zero-valued fields
+// are ignored and the remaining ones are combined with AND.
+type ClubRequestFilter struct {
+	ClubID uuid.UUID
+	UserID uuid.UUID
+	Kind   string
+	Status string
+}
@@ -0,0 +1,198 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Country is an ISO 3166-1 country. Users store the Alpha2 code.
+type Country struct {
+	Alpha2  string
+	Alpha3  string
+	Numeric string
+	Name    string
+	// Aliases are other English names the country is known by.
+	Aliases []string
+}
+
+//go:embed countries.csv
+var countriesCSV []byte
+
+var (
+	countriesOnce sync.Once
+	countries     []Country
+	countryCodes  map[string]*Country
+	countryNames  map[string]string
+)
+
+// Countries returns the ISO 3166-1 countries, ordered by code.
+func Countries() []Country {
+	loadCountries()
+	return countries
+}
+
+// ParseCountry returns the alpha-2 code of the country s names. s may be an
+// alpha-2, alpha-3 or numeric code, or the name of the country in English or
+// any language it has a translation in; case, accents, spaces and punctuation
+// are ignored, so "PL", "pol", "616", "Poland" and "polska" all give "PL".
+func ParseCountry(s string) (string, bool) {
+	loadCountries()
+
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", false
+	}
+	if c, ok := countryCodes[strings.ToUpper(s)]; ok {
+		return c.Alpha2, true
+	}
+	if code, ok := countryNames[placeNameKey(s)]; ok {
+		return code, true
+	}
+
+	return "", false
+}
+
+// CountryName returns the name of the country with the alpha-2 code in the
+// language of locale, falling back to English. Unknown codes are returned as
+// they are.
+func CountryName(code string, locale language.Tag) string {
+	loadCountries()
+
+	c, ok := countryCodes[code]
+	if !ok || len(code) != 2 {
+		return code
+	}
+	if names := display.Regions(locale); names != nil {
+		if region, err := language.ParseRegion(c.Alpha2); err == nil {
+			if name := names.Name(region); name != "" {
+				return name
+			}
+		}
+	}
+
+	return c.Name
+}
+
+// ParseLocale returns the language country names can be given in that best
+// matches s, a language tag or an Accept-Language header value. It returns
+// language.English when s names no language, or none that is supported.
+func ParseLocale(s string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(s)
+	if err != nil || len(tags) == 0 {
+		return language.English
+	}
+
+	_, i, confidence := localeMatcher().Match(tags...)
+	if confidence == language.No {
+		return language.English
+	}
+
+	return display.Supported.Tags()[i]
+}
+
+var localeMatcher = sync.OnceValue(func() language.Matcher {
+	return language.NewMatcher(display.Supported.Tags())
+})
+
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx carrying the language of the response.
+func WithLocale(ctx context.Context, locale language.Tag) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFrom returns the language carried by ctx, or English.
+func LocaleFrom(ctx context.Context) language.Tag {
+	if locale, ok := ctx.Value(localeKey{}).(language.Tag); ok {
+		return locale
+	}
+	return language.English
+}
+
+func loadCountries() {
+	countriesOnce.Do(func() {
+		records, err := csv.NewReader(bytes.NewReader(countriesCSV)).ReadAll()
+		if err != nil {
+			panic("invalid countries.csv: " + err.Error())
+		}
+
+		countries = make([]Country, 0, len(records)-1)
+		for _, r := range records[1:] {
+			c := Country{Alpha2: r[0], Alpha3: r[1], Numeric: r[2], Name: r[3]}
+			if r[4] != "" {
+				c.Aliases = strings.Split(r[4], "|")
+			}
+			countries = append(countries, c)
+		}
+
+		countryCodes = make(map[string]*Country, 3*len(countries))
+		countryNames = make(map[string]string)
+		for i := range countries {
+			c := &countries[i]
+			countryCodes[c.Alpha2] = c
+			countryCodes[c.Alpha3] = c
+			countryCodes[c.Numeric] = c
+		}
+
+		// English names and aliases come first, so they win over a translation
+		// that happens to be spelled the same as another country.
+		for i := range countries {
+			c := &countries[i]
+			addCountryName(c.Alpha2, c.Name)
+			for _, a := range c.Aliases {
+				addCountryName(c.Alpha2, a)
+			}
+		}
+		for _, tag := range display.Supported.Tags() {
+			names := display.Regions(tag)
+			if names == nil {
+				continue
+			}
+			for i := range countries {
+				c := &countries[i]
+				if region, err := language.ParseRegion(c.Alpha2); err == nil {
+					addCountryName(c.Alpha2, names.Name(region))
+				}
+			}
+		}
+	})
+}
+
+func addCountryName(code, name string) {
+	for _, n := range []string{name, expandSaint(name)} {
+		key := placeNameKey(n)
+		if _, ok := countryNames[key]; key == "" || ok {
+			continue
+		}
+		countryNames[key] = code
+	}
+}
+
+// expandSaint spells out a leading "St.", as in "St. Lucia".
+func expandSaint(name string) string {
+	if rest, ok := strings.CutPrefix(name, "St. "); ok {
+		return "Saint " + rest
+	}
+	return name
+}
+
+// placeNameKey folds case and drops accents and all but letters and digits.
+func placeNameKey(name string) string {
+	name = strings.ReplaceAll(name, "&", "and")
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, norm.NFD.String(cases.Fold().String(name)))
+}
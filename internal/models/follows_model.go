@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Follow is a user following another one since a point in time.
+type Follow struct {
+	FollowerID uuid.UUID `json:"follower_id"`
+	FolloweeID uuid.UUID `json:"followee_id"`
+	Since      time.Time `json:"since"`
+}
+
+// FollowUser is a user listed among the followers of another user, or among
+// the users another user follows, and since when the follow holds.
+type FollowUser struct {
+	User
+	Since time.Time `json:"since"`
+}
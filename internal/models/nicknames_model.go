@@ -0,0 +1,52 @@
+package model
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NicknameKey returns the canonical form of a nickname: NFKC-normalized and
+// case-folded, so "Ania", "ANIA" and "Ａｎｉａ" share one key. Nicknames are
+// unique and looked up by key.
+func NicknameKey(nickname string) string {
+	return norm.NFKC.String(cases.Fold().String(norm.NFKC.String(strings.TrimSpace(nickname))))
+}
+
+// NicknameSkeleton returns what a nickname looks like: the characters that are
+// easily mistaken for a Latin letter or digit are replaced with it before
+// case folding, so "Ania" and "Аnia" (with a Cyrillic A) share a skeleton.
+// A nickname whose skeleton belongs to another user is refused as confusable.
+func NicknameSkeleton(nickname string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(strings.TrimSpace(nickname)) {
+		if p, ok := nicknameConfusables[r]; ok {
+			b.WriteRune(p)
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	folded := cases.Fold().String(b.String())
+	return norm.NFD.String(nicknameConfusableSequences.Replace(folded))
+}
+
+// nicknameConfusables maps characters to the Latin letter or digit they pass for.
+var nicknameConfusables = map[rune]rune{
+	// Latin and digits
+	'0': 'o', '1': 'l', 'I': 'l', 'ı': 'i', 'ȷ': 'j', 'ɑ': 'a', 'ɡ': 'g', 'ɩ': 'i',
+	// Cyrillic
+	'А': 'A', 'а': 'a', 'В': 'B', 'Е': 'E', 'е': 'e', 'Ѕ': 'S', 'ѕ': 's', 'І': 'l',
+	'і': 'i', 'Ј': 'J', 'ј': 'j', 'К': 'K', 'к': 'k', 'М': 'M', 'Н': 'H', 'О': 'O',
+	'о': 'o', 'Р': 'P', 'р': 'p', 'С': 'C', 'с': 'c', 'Т': 'T', 'У': 'Y', 'у': 'y',
+	'Х': 'X', 'х': 'x', 'Ү': 'Y', 'ү': 'y', 'Ӏ': 'l', 'ӏ': 'l', 'ԁ': 'd', 'ԛ': 'q',
+	'ԝ': 'w', 'Ԛ': 'Q', 'Ԝ': 'W',
+	// Greek
+	'Α': 'A', 'α': 'a', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'l', 'ι': 'i',
+	'Κ': 'K', 'κ': 'k', 'Μ': 'M', 'Ν': 'N', 'ν': 'v', 'Ο': 'O', 'ο': 'o', 'Ρ': 'P',
+	'ρ': 'p', 'Τ': 'T', 'Υ': 'Y', 'υ': 'u', 'Χ': 'X', 'χ': 'x', 'ϲ': 'c', 'ϳ': 'j',
+}
+
+// nicknameConfusableSequences replaces pairs passing for one letter, and l with i as I folds to i.
+var nicknameConfusableSequences = strings.NewReplacer("rn", "m", "vv", "w", "l", "i")
@@ -0,0 +1,61 @@
+package model
+
+import "testing"
+
+func TestNicknameKey(t *testing.T) {
+	tests := []struct {
+		nickname string
+		want     string
+	}{
+		{nickname: "admin", want: "admin"},
+		{nickname: "Admin", want: "admin"},
+		{nickname: "ADMIN", want: "admin"},
+		{nickname: "  Admin  ", want: "admin"},
+		{nickname: "Ａｄｍｉｎ", want: "admin"},
+		{nickname: "ＡＤＭＩＮ", want: "admin"},
+		{nickname: "Straße", want: "strasse"},
+		{nickname: "Ǆemal", want: "džemal"},
+		{nickname: "аdmin", want: "аdmin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.nickname, func(t *testing.T) {
+			if got := NicknameKey(tt.nickname); got != tt.want {
+				t.Errorf("NicknameKey(%q) = %q, want %q", tt.nickname, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNicknameSkeleton(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{name: "case", a: "Admin", b: "ADMIN", same: true},
+		{name: "case with an L", a: "LISA", b: "lisa", same: true},
+		{name: "upper case and Cyrillic a", a: "ADMIN", b: "аdmin", same: true},
+		{name: "fullwidth", a: "Ａｄｍｉｎ", b: "admin", same: true},
+		{name: "Cyrillic a", a: "аdmin", b: "admin", same: true},
+		{name: "Cyrillic capital A", a: "Аdmin", b: "admin", same: true},
+		{name: "Greek omicron", a: "rοot", b: "root", same: true},
+		{name: "digits passing for letters", a: "r00t", b: "root", same: true},
+		{name: "capital I passing for l", a: "AIice", b: "alice", same: true},
+		{name: "rn passing for m", a: "rnary", b: "mary", same: true},
+		{name: "vv passing for w", a: "vvendy", b: "wendy", same: true},
+		{name: "surrounding spaces", a: " admin ", b: "admin", same: true},
+		{name: "different letters", a: "admin", b: "odmin", same: false},
+		{name: "accents are kept", a: "éva", b: "eva", same: false},
+		{name: "separators are kept", a: "ad_min", b: "admin", same: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := NicknameSkeleton(tt.a), NicknameSkeleton(tt.b)
+			if (a == b) != tt.same {
+				t.Errorf("NicknameSkeleton(%q) = %q, NicknameSkeleton(%q) = %q, want same = %t", tt.a, a, tt.b, b, tt.same)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent is a user change recorded in the outbox, waiting to be
+// delivered. Type is one of the UserEvent* types and Payload is the JSON of
+// the user after the change; its version tells redelivered or reordered
+// events apart.
+type OutboxEvent struct {
+	ID       int64           `json:"id"`
+	UserID   uuid.UUID       `json:"user_id"`
+	Type     string          `json:"type"`
+	Payload  json.RawMessage `json:"payload"`
+	Created  time.Time       `json:"created"`
+	Attempts int32           `json:"attempts"`
+}
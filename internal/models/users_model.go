@@ -6,19 +6,107 @@ import (
 	"github.com/google/uuid"
 )
 
+// User is a user of the service. Country is an ISO 3166-1 alpha-2 code;
+// CountryName is its name in the language of the request and is only set on
+// what is returned to clients. CityID is the gazetteer city (see City) that
+// City and Country resolve to, if any; it is kept by the repository.
+// Followers and Following count the active users following the user and
+// followed by them; they are kept by the repository too.
 type User struct {
-	ID       uuid.UUID `json:"id"`
+	ID          uuid.UUID `json:"id"`
+	Nickname    string    `json:"nickname"`
+	Img         string    `json:"img"`
+	Country     string    `json:"country"`
+	CountryName string    `json:"country_name,omitempty"`
+	City        string    `json:"city"`
+	CityID      string    `json:"city_id,omitempty"`
+	Clubs       []Club    `json:"clubs"`
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+	Deleted     bool      `json:"deleted"`
+	Version     int32     `json:"version"`
+	Followers   int32     `json:"followers"`
+	Following   int32     `json:"following"`
+}
+
+// Avatar is where a user's image can be downloaded from. Variants holds the
+// URLs of the square thumbnails of uploaded avatars, by side in pixels.
+// Generated is set for the identicons of users without an image.
+type Avatar struct {
+	Img       string         `json:"img"`
+	URL       string         `json:"url"`
+	Variants  map[int]string `json:"variants,omitempty"`
+	Generated bool           `json:"generated,omitempty"`
+}
+
+// FieldError is a cause of a rejected request that names the input field at
+// fault.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Names of the user fields that can be changed by a partial update. They
+// match the JSON keys and the proto field names.
+const (
+	UserFieldCountry = "country"
+	UserFieldCity    = "city"
+	UserFieldClubs   = "clubs"
+)
+
+// Types of UserEvent.
+const (
+	UserEventCreated      = "created"
+	UserEventUpdated      = "updated"
+	UserEventImageChanged = "image_changed"
+	UserEventDeleted      = "deleted"
+)
+
+// UserEvent describes a change of a user, carrying the user as it is after the
+// change. IDs grow monotonically.
+type UserEvent struct {
+	ID     uint64    `json:"id"`
+	Type   string    `json:"type"`
+	UserID uuid.UUID `json:"user_id"`
+	User   User      `json:"user"`
+	Time   time.Time `json:"time"`
+}
+
+// ImportResult is the outcome of a single row of a bulk import. Row is 1-based.
+type ImportResult struct {
+	Row      int       `json:"row"`
 	Nickname string    `json:"nickname"`
-	Img      string    `json:"img"`
-	Country  string    `json:"country"`
-	City     string    `json:"city"`
-	Clubs    []Club    `json:"clubs"`
-	Created  time.Time `json:"created"`
-	Updated  time.Time `json:"updated"`
-	Deleted  bool      `json:"deleted"`
+	UserID   uuid.UUID `json:"user_id,omitempty"`
+	Error    string    `json:"error,omitempty"`
 }
 
+// ImportReport summarizes a bulk import, with one result per row in input order.
+type ImportReport struct {
+	Total    int            `json:"total"`
+	Imported int            `json:"imported"`
+	Failed   int            `json:"failed"`
+	Results  []ImportResult `json:"results"`
+}
+
+// Club is a club, as listed on its own or among the clubs of a user. In the
+// latter case Role and Joined describe the user's membership. JoinPolicy is
+// one of ClubJoinPolicies.
 type Club struct {
-	ID   uuid.UUID `json:"id"`
-	Name string    `json:"name"`
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	JoinPolicy string     `json:"join_policy,omitempty"`
+	Role       string     `json:"role,omitempty"`
+	Joined     *time.Time `json:"joined,omitempty"`
+}
+
+// UserFilter narrows down user listings. Zero-valued fields are ignored and
+// the remaining ones are combined with AND.
+type UserFilter struct {
+	Country        string    `json:"country"`
+	City           string    `json:"city"`
+	ClubID         uuid.UUID `json:"club_id"`
+	ClubName       string    `json:"club_name"`
+	NicknamePrefix string    `json:"nickname_prefix"`
+	CreatedFrom    time.Time `json:"created_from"`
+	CreatedTo      time.Time `json:"created_to"`
 }
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook is a partner endpoint subscribed to user events. EventTypes holds
+// UserEvent* types. Secret signs the deliveries and is only shown when it is
+// set.
+type Webhook struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret,omitempty"`
+	Active     bool      `json:"active"`
+	Created    time.Time `json:"created"`
+	Updated    time.Time `json:"updated"`
+}
+
+// Statuses of WebhookDelivery.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is one event sent (or to be sent) to a webhook, along with
+// the outcome of its last attempt. Delivered is set once it has succeeded.
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id"`
+	WebhookID      uuid.UUID       `json:"webhook_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int32           `json:"attempts"`
+	NextAttempt    time.Time       `json:"next_attempt"`
+	LastStatusCode int32           `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	Created        time.Time       `json:"created"`
+	Delivered      *time.Time      `json:"delivered,omitempty"`
+}
@@ -0,0 +1,114 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/demkowo/utils/resp"
+)
+
+// BlobStore keeps binary objects, such as avatars, under slash-separated keys.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) *resp.Err
+	Get(ctx context.Context, key string) (io.ReadCloser, *resp.Err)
+	Delete(ctx context.Context, key string) *resp.Err
+}
+
+type blobStore struct {
+	root string
+}
+
+// NewBlobStore returns a BlobStore keeping every object in a file under root,
+// at the path given by its key.
+func NewBlobStore(root string) BlobStore {
+	return &blobStore{
+		root: root,
+	}
+}
+
+// Put writes the object to a temporary file first and moves it into place
+// once complete, so readers never see a partial object.
+func (s *blobStore) Put(ctx context.Context, key string, r io.Reader) *resp.Err {
+	path, err := s.path(key)
+	if err != nil {
+		return resp.Error(http.StatusBadRequest, "failed to store blob", []interface{}{err.Error()})
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return resp.Error(http.StatusInternalServerError, "failed to store blob", []interface{}{err.Error()})
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return resp.Error(http.StatusInternalServerError, "failed to store blob", []interface{}{err.Error()})
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return resp.Error(http.StatusInternalServerError, "failed to store blob", []interface{}{err.Error()})
+	}
+	if err := tmp.Close(); err != nil {
+		return resp.Error(http.StatusInternalServerError, "failed to store blob", []interface{}{err.Error()})
+	}
+	if err := ctx.Err(); err != nil {
+		return resp.Error(http.StatusInternalServerError, "failed to store blob", []interface{}{err.Error()})
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return resp.Error(http.StatusInternalServerError, "failed to store blob", []interface{}{err.Error()})
+	}
+
+	return nil
+}
+
+func (s *blobStore) Get(ctx context.Context, key string) (io.ReadCloser, *resp.Err) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, resp.Error(http.StatusBadRequest, "failed to get blob", []interface{}{err.Error()})
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, resp.Error(http.StatusNotFound, "failed to get blob", []interface{}{"blob not found"})
+		}
+		return nil, resp.Error(http.StatusInternalServerError, "failed to get blob", []interface{}{err.Error()})
+	}
+
+	return f, nil
+}
+
+// Delete removes the object; deleting a missing one is not an error.
+func (s *blobStore) Delete(ctx context.Context, key string) *resp.Err {
+	path, err := s.path(key)
+	if err != nil {
+		return resp.Error(http.StatusBadRequest, "failed to delete blob", []interface{}{err.Error()})
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return resp.Error(http.StatusInternalServerError, "failed to delete blob", []interface{}{err.Error()})
+	}
+
+	return nil
+}
+
+// path maps key to a file under root, rejecting keys that would escape it.
+func (s *blobStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", errors.New("invalid blob key")
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." || strings.HasPrefix(part, ".") {
+			return "", errors.New("invalid blob key")
+		}
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
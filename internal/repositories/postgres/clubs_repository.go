@@ -0,0 +1,620 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	model "github.com/demkowo/users/internal/models"
+	"github.com/demkowo/users/internal/repositories/postgres/sqlc"
+	"github.com/demkowo/utils/resp"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type Clubs interface {
+	Add(ctx context.Context, club model.Club) (model.Club, *resp.Err)
+	AddRequest(ctx context.Context, request model.ClubRequest) (model.ClubRequest, *model.User, *resp.Err)
+	DecideRequest(ctx context.Context, clubID, requestID uuid.UUID, status string) (model.ClubRequest, *model.User, *resp.Err)
+	Delete(ctx context.Context, id uuid.UUID) (model.Club, *resp.Err)
+	GetByID(ctx context.Context, id uuid.UUID) (model.Club, *resp.Err)
+	List(ctx context.Context, limit, offset int32) ([]model.Club, int64, *resp.Err)
+	ListMembers(ctx context.Context, clubID uuid.UUID, limit, offset int32) ([]model.ClubMember, int64, *resp.Err)
+	ListRequests(ctx context.Context, filter model.ClubRequestFilter, limit, offset int32) ([]model.ClubRequest, int64, *resp.Err)
+	Rename(ctx context.Context, id uuid.UUID, name string) (model.Club, *resp.Err)
+	SetJoinPolicy(ctx context.Context, id uuid.UUID, policy string) (model.Club, *resp.Err)
+	SetMemberRole(ctx context.Context, clubID, userID uuid.UUID, role string) (model.ClubMember, bool, *resp.Err)
+}
+
+type clubs struct {
+	db *sql.DB
+	q  *sqlc.Queries
+}
+
+func NewClubs(db *sql.DB) Clubs {
+	return &clubs{
+		db: db,
+		q:  sqlc.New(db),
+	}
+}
+
+func (r *clubs) Add(ctx context.Context, club model.Club) (model.Club, *resp.Err) {
+	c, err := r.q.InsertClub(ctx, sqlc.InsertClubParams{
+		ID:         club.ID,
+		Name:       club.Name,
+		JoinPolicy: club.JoinPolicy,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return model.Club{}, resp.Error(http.StatusConflict, "failed to add club", []interface{}{"club name already exists"})
+		}
+		return model.Club{}, resp.Error(http.StatusInternalServerError, "failed to add club", []interface{}{err.Error()})
+	}
+
+	return clubToDomain(c), nil
+}
+
+// AddRequest records a join request or an invitation, expiring stale ones first. A join
+// request to an open club is accepted at once and the joined user returned.
+func (r *clubs) AddRequest(ctx context.Context, request model.ClubRequest) (model.ClubRequest, *model.User, *resp.Err) {
+	message := "failed to request to join club"
+	if request.Kind == model.ClubRequestInvitation {
+		message = "failed to invite user"
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	qtx := r.q.WithTx(tx)
+
+	if _, err := qtx.ExpireClubRequests(ctx); err != nil {
+		_ = tx.Rollback()
+		return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+	}
+
+	club, err := qtx.GetClubByID(ctx, request.ClubID)
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ClubRequest{}, nil, resp.Error(http.StatusNotFound, message, []interface{}{"club not found"})
+		}
+		return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+	}
+
+	if request.Kind == model.ClubRequestInvitation {
+		manager, err := isClubManager(ctx, qtx, request.ClubID)
+		if err != nil {
+			_ = tx.Rollback()
+			return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+		}
+		if !manager {
+			_ = tx.Rollback()
+			return model.ClubRequest{}, nil, resp.Error(http.StatusForbidden, message, []interface{}{"only owners and moderators of the club can invite users"})
+		}
+	}
+
+	before, err := lockedUser(ctx, qtx, request.UserID)
+	if err != nil || before == nil || before.Deleted {
+		_ = tx.Rollback()
+		if err == nil {
+			return model.ClubRequest{}, nil, resp.Error(http.StatusNotFound, message, []interface{}{"user not found"})
+		}
+		return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+	}
+
+	member, err := qtx.IsClubMember(ctx, sqlc.IsClubMemberParams{
+		ClubID: request.ClubID,
+		UserID: request.UserID,
+	})
+	if err != nil {
+		_ = tx.Rollback()
+		return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+	}
+	if member {
+		_ = tx.Rollback()
+		return model.ClubRequest{}, nil, resp.Error(http.StatusConflict, message, []interface{}{"user is already a member of the club"})
+	}
+
+	cr, err := qtx.InsertClubRequest(ctx, sqlc.InsertClubRequestParams{
+		ID:        request.ID,
+		ClubID:    request.ClubID,
+		UserID:    request.UserID,
+		Kind:      request.Kind,
+		CreatedBy: model.AuditActorFrom(ctx).ID,
+		ExpiresAt: request.Expires,
+	})
+	if err != nil {
+		_ = tx.Rollback()
+		if isUniqueViolation(err) {
+			return model.ClubRequest{}, nil, resp.Error(http.StatusConflict, message, []interface{}{"user already has a pending join request or invitation for the club"})
+		}
+		return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+	}
+
+	var joined *model.User
+	if cr.Kind == model.ClubRequestJoin && club.JoinPolicy == model.ClubJoinOpen {
+		changed, err := joinClub(ctx, qtx, *before, cr.ClubID)
+		if err == nil {
+			joined = &changed
+			cr, err = qtx.DecideClubRequest(ctx, sqlc.DecideClubRequestParams{
+				ID:        cr.ID,
+				Status:    model.ClubRequestAccepted,
+				DecidedBy: nullString(model.AuditActorFrom(ctx).ID),
+			})
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+	}
+
+	return clubRequestToDomain(cr), joined, nil
+}
+
+// DecideRequest accepts or rejects a pending request, returning the joined user on accept.
+func (r *clubs) DecideRequest(ctx context.Context, clubID, requestID uuid.UUID, status string) (model.ClubRequest, *model.User, *resp.Err) {
+	message := "failed to reject club request"
+	if status == model.ClubRequestAccepted {
+		message = "failed to accept club request"
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	qtx := r.q.WithTx(tx)
+
+	if _, err := qtx.ExpireClubRequests(ctx); err != nil {
+		_ = tx.Rollback()
+		return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+	}
+
+	cr, err := qtx.GetClubRequestForUpdate(ctx, requestID)
+	if err != nil || cr.ClubID != clubID {
+		_ = tx.Rollback()
+		if err == nil || errors.Is(err, sql.ErrNoRows) {
+			return model.ClubRequest{}, nil, resp.Error(http.StatusNotFound, message, []interface{}{"request not found"})
+		}
+		return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+	}
+	if cr.Status != model.ClubRequestPending {
+		_ = tx.Rollback()
+		return model.ClubRequest{}, nil, resp.Error(http.StatusConflict, message, []interface{}{fmt.Sprintf("request is already %s", cr.Status)})
+	}
+
+	allowed, cause, err := canDecideRequest(ctx, qtx, cr)
+	if err != nil {
+		_ = tx.Rollback()
+		return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+	}
+	if !allowed {
+		_ = tx.Rollback()
+		return model.ClubRequest{}, nil, resp.Error(http.StatusForbidden, message, []interface{}{cause})
+	}
+
+	var joined *model.User
+	if status == model.ClubRequestAccepted {
+		before, err := lockedUser(ctx, qtx, cr.UserID)
+		if err != nil || before == nil || before.Deleted {
+			_ = tx.Rollback()
+			if err == nil {
+				return model.ClubRequest{}, nil, resp.Error(http.StatusNotFound, message, []interface{}{"user not found"})
+			}
+			return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+		}
+
+		member, err := qtx.IsClubMember(ctx, sqlc.IsClubMemberParams{
+			ClubID: cr.ClubID,
+			UserID: cr.UserID,
+		})
+		if err == nil && !member {
+			var changed model.User
+			if changed, err = joinClub(ctx, qtx, *before, cr.ClubID); err == nil {
+				joined = &changed
+			}
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+		}
+	}
+
+	cr, err = qtx.DecideClubRequest(ctx, sqlc.DecideClubRequestParams{
+		ID:        cr.ID,
+		Status:    status,
+		DecidedBy: nullString(model.AuditActorFrom(ctx).ID),
+	})
+	if err != nil {
+		_ = tx.Rollback()
+		return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.ClubRequest{}, nil, resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+	}
+
+	return clubRequestToDomain(cr), joined, nil
+}
+
+func (r *clubs) Delete(ctx context.Context, id uuid.UUID) (model.Club, *resp.Err) {
+	c, err := r.q.DeleteClub(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Club{}, resp.Error(http.StatusNotFound, "failed to delete club", []interface{}{"club not found"})
+		}
+		return model.Club{}, resp.Error(http.StatusInternalServerError, "failed to delete club", []interface{}{err.Error()})
+	}
+
+	return clubToDomain(c), nil
+}
+
+func (r *clubs) GetByID(ctx context.Context, id uuid.UUID) (model.Club, *resp.Err) {
+	c, err := r.q.GetClubByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Club{}, resp.Error(http.StatusNotFound, "failed to get club", []interface{}{"club not found"})
+		}
+		return model.Club{}, resp.Error(http.StatusInternalServerError, "failed to get club", []interface{}{err.Error()})
+	}
+
+	return clubToDomain(c), nil
+}
+
+func (r *clubs) List(ctx context.Context, limit, offset int32) ([]model.Club, int64, *resp.Err) {
+	cs, err := r.q.ListClubs(ctx, sqlc.ListClubsParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list clubs", []interface{}{err.Error()})
+	}
+
+	total, err := r.q.CountClubs(ctx)
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list clubs", []interface{}{err.Error()})
+	}
+
+	return clubsToDomain(cs), total, nil
+}
+
+// ListMembers returns the active members of the club, owners first, then
+// moderators, then members, each by nickname.
+func (r *clubs) ListMembers(ctx context.Context, clubID uuid.UUID, limit, offset int32) ([]model.ClubMember, int64, *resp.Err) {
+	rows, err := r.q.ListClubMembers(ctx, sqlc.ListClubMembersParams{
+		ClubID: clubID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list club members", []interface{}{err.Error()})
+	}
+
+	total, err := r.q.CountClubMembers(ctx, clubID)
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list club members", []interface{}{err.Error()})
+	}
+
+	us := make([]sqlc.User, len(rows))
+	for i, m := range rows {
+		us[i] = sqlc.User{
+			ID:               m.ID,
+			Nickname:         m.Nickname,
+			Img:              m.Img,
+			Country:          m.Country,
+			City:             m.City,
+			CreatedAt:        m.CreatedAt,
+			UpdatedAt:        m.UpdatedAt,
+			Deleted:          m.Deleted,
+			Version:          m.Version,
+			NicknameKey:      m.NicknameKey,
+			NicknameSkeleton: m.NicknameSkeleton,
+			CityID:           m.CityID,
+			FollowerCount:    m.FollowerCount,
+			FollowingCount:   m.FollowingCount,
+		}
+	}
+
+	users, e := attachClubs(ctx, r.q, us)
+	if e != nil {
+		return nil, 0, e
+	}
+
+	members := make([]model.ClubMember, len(rows))
+	for i, m := range rows {
+		members[i] = model.ClubMember{
+			User:   users[i],
+			Role:   m.Role,
+			Joined: m.JoinedAt,
+		}
+	}
+
+	return members, total, nil
+}
+
+// ListRequests returns the join requests and invitations matching the filter,
+// newest first, after expiring the pending ones past their expiry.
+func (r *clubs) ListRequests(ctx context.Context, filter model.ClubRequestFilter, limit, offset int32) ([]model.ClubRequest, int64, *resp.Err) {
+	if _, err := r.q.ExpireClubRequests(ctx); err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list club requests", []interface{}{err.Error()})
+	}
+
+	crs, err := r.q.ListClubRequests(ctx, sqlc.ListClubRequestsParams{
+		ClubID: nullUUID(filter.ClubID),
+		UserID: nullUUID(filter.UserID),
+		Kind:   nullString(filter.Kind),
+		Status: nullString(filter.Status),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list club requests", []interface{}{err.Error()})
+	}
+
+	total, err := r.q.CountClubRequests(ctx, sqlc.CountClubRequestsParams{
+		ClubID: nullUUID(filter.ClubID),
+		UserID: nullUUID(filter.UserID),
+		Kind:   nullString(filter.Kind),
+		Status: nullString(filter.Status),
+	})
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list club requests", []interface{}{err.Error()})
+	}
+
+	requests := make([]model.ClubRequest, len(crs))
+	for i, cr := range crs {
+		requests[i] = clubRequestToDomain(cr)
+	}
+
+	return requests, total, nil
+}
+
+func (r *clubs) Rename(ctx context.Context, id uuid.UUID, name string) (model.Club, *resp.Err) {
+	c, err := r.q.RenameClub(ctx, sqlc.RenameClubParams{
+		ID:   id,
+		Name: name,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Club{}, resp.Error(http.StatusNotFound, "failed to rename club", []interface{}{"club not found"})
+		}
+		if isUniqueViolation(err) {
+			return model.Club{}, resp.Error(http.StatusConflict, "failed to rename club", []interface{}{"club name already exists"})
+		}
+		return model.Club{}, resp.Error(http.StatusInternalServerError, "failed to rename club", []interface{}{err.Error()})
+	}
+
+	return clubToDomain(c), nil
+}
+
+// SetJoinPolicy opens the club to anyone or makes it invite-only. Pending
+// join requests and invitations stay pending either way.
+func (r *clubs) SetJoinPolicy(ctx context.Context, id uuid.UUID, policy string) (model.Club, *resp.Err) {
+	if _, err := r.q.GetClubByID(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Club{}, resp.Error(http.StatusNotFound, "failed to change club join policy", []interface{}{"club not found"})
+		}
+		return model.Club{}, resp.Error(http.StatusInternalServerError, "failed to change club join policy", []interface{}{err.Error()})
+	}
+
+	manager, err := isClubManager(ctx, r.q, id)
+	if err != nil {
+		return model.Club{}, resp.Error(http.StatusInternalServerError, "failed to change club join policy", []interface{}{err.Error()})
+	}
+	if !manager {
+		return model.Club{}, resp.Error(http.StatusForbidden, "failed to change club join policy", []interface{}{"only owners and moderators of the club can change its join policy"})
+	}
+
+	c, err := r.q.SetClubJoinPolicy(ctx, sqlc.SetClubJoinPolicyParams{
+		ID:         id,
+		JoinPolicy: policy,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Club{}, resp.Error(http.StatusNotFound, "failed to change club join policy", []interface{}{"club not found"})
+		}
+		return model.Club{}, resp.Error(http.StatusInternalServerError, "failed to change club join policy", []interface{}{err.Error()})
+	}
+
+	return clubToDomain(c), nil
+}
+
+// SetMemberRole changes the role of a member, locking the owners first, and reports
+// whether it changed.
+func (r *clubs) SetMemberRole(ctx context.Context, clubID, userID uuid.UUID, role string) (model.ClubMember, bool, *resp.Err) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.ClubMember{}, false, resp.Error(http.StatusInternalServerError, "failed to change member role", []interface{}{err.Error()})
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	qtx := r.q.WithTx(tx)
+
+	owners, err := qtx.LockClubOwners(ctx, clubID)
+	if err != nil {
+		_ = tx.Rollback()
+		return model.ClubMember{}, false, resp.Error(http.StatusInternalServerError, "failed to change member role", []interface{}{err.Error()})
+	}
+
+	m, err := qtx.GetClubMemberForUpdate(ctx, sqlc.GetClubMemberForUpdateParams{
+		ClubID: clubID,
+		UserID: userID,
+	})
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.ClubMember{}, false, resp.Error(http.StatusNotFound, "failed to change member role", []interface{}{"user is not a member of the club"})
+		}
+		return model.ClubMember{}, false, resp.Error(http.StatusInternalServerError, "failed to change member role", []interface{}{err.Error()})
+	}
+
+	if m.Role == model.ClubRoleOwner && role != model.ClubRoleOwner && len(owners) <= 1 {
+		_ = tx.Rollback()
+		return model.ClubMember{}, false, resp.Error(http.StatusConflict, "failed to change member role", []interface{}{"a club must have at least one owner"})
+	}
+
+	before, err := lockedUser(ctx, qtx, userID)
+	if err != nil {
+		_ = tx.Rollback()
+		return model.ClubMember{}, false, resp.Error(http.StatusInternalServerError, "failed to change member role", []interface{}{err.Error()})
+	}
+	if before == nil || before.Deleted {
+		_ = tx.Rollback()
+		return model.ClubMember{}, false, resp.Error(http.StatusNotFound, "failed to change member role", []interface{}{"user not found"})
+	}
+
+	if m.Role == role {
+		_ = tx.Rollback()
+		return model.ClubMember{User: *before, Role: m.Role, Joined: m.JoinedAt}, false, nil
+	}
+
+	m, err = qtx.SetClubMemberRole(ctx, sqlc.SetClubMemberRoleParams{
+		ClubID: clubID,
+		UserID: userID,
+		Role:   role,
+	})
+	if err != nil {
+		_ = tx.Rollback()
+		return model.ClubMember{}, false, resp.Error(http.StatusInternalServerError, "failed to change member role", []interface{}{err.Error()})
+	}
+
+	user, err := touchUser(ctx, qtx, *before, model.AuditOpSetClubRole)
+	if err != nil {
+		_ = tx.Rollback()
+		return model.ClubMember{}, false, resp.Error(http.StatusInternalServerError, "failed to change member role", []interface{}{err.Error()})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.ClubMember{}, false, resp.Error(http.StatusInternalServerError, "failed to change member role", []interface{}{err.Error()})
+	}
+
+	return model.ClubMember{
+		User:   user,
+		Role:   m.Role,
+		Joined: m.JoinedAt,
+	}, true, nil
+}
+
+// joinClub makes the locked user a member of the club once a request is accepted.
+func joinClub(ctx context.Context, q *sqlc.Queries, before model.User, clubID uuid.UUID) (model.User, error) {
+	if err := q.AddUserClub(ctx, sqlc.AddUserClubParams{
+		UserID: before.ID,
+		ClubID: clubID,
+	}); err != nil {
+		return model.User{}, err
+	}
+
+	return touchUser(ctx, q, before, model.AuditOpJoinClub)
+}
+
+// touchUser bumps the version of a user whose clubs changed and records it as op.
+func touchUser(ctx context.Context, q *sqlc.Queries, before model.User, op string) (model.User, error) {
+	u, err := q.TouchUser(ctx, before.ID)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	changed, err := changedUser(ctx, q, u)
+	if err == nil {
+		err = addOutboxEvent(ctx, q, model.UserEventUpdated, changed)
+	}
+	if err == nil {
+		err = addAuditEntry(ctx, q, op, &before, &changed)
+	}
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return changed, nil
+}
+
+// actorID is the user id the actor of ctx claims, or uuid.Nil.
+func actorID(ctx context.Context) uuid.UUID {
+	id, err := uuid.Parse(model.AuditActorFrom(ctx).ID)
+	if err != nil {
+		return uuid.Nil
+	}
+	return id
+}
+
+// isClubManager reports whether the actor of ctx owns or moderates the club.
+func isClubManager(ctx context.Context, q *sqlc.Queries, clubID uuid.UUID) (bool, error) {
+	id := actorID(ctx)
+	if id == uuid.Nil {
+		return false, nil
+	}
+	return q.IsClubManager(ctx, sqlc.IsClubManagerParams{ClubID: clubID, UserID: id})
+}
+
+// canDecideRequest reports whether the actor of ctx may decide on cr, or why not.
+func canDecideRequest(ctx context.Context, q *sqlc.Queries, cr sqlc.ClubRequest) (bool, string, error) {
+	if cr.Kind == model.ClubRequestInvitation {
+		if actorID(ctx) != cr.UserID {
+			return false, "only the invited user can decide on an invitation", nil
+		}
+		return true, "", nil
+	}
+
+	manager, err := isClubManager(ctx, q, cr.ClubID)
+	if err != nil || !manager {
+		return false, "only owners and moderators of the club can decide on join requests", err
+	}
+	return true, "", nil
+}
+
+func clubToDomain(c sqlc.Club) model.Club {
+	return model.Club{
+		ID:         c.ID,
+		Name:       c.Name,
+		JoinPolicy: c.JoinPolicy,
+	}
+}
+
+func clubRequestToDomain(cr sqlc.ClubRequest) model.ClubRequest {
+	request := model.ClubRequest{
+		ID:        cr.ID,
+		ClubID:    cr.ClubID,
+		UserID:    cr.UserID,
+		Kind:      cr.Kind,
+		Status:    cr.Status,
+		CreatedBy: cr.CreatedBy,
+		DecidedBy: nullStringToString(cr.DecidedBy),
+		Created:   cr.CreatedAt,
+		Expires:   cr.ExpiresAt,
+	}
+	if cr.DecidedAt.Valid {
+		decided := cr.DecidedAt.Time
+		request.Decided = &decided
+	}
+	return request
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	model "github.com/demkowo/users/internal/models"
+	"github.com/demkowo/users/internal/repositories/postgres/sqlc"
+	"github.com/demkowo/utils/resp"
+)
+
+type Outbox interface {
+	Relay(ctx context.Context, limit int32, publish func(model.OutboxEvent) error, retryAt func(attempts int32) time.Time) (int, *resp.Err)
+}
+
+type outbox struct {
+	db *sql.DB
+	q  *sqlc.Queries
+}
+
+func NewOutbox(db *sql.DB) Outbox {
+	return &outbox{
+		db: db,
+		q:  sqlc.New(db),
+	}
+}
+
+// Relay passes up to limit due events to publish in order, marking them published or
+// rescheduled for retryAt, and returns how many it claimed.
+func (r *outbox) Relay(ctx context.Context, limit int32, publish func(model.OutboxEvent) error, retryAt func(attempts int32) time.Time) (int, *resp.Err) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, resp.Error(http.StatusInternalServerError, "failed to relay outbox", []interface{}{err.Error()})
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	qtx := r.q.WithTx(tx)
+
+	events, err := qtx.ClaimOutboxEvents(ctx, limit)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, resp.Error(http.StatusInternalServerError, "failed to relay outbox", []interface{}{err.Error()})
+	}
+
+	for _, e := range events {
+		if pubErr := publish(outboxToDomain(e)); pubErr != nil {
+			err = qtx.MarkOutboxEventFailed(ctx, sqlc.MarkOutboxEventFailedParams{
+				ID:            e.ID,
+				LastError:     nullString(pubErr.Error()),
+				NextAttemptAt: retryAt(e.Attempts + 1),
+			})
+		} else {
+			err = qtx.MarkOutboxEventPublished(ctx, e.ID)
+		}
+		if err != nil {
+			_ = tx.Rollback()
+			return 0, resp.Error(http.StatusInternalServerError, "failed to relay outbox", []interface{}{err.Error()})
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, resp.Error(http.StatusInternalServerError, "failed to relay outbox", []interface{}{err.Error()})
+	}
+
+	return len(events), nil
+}
+
+func outboxToDomain(e sqlc.Outbox) model.OutboxEvent {
+	return model.OutboxEvent{
+		ID:       e.ID,
+		UserID:   e.UserID,
+		Type:     e.EventType,
+		Payload:  e.Payload,
+		Created:  e.CreatedAt,
+		Attempts: e.Attempts,
+	}
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	model "github.com/demkowo/users/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestRelay(t *testing.T) {
+	created := time.Now().UTC().Truncate(time.Microsecond)
+	events := []model.OutboxEvent{
+		{ID: 1, UserID: uuid.New(), Type: model.UserEventCreated, Payload: []byte(`{"version":1}`), Created: created},
+		{ID: 2, UserID: uuid.New(), Type: model.UserEventUpdated, Payload: []byte(`{"version":4}`), Created: created, Attempts: 2},
+		{ID: 3, UserID: uuid.New(), Type: model.UserEventDeleted, Payload: []byte(`{"version":7}`), Created: created},
+	}
+	retry := time.Now().Add(time.Minute)
+
+	tests := []struct {
+		name        string
+		fail        map[string]bool
+		failing     map[int64]bool
+		wantN       int
+		wantCode    int
+		wantLog     []string
+		wantRetries []int32
+	}{
+		{
+			name:  "published",
+			wantN: 3,
+			wantLog: []string{"BEGIN", "ClaimOutboxEvents",
+				"MarkOutboxEventPublished 1", "MarkOutboxEventPublished 2", "MarkOutboxEventPublished 3", "COMMIT"},
+		},
+		{
+			name:    "failed events rescheduled",
+			failing: map[int64]bool{2: true},
+			wantN:   3,
+			wantLog: []string{"BEGIN", "ClaimOutboxEvents",
+				"MarkOutboxEventPublished 1", "MarkOutboxEventFailed 2 broker is down", "MarkOutboxEventPublished 3", "COMMIT"},
+			wantRetries: []int32{3},
+		},
+		{
+			name:     "claim failed",
+			fail:     map[string]bool{"ClaimOutboxEvents": true},
+			wantCode: http.StatusInternalServerError,
+			wantLog:  []string{"BEGIN", "ClaimOutboxEvents", "ROLLBACK"},
+		},
+		{
+			name:     "mark failed",
+			fail:     map[string]bool{"MarkOutboxEventPublished 2": true},
+			wantCode: http.StatusInternalServerError,
+			wantLog:  []string{"BEGIN", "ClaimOutboxEvents", "MarkOutboxEventPublished 1", "MarkOutboxEventPublished 2", "ROLLBACK"},
+		},
+		{
+			name:     "commit failed",
+			fail:     map[string]bool{"COMMIT": true},
+			wantCode: http.StatusInternalServerError,
+			wantLog: []string{"BEGIN", "ClaimOutboxEvents",
+				"MarkOutboxEventPublished 1", "MarkOutboxEventPublished 2", "MarkOutboxEventPublished 3", "COMMIT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fdb := &fakeDB{outbox: events, fail: tt.fail}
+			db := sql.OpenDB(fdb)
+			defer db.Close()
+
+			var published []model.OutboxEvent
+			var retries []int32
+			n, err := NewOutbox(db).Relay(context.Background(), 10, func(e model.OutboxEvent) error {
+				published = append(published, e)
+				if tt.failing[e.ID] {
+					return errors.New("broker is down")
+				}
+				return nil
+			}, func(attempts int32) time.Time {
+				retries = append(retries, attempts)
+				return retry
+			})
+
+			code := 0
+			if err != nil {
+				code = err.Code
+			}
+			if code != tt.wantCode || n != tt.wantN {
+				t.Errorf("Relay = %d, %+v, want %d, code %d", n, err, tt.wantN, tt.wantCode)
+			}
+			if !reflect.DeepEqual(fdb.log, tt.wantLog) {
+				t.Errorf("statements = %q, want %q", fdb.log, tt.wantLog)
+			}
+			if !reflect.DeepEqual(retries, tt.wantRetries) {
+				t.Errorf("retries after %d attempts, want %d", retries, tt.wantRetries)
+			}
+			if tt.fail["ClaimOutboxEvents"] {
+				return
+			}
+			for i, e := range published {
+				if e.ID != events[i].ID || e.UserID != events[i].UserID || e.Type != events[i].Type ||
+					string(e.Payload) != string(events[i].Payload) || !e.Created.Equal(created) || e.Attempts != events[i].Attempts {
+					t.Errorf("published[%d] = %+v, want %+v", i, e, events[i])
+				}
+			}
+		})
+	}
+}
@@ -1,49 +1,69 @@
 -- name: CreateUser :one
-INSERT INTO users (id, nickname, img, country, city)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, nickname, img, country, city, created_at, updated_at, deleted;
+INSERT INTO users (id, nickname, img, country, city, created_at, updated_at, nickname_key, nickname_skeleton, city_id)
+VALUES ($1, $2, $3, $4, $5, now(), now(), $6, $7, $8)
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count;
 
 -- name: UpdateUser :one
 UPDATE users
-SET country = $2,
-    city = $3,
-    updated_at = now()
-WHERE id = $1 AND deleted = FALSE
-RETURNING id, nickname, img, country, city, created_at, updated_at, deleted;
+SET country = sqlc.arg('country'),
+    city = sqlc.arg('city'),
+    city_id = sqlc.arg('city_id'),
+    updated_at = now(),
+    version = version + 1
+WHERE id = sqlc.arg('id') AND deleted = FALSE
+  AND (sqlc.narg('expected_version')::int IS NULL OR version = sqlc.narg('expected_version'))
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count;
 
 -- name: UpdateUserImg :one
 UPDATE users
 SET img = $2,
-    updated_at = now()
+    updated_at = now(),
+    version = version + 1
 WHERE id = $1 AND deleted = FALSE
-RETURNING id, nickname, img, country, city, created_at, updated_at, deleted;
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count;
+
+-- name: TouchUser :one
+UPDATE users
+SET updated_at = now(),
+    version = version + 1
+WHERE id = $1 AND deleted = FALSE
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count;
 
 -- name: SoftDeleteUser :one
 UPDATE users
 SET deleted = TRUE,
-    updated_at = now()
+    updated_at = now(),
+    version = version + 1
 WHERE id = $1
-RETURNING id, nickname, img, country, city, created_at, updated_at, deleted;
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count;
 
 -- name: GetUserByID :one
-SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
 FROM users u
 WHERE u.id = $1;
 
 -- name: GetUserImgByNickname :one
 SELECT img
 FROM users
-WHERE nickname = $1 AND deleted = FALSE;
+WHERE nickname_key = $1 AND deleted = FALSE;
 
 -- name: ListUsers :many
-SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
 FROM users u
 WHERE u.deleted = FALSE
-ORDER BY u.created_at DESC
+ORDER BY u.created_at DESC, u.id DESC
 LIMIT $1 OFFSET $2;
 
+-- name: ListUsersAfter :many
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
+FROM users u
+WHERE u.deleted = FALSE
+  AND (u.created_at, u.id) < (sqlc.arg('created_at')::timestamptz, sqlc.arg('id')::uuid)
+ORDER BY u.created_at DESC, u.id DESC
+LIMIT sqlc.arg('limit');
+
 -- name: FindUsers :many
-SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
 FROM users u
 WHERE u.deleted = FALSE
 ORDER BY u.created_at DESC;
@@ -54,19 +74,585 @@ VALUES ($1, $2)
 ON CONFLICT (name) 
 DO UPDATE 
     SET name = EXCLUDED.name
-RETURNING id, name;
+RETURNING id, name, join_policy;
 
 -- name: AddUserClub :exec
-INSERT INTO user_clubs (user_id, club_id)
-VALUES ($1, $2)
+INSERT INTO user_clubs (user_id, club_id, role)
+VALUES (
+    sqlc.arg('user_id'),
+    sqlc.arg('club_id'),
+    CASE WHEN EXISTS (SELECT 1 FROM user_clubs WHERE club_id = sqlc.arg('club_id')) THEN 'member' ELSE 'owner' END
+)
 ON CONFLICT DO NOTHING;
 
--- name: DeleteUserClubsByUserID :exec
+-- name: DeleteUserClubs :exec
 DELETE FROM user_clubs
-WHERE user_id = $1;
+WHERE user_id = sqlc.arg('user_id') AND club_id = ANY(sqlc.arg('club_ids')::uuid[]);
+
+-- name: PromoteClubSuccessors :exec
+UPDATE user_clubs uc
+SET role = 'owner'
+FROM (
+    SELECT DISTINCT ON (m.club_id) m.club_id, m.user_id
+    FROM user_clubs m
+    JOIN users u ON u.id = m.user_id
+    WHERE m.club_id = ANY(sqlc.arg('club_ids')::uuid[])
+      AND m.user_id <> sqlc.arg('user_id')
+      AND NOT EXISTS (
+          SELECT 1 FROM user_clubs o
+          JOIN users ou ON ou.id = o.user_id
+          WHERE o.club_id = m.club_id AND o.role = 'owner' AND o.user_id <> sqlc.arg('user_id')
+            AND ou.deleted = FALSE
+      )
+    ORDER BY m.club_id, u.deleted, CASE m.role WHEN 'moderator' THEN 0 ELSE 1 END, m.joined_at, m.user_id
+) successor
+WHERE uc.club_id = successor.club_id AND uc.user_id = successor.user_id;
 
 -- name: GetClubsByUserID :many
-SELECT c.id, c.name
+SELECT c.id, c.name, c.join_policy, uc.role, uc.joined_at
 FROM clubs c
 JOIN user_clubs uc ON uc.club_id = c.id
 WHERE uc.user_id = $1;
+
+-- name: GetClubsByUserIDs :many
+SELECT uc.user_id, c.id, c.name, c.join_policy, uc.role, uc.joined_at
+FROM user_clubs uc
+JOIN clubs c ON c.id = uc.club_id
+WHERE uc.user_id = ANY(sqlc.arg('user_ids')::uuid[])
+ORDER BY c.name;
+
+-- name: InsertClub :one
+INSERT INTO clubs (id, name, join_policy)
+VALUES ($1, $2, $3)
+RETURNING id, name, join_policy;
+
+-- name: GetClubByID :one
+SELECT id, name, join_policy
+FROM clubs
+WHERE id = $1;
+
+-- name: ListClubs :many
+SELECT id, name, join_policy
+FROM clubs
+ORDER BY name
+LIMIT $1 OFFSET $2;
+
+-- name: CountClubs :one
+SELECT COUNT(*)
+FROM clubs;
+
+-- name: RenameClub :one
+UPDATE clubs
+SET name = $2
+WHERE id = $1
+RETURNING id, name, join_policy;
+
+-- name: DeleteClub :one
+DELETE FROM clubs
+WHERE id = $1
+RETURNING id, name, join_policy;
+
+-- name: ListClubMembers :many
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count, uc.role, uc.joined_at
+FROM users u
+JOIN user_clubs uc ON uc.user_id = u.id
+WHERE uc.club_id = $1 AND u.deleted = FALSE
+ORDER BY CASE uc.role WHEN 'owner' THEN 0 WHEN 'moderator' THEN 1 ELSE 2 END, u.nickname
+LIMIT $2 OFFSET $3;
+
+-- name: CountClubMembers :one
+SELECT COUNT(*)
+FROM user_clubs uc
+JOIN users u ON u.id = uc.user_id
+WHERE uc.club_id = $1 AND u.deleted = FALSE;
+
+-- name: GetClubMemberForUpdate :one
+SELECT user_id, club_id, role, joined_at
+FROM user_clubs
+WHERE club_id = $1 AND user_id = $2
+FOR UPDATE;
+
+-- name: LockClubOwners :many
+SELECT user_id
+FROM user_clubs
+WHERE club_id = $1 AND role = 'owner'
+ORDER BY user_id
+FOR UPDATE;
+
+-- name: SetClubMemberRole :one
+UPDATE user_clubs
+SET role = $3
+WHERE club_id = $1 AND user_id = $2
+RETURNING user_id, club_id, role, joined_at;
+
+-- name: IsClubManager :one
+SELECT EXISTS (
+    SELECT 1 FROM user_clubs uc
+    JOIN users u ON u.id = uc.user_id
+    WHERE uc.club_id = $1 AND uc.user_id = $2
+      AND uc.role IN ('owner', 'moderator') AND u.deleted = FALSE
+);
+
+-- name: IsClubMember :one
+SELECT EXISTS (
+    SELECT 1 FROM user_clubs
+    WHERE club_id = $1 AND user_id = $2
+);
+
+-- name: SetClubJoinPolicy :one
+UPDATE clubs
+SET join_policy = $2
+WHERE id = $1
+RETURNING id, name, join_policy;
+
+-- name: ExpireClubRequests :execrows
+UPDATE club_requests
+SET status = 'expired', decided_at = expires_at
+WHERE status = 'pending' AND expires_at <= now();
+
+-- name: InsertClubRequest :one
+INSERT INTO club_requests (id, club_id, user_id, kind, created_by, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, club_id, user_id, kind, status, created_by, decided_by, created_at, expires_at, decided_at;
+
+-- name: GetClubRequestForUpdate :one
+SELECT id, club_id, user_id, kind, status, created_by, decided_by, created_at, expires_at, decided_at
+FROM club_requests
+WHERE id = $1
+FOR UPDATE;
+
+-- name: DecideClubRequest :one
+UPDATE club_requests
+SET status = $2, decided_by = $3, decided_at = now()
+WHERE id = $1
+RETURNING id, club_id, user_id, kind, status, created_by, decided_by, created_at, expires_at, decided_at;
+
+-- name: ListClubRequests :many
+SELECT id, club_id, user_id, kind, status, created_by, decided_by, created_at, expires_at, decided_at
+FROM club_requests
+WHERE (sqlc.narg('club_id')::uuid IS NULL OR club_id = sqlc.narg('club_id'))
+  AND (sqlc.narg('user_id')::uuid IS NULL OR user_id = sqlc.narg('user_id'))
+  AND (sqlc.narg('kind')::text IS NULL OR kind = sqlc.narg('kind'))
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountClubRequests :one
+SELECT COUNT(*)
+FROM club_requests
+WHERE (sqlc.narg('club_id')::uuid IS NULL OR club_id = sqlc.narg('club_id'))
+  AND (sqlc.narg('user_id')::uuid IS NULL OR user_id = sqlc.narg('user_id'))
+  AND (sqlc.narg('kind')::text IS NULL OR kind = sqlc.narg('kind'))
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'));
+
+-- name: IsNicknameTaken :one
+SELECT EXISTS (
+    SELECT 1
+    FROM users
+    WHERE nickname_key = $1 AND id <> $2 AND deleted = FALSE
+);
+
+-- name: RestoreUser :one
+UPDATE users
+SET deleted = FALSE,
+    updated_at = now(),
+    version = version + 1
+WHERE id = $1 AND deleted = TRUE
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count;
+
+-- name: PurgeUser :one
+DELETE FROM users
+WHERE id = $1
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count;
+
+-- name: GetUserByNickname :one
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
+FROM users u
+WHERE u.nickname_key = $1 AND u.deleted = FALSE;
+
+-- name: GetUserByPreviousNickname :one
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
+FROM nickname_history nh
+JOIN users u ON u.id = nh.user_id
+WHERE nh.nickname_key = $1 AND nh.changed_at >= $2 AND u.deleted = FALSE
+ORDER BY nh.changed_at DESC
+LIMIT 1;
+
+-- name: IsNicknameReserved :one
+SELECT EXISTS (
+    SELECT 1
+    FROM nickname_history
+    WHERE nickname_key = $1 AND user_id <> $2 AND changed_at >= $3
+);
+
+-- name: IsNicknameConfusable :one
+SELECT EXISTS (
+    SELECT 1
+    FROM users
+    WHERE nickname_skeleton = sqlc.arg('nickname_skeleton') AND id <> sqlc.arg('user_id') AND deleted = FALSE
+    UNION ALL
+    SELECT 1
+    FROM nickname_history
+    WHERE nickname_skeleton = sqlc.arg('nickname_skeleton') AND user_id <> sqlc.arg('user_id')
+      AND changed_at >= sqlc.arg('changed_at')
+);
+
+-- name: AddNicknameHistory :exec
+INSERT INTO nickname_history (id, user_id, nickname, nickname_key, nickname_skeleton)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: UpdateUserNickname :one
+UPDATE users
+SET nickname = $2,
+    nickname_key = $3,
+    nickname_skeleton = $4,
+    updated_at = now(),
+    version = version + 1
+WHERE id = $1 AND deleted = FALSE
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count;
+
+-- name: SearchUsers :many
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
+FROM users u
+WHERE u.deleted = FALSE
+  AND (sqlc.narg('country')::text IS NULL OR u.country = sqlc.narg('country'))
+  AND (sqlc.narg('city')::text IS NULL OR u.city = sqlc.narg('city'))
+  AND (sqlc.narg('nickname_prefix')::text IS NULL OR u.nickname_key LIKE sqlc.narg('nickname_prefix') || '%')
+  AND (sqlc.narg('club_id')::uuid IS NULL OR EXISTS (
+      SELECT 1 FROM user_clubs uc
+      WHERE uc.user_id = u.id AND uc.club_id = sqlc.narg('club_id')
+  ))
+  AND (sqlc.narg('club_name')::text IS NULL OR EXISTS (
+      SELECT 1 FROM user_clubs uc
+      JOIN clubs c ON c.id = uc.club_id
+      WHERE uc.user_id = u.id AND c.name = sqlc.narg('club_name')
+  ))
+  AND (sqlc.narg('created_from')::timestamptz IS NULL OR u.created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamptz IS NULL OR u.created_at < sqlc.narg('created_to'))
+ORDER BY u.created_at DESC, u.id DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountSearchUsers :one
+SELECT COUNT(*)
+FROM users u
+WHERE u.deleted = FALSE
+  AND (sqlc.narg('country')::text IS NULL OR u.country = sqlc.narg('country'))
+  AND (sqlc.narg('city')::text IS NULL OR u.city = sqlc.narg('city'))
+  AND (sqlc.narg('nickname_prefix')::text IS NULL OR u.nickname_key LIKE sqlc.narg('nickname_prefix') || '%')
+  AND (sqlc.narg('club_id')::uuid IS NULL OR EXISTS (
+      SELECT 1 FROM user_clubs uc
+      WHERE uc.user_id = u.id AND uc.club_id = sqlc.narg('club_id')
+  ))
+  AND (sqlc.narg('club_name')::text IS NULL OR EXISTS (
+      SELECT 1 FROM user_clubs uc
+      JOIN clubs c ON c.id = uc.club_id
+      WHERE uc.user_id = u.id AND c.name = sqlc.narg('club_name')
+  ))
+  AND (sqlc.narg('created_from')::timestamptz IS NULL OR u.created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamptz IS NULL OR u.created_at < sqlc.narg('created_to'));
+
+-- name: ExportUsers :many
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
+FROM users u
+WHERE u.deleted = FALSE
+  AND (sqlc.narg('country')::text IS NULL OR u.country = sqlc.narg('country'))
+  AND (sqlc.narg('city')::text IS NULL OR u.city = sqlc.narg('city'))
+  AND (sqlc.narg('nickname_prefix')::text IS NULL OR u.nickname_key LIKE sqlc.narg('nickname_prefix') || '%')
+  AND (sqlc.narg('club_id')::uuid IS NULL OR EXISTS (
+      SELECT 1 FROM user_clubs uc
+      WHERE uc.user_id = u.id AND uc.club_id = sqlc.narg('club_id')
+  ))
+  AND (sqlc.narg('club_name')::text IS NULL OR EXISTS (
+      SELECT 1 FROM user_clubs uc
+      JOIN clubs c ON c.id = uc.club_id
+      WHERE uc.user_id = u.id AND c.name = sqlc.narg('club_name')
+  ))
+  AND (sqlc.narg('created_from')::timestamptz IS NULL OR u.created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::timestamptz IS NULL OR u.created_at < sqlc.narg('created_to'))
+  AND (sqlc.narg('after_created_at')::timestamptz IS NULL
+       OR (u.created_at, u.id) < (sqlc.narg('after_created_at'), sqlc.narg('after_id')::uuid))
+ORDER BY u.created_at DESC, u.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: PatchUser :one
+UPDATE users
+SET country = CASE WHEN sqlc.arg('set_country')::boolean THEN sqlc.narg('country')::text ELSE country END,
+    city = CASE WHEN sqlc.arg('set_city')::boolean THEN sqlc.narg('city')::text ELSE city END,
+    city_id = CASE WHEN sqlc.arg('set_city_id')::boolean THEN sqlc.narg('city_id')::text ELSE city_id END,
+    updated_at = now(),
+    version = version + 1
+WHERE id = sqlc.arg('id') AND deleted = FALSE
+  AND (sqlc.narg('expected_version')::int IS NULL OR version = sqlc.narg('expected_version'))
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count;
+
+-- name: InsertOutboxEvent :exec
+INSERT INTO outbox (user_id, event_type, payload)
+VALUES ($1, $2, $3);
+
+-- name: ClaimOutboxEvents :many
+SELECT id, user_id, event_type, payload, created_at, attempts, last_error, next_attempt_at, published_at
+FROM outbox
+WHERE published_at IS NULL AND next_attempt_at <= now()
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxEventPublished :exec
+UPDATE outbox
+SET published_at = now(),
+    attempts = attempts + 1,
+    last_error = NULL
+WHERE id = $1;
+
+-- name: MarkOutboxEventFailed :exec
+UPDATE outbox
+SET attempts = attempts + 1,
+    last_error = sqlc.arg('last_error'),
+    next_attempt_at = sqlc.arg('next_attempt_at')
+WHERE id = sqlc.arg('id');
+
+-- name: CreateWebhook :one
+INSERT INTO webhooks (id, url, event_types, secret, active)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, url, event_types, secret, active, created_at, updated_at;
+
+-- name: UpdateWebhook :one
+UPDATE webhooks
+SET url = sqlc.arg('url'),
+    event_types = sqlc.arg('event_types'),
+    secret = COALESCE(sqlc.narg('secret'), secret),
+    active = sqlc.arg('active'),
+    updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING id, url, event_types, secret, active, created_at, updated_at;
+
+-- name: DeleteWebhook :one
+DELETE FROM webhooks
+WHERE id = $1
+RETURNING id, url, event_types, secret, active, created_at, updated_at;
+
+-- name: GetWebhookByID :one
+SELECT id, url, event_types, secret, active, created_at, updated_at
+FROM webhooks
+WHERE id = $1;
+
+-- name: ListWebhooks :many
+SELECT id, url, event_types, secret, active, created_at, updated_at
+FROM webhooks
+ORDER BY created_at, id
+LIMIT $1 OFFSET $2;
+
+-- name: CountWebhooks :one
+SELECT COUNT(*)
+FROM webhooks;
+
+-- name: ListWebhooksForEvent :many
+SELECT id, url, event_types, secret, active, created_at, updated_at
+FROM webhooks
+WHERE active = TRUE AND sqlc.arg('event_type')::text = ANY(event_types);
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (id, webhook_id, event_type, payload)
+VALUES ($1, $2, $3, $4)
+RETURNING id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at;
+
+-- name: GetWebhookDeliveryByID :one
+SELECT id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at
+FROM webhook_deliveries
+WHERE id = $1;
+
+-- name: ListWebhookDeliveries :many
+SELECT id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at
+FROM webhook_deliveries
+WHERE webhook_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountWebhookDeliveries :one
+SELECT COUNT(*)
+FROM webhook_deliveries
+WHERE webhook_id = $1;
+
+-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries
+SET next_attempt_at = sqlc.arg('lease_until')
+WHERE id IN (
+    SELECT d.id
+    FROM webhook_deliveries d
+    WHERE d.status = 'pending' AND d.next_attempt_at <= now()
+    ORDER BY d.next_attempt_at
+    LIMIT sqlc.arg('limit')
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at;
+
+-- name: RecordWebhookDeliveryAttempt :exec
+UPDATE webhook_deliveries
+SET attempts = attempts + 1,
+    status = sqlc.arg('status'),
+    last_status_code = sqlc.narg('last_status_code'),
+    last_error = sqlc.narg('last_error'),
+    next_attempt_at = sqlc.arg('next_attempt_at'),
+    delivered_at = CASE WHEN sqlc.arg('status') = 'succeeded' THEN now() ELSE delivered_at END
+WHERE id = sqlc.arg('id');
+
+-- name: GetUserByIDForUpdate :one
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
+FROM users u
+WHERE u.id = $1
+FOR UPDATE;
+
+-- name: InsertAuditEntry :exec
+INSERT INTO user_audit (id, user_id, actor, actor_addr, transport, operation, before, after, changes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: ListAuditEntries :many
+SELECT id, user_id, actor, transport, operation, before, after, changes, created_at, actor_addr
+FROM user_audit
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountAuditEntries :one
+SELECT COUNT(*)
+FROM user_audit
+WHERE user_id = $1;
+
+-- name: ListUsersWithoutNicknameKey :many
+SELECT id, nickname
+FROM users
+WHERE nickname_key IS NULL AND id > $1
+ORDER BY id
+LIMIT $2;
+
+-- name: SetUserNicknameKey :exec
+UPDATE users
+SET nickname_key = $2,
+    nickname_skeleton = $3
+WHERE id = $1;
+
+-- name: ListNicknameHistoryWithoutKey :many
+SELECT id, nickname
+FROM nickname_history
+WHERE nickname_key IS NULL AND id > $1
+ORDER BY id
+LIMIT $2;
+
+-- name: SetNicknameHistoryKey :exec
+UPDATE nickname_history
+SET nickname_key = $2,
+    nickname_skeleton = $3
+WHERE id = $1;
+
+-- name: ListDistinctCountries :many
+SELECT DISTINCT country
+FROM users
+WHERE country IS NOT NULL AND country <> ''
+ORDER BY country;
+
+-- name: ReplaceUserCountry :execrows
+UPDATE users
+SET country = sqlc.arg('code'),
+    updated_at = now(),
+    version = version + 1
+WHERE country = sqlc.arg('country');
+
+-- name: ListUsersInCities :many
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
+FROM users u
+JOIN unnest(sqlc.arg('city_ids')::text[]) WITH ORDINALITY AS c(id, rank) ON c.id = u.city_id
+WHERE u.deleted = FALSE
+ORDER BY c.rank, u.created_at, u.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountUsersInCities :one
+SELECT COUNT(*)
+FROM users
+WHERE deleted = FALSE AND city_id = ANY(sqlc.arg('city_ids')::text[]);
+
+-- name: ListUsersWithoutCityID :many
+SELECT id, country, city
+FROM users
+WHERE city_id IS NULL AND city IS NOT NULL AND city <> '' AND id > $1
+ORDER BY id
+LIMIT $2;
+
+-- name: SetUserCityID :exec
+UPDATE users
+SET city_id = $2
+WHERE id = $1;
+
+-- name: LockUsers :many
+SELECT id, deleted
+FROM users
+WHERE id = ANY(sqlc.arg('ids')::uuid[])
+ORDER BY id
+FOR UPDATE;
+
+-- name: InsertFollow :execrows
+INSERT INTO user_follows (follower_id, followee_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteFollow :execrows
+DELETE FROM user_follows
+WHERE follower_id = $1 AND followee_id = $2;
+
+-- name: GetFollow :one
+SELECT follower_id, followee_id, created_at
+FROM user_follows
+WHERE follower_id = $1 AND followee_id = $2;
+
+-- name: AddFollowerCount :exec
+UPDATE users
+SET follower_count = follower_count + sqlc.arg('delta')::int
+WHERE id = sqlc.arg('id');
+
+-- name: AddFollowingCount :exec
+UPDATE users
+SET following_count = following_count + sqlc.arg('delta')::int
+WHERE id = sqlc.arg('id');
+
+-- name: AddFollowerCountOfFollowees :exec
+UPDATE users
+SET follower_count = follower_count + sqlc.arg('delta')::int
+WHERE id IN (
+    SELECT followee_id FROM user_follows
+    WHERE follower_id = sqlc.arg('user_id')
+);
+
+-- name: AddFollowingCountOfFollowers :exec
+UPDATE users
+SET following_count = following_count + sqlc.arg('delta')::int
+WHERE id IN (
+    SELECT follower_id FROM user_follows
+    WHERE followee_id = sqlc.arg('user_id')
+);
+
+-- name: ListFollowers :many
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count, f.created_at AS followed_at
+FROM user_follows f
+JOIN users u ON u.id = f.follower_id
+WHERE f.followee_id = $1 AND u.deleted = FALSE
+ORDER BY f.created_at DESC, u.id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountFollowers :one
+SELECT COUNT(*)
+FROM user_follows f
+JOIN users u ON u.id = f.follower_id
+WHERE f.followee_id = $1 AND u.deleted = FALSE;
+
+-- name: ListFollowing :many
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count, f.created_at AS followed_at
+FROM user_follows f
+JOIN users u ON u.id = f.followee_id
+WHERE f.follower_id = $1 AND u.deleted = FALSE
+ORDER BY f.created_at DESC, u.id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountFollowing :one
+SELECT COUNT(*)
+FROM user_follows f
+JOIN users u ON u.id = f.followee_id
+WHERE f.follower_id = $1 AND u.deleted = FALSE;
@@ -3,15 +3,42 @@ CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 -- Users table
 CREATE TABLE IF NOT EXISTS users (
     id UUID PRIMARY KEY,
-    nickname TEXT UNIQUE NOT NULL,
+    nickname TEXT NOT NULL,
     img TEXT,
     country TEXT,
     city TEXT,
-    created_at TIMESTAMP WITH TIME ZONE,
-    updated_at TIMESTAMP WITH TIME ZONE,
-    deleted BOOLEAN DEFAULT false
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
+    deleted BOOLEAN DEFAULT false,
+    version INTEGER NOT NULL DEFAULT 1
 );
 
+-- Nicknames must be unique among active users only, so a soft-deleted
+-- user's nickname can be taken again.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_nickname_key;
+CREATE UNIQUE INDEX IF NOT EXISTS users_active_nickname_idx ON users (nickname) WHERE deleted = FALSE;
+
+-- Keyset pagination walks active users by (created_at, id), which needs
+-- created_at on every row.
+UPDATE users SET created_at = COALESCE(updated_at, now()) WHERE created_at IS NULL;
+CREATE INDEX IF NOT EXISTS users_created_at_id_idx ON users (created_at DESC, id DESC) WHERE deleted = FALSE;
+
+-- Canonical nickname forms (see model.NicknameKey and model.NicknameSkeleton),
+-- filled in by the application. Keys are unique among active users; skeletons
+-- are only looked up, to refuse confusable nicknames. Rows from before these
+-- columns are backfilled by `go run ./cmd/migrate nicknames`.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS nickname_key TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS nickname_skeleton TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS users_active_nickname_key_idx ON users (nickname_key) WHERE deleted = FALSE;
+CREATE INDEX IF NOT EXISTS users_active_nickname_skeleton_idx ON users (nickname_skeleton) WHERE deleted = FALSE;
+
+-- Cities of the gazetteer bundled with the application (see model.City),
+-- resolved from country and city by the application. Users whose city isn't
+-- in the gazetteer have none and aren't found by nearby searches. Rows from
+-- before this column are resolved by `go run ./cmd/migrate cities`.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS city_id TEXT;
+CREATE INDEX IF NOT EXISTS users_active_city_id_idx ON users (city_id) WHERE deleted = FALSE;
+
 -- Clubs table
 CREATE TABLE IF NOT EXISTS clubs (
     id UUID PRIMARY KEY,
@@ -24,3 +51,147 @@ CREATE TABLE IF NOT EXISTS user_clubs (
     club_id UUID NOT NULL REFERENCES clubs(id) ON DELETE CASCADE,
     PRIMARY KEY (user_id, club_id)
 );
+
+-- Roles of club members (see model.ClubMember). The first member of a club
+-- becomes its owner and a club with members always keeps one. Clubs from
+-- before roles get their longest-standing member as owner.
+ALTER TABLE user_clubs ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'member';
+ALTER TABLE user_clubs ADD COLUMN IF NOT EXISTS joined_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now();
+ALTER TABLE user_clubs DROP CONSTRAINT IF EXISTS user_clubs_role_check;
+ALTER TABLE user_clubs ADD CONSTRAINT user_clubs_role_check CHECK (role IN ('member', 'moderator', 'owner'));
+CREATE INDEX IF NOT EXISTS user_clubs_club_id_role_idx ON user_clubs (club_id, role);
+UPDATE user_clubs uc
+SET role = 'owner'
+FROM (
+    SELECT DISTINCT ON (m.club_id) m.club_id, m.user_id
+    FROM user_clubs m
+    JOIN users u ON u.id = m.user_id
+    WHERE NOT EXISTS (
+        SELECT 1 FROM user_clubs o
+        WHERE o.club_id = m.club_id AND o.role = 'owner'
+    )
+    ORDER BY m.club_id, u.created_at, m.user_id
+) oldest
+WHERE uc.club_id = oldest.club_id AND uc.user_id = oldest.user_id;
+
+-- Clubs are open to anyone or invite-only. Invite-only clubs are joined by an
+-- accepted invitation or join request (see model.ClubRequest).
+ALTER TABLE clubs ADD COLUMN IF NOT EXISTS join_policy TEXT NOT NULL DEFAULT 'open';
+ALTER TABLE clubs DROP CONSTRAINT IF EXISTS clubs_join_policy_check;
+ALTER TABLE clubs ADD CONSTRAINT clubs_join_policy_check CHECK (join_policy IN ('open', 'invite_only'));
+
+-- Club_Requests (join requests and invitations). A user has at most one
+-- pending request or invitation per club; pending ones past expires_at are
+-- marked expired before requests are read or acted on.
+CREATE TABLE IF NOT EXISTS club_requests (
+    id UUID PRIMARY KEY,
+    club_id UUID NOT NULL REFERENCES clubs(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('join', 'invitation')),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'rejected', 'expired')),
+    created_by TEXT NOT NULL,
+    decided_by TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    decided_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS club_requests_pending_idx ON club_requests (club_id, user_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS club_requests_club_id_created_at_idx ON club_requests (club_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS club_requests_user_id_created_at_idx ON club_requests (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS club_requests_expires_at_idx ON club_requests (expires_at) WHERE status = 'pending';
+
+-- User_Follows (who follows whom). The follower_count and following_count of
+-- users count the follows of active users only; the application keeps them in
+-- the transaction of every follow, unfollow, delete, restore and purge.
+CREATE TABLE IF NOT EXISTS user_follows (
+    follower_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (follower_id, followee_id),
+    CONSTRAINT user_follows_self_check CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_follows_follower_id_created_at_idx ON user_follows (follower_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS user_follows_followee_id_created_at_idx ON user_follows (followee_id, created_at DESC);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS follower_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS following_count INTEGER NOT NULL DEFAULT 0;
+
+-- Nickname_History (previous nicknames, resolved to their user for a grace period)
+CREATE TABLE IF NOT EXISTS nickname_history (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    nickname TEXT NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS nickname_history_nickname_idx ON nickname_history (nickname, changed_at DESC);
+
+ALTER TABLE nickname_history ADD COLUMN IF NOT EXISTS nickname_key TEXT;
+ALTER TABLE nickname_history ADD COLUMN IF NOT EXISTS nickname_skeleton TEXT;
+CREATE INDEX IF NOT EXISTS nickname_history_nickname_key_idx ON nickname_history (nickname_key, changed_at DESC);
+CREATE INDEX IF NOT EXISTS nickname_history_nickname_skeleton_idx ON nickname_history (nickname_skeleton, changed_at DESC);
+
+-- Outbox (user events written in the same transaction as the change, relayed
+-- to the publisher until delivered)
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    published_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (next_attempt_at, id) WHERE published_at IS NULL;
+
+-- Webhooks (partner endpoints subscribed to user events)
+CREATE TABLE IF NOT EXISTS webhooks (
+    id UUID PRIMARY KEY,
+    url TEXT NOT NULL,
+    event_types TEXT[] NOT NULL,
+    secret TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+-- Webhook_Deliveries (delivery log; pending rows are retried until they
+-- succeed or run out of attempts)
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY,
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    last_status_code INTEGER,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_pending_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_idx ON webhook_deliveries (webhook_id, created_at DESC);
+
+-- User_Audit (who changed what on a user; kept after the user is purged)
+CREATE TABLE IF NOT EXISTS user_audit (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    transport VARCHAR(10) NOT NULL,
+    operation VARCHAR(50) NOT NULL,
+    before JSONB NOT NULL,
+    after JSONB NOT NULL,
+    changes JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+ALTER TABLE user_audit ADD COLUMN IF NOT EXISTS actor_addr VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS user_audit_user_idx ON user_audit (user_id, created_at DESC, id DESC);
@@ -6,27 +6,115 @@ package sqlc
 
 import (
 	"database/sql"
+	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Club struct {
-	ID   uuid.UUID
-	Name string
+	ID         uuid.UUID
+	Name       string
+	JoinPolicy string
+}
+
+type ClubRequest struct {
+	ID        uuid.UUID
+	ClubID    uuid.UUID
+	UserID    uuid.UUID
+	Kind      string
+	Status    string
+	CreatedBy string
+	DecidedBy sql.NullString
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	DecidedAt sql.NullTime
+}
+
+type NicknameHistory struct {
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	Nickname         string
+	ChangedAt        time.Time
+	NicknameKey      sql.NullString
+	NicknameSkeleton sql.NullString
+}
+
+type Outbox struct {
+	ID            int64
+	UserID        uuid.UUID
+	EventType     string
+	Payload       json.RawMessage
+	CreatedAt     time.Time
+	Attempts      int32
+	LastError     sql.NullString
+	NextAttemptAt time.Time
+	PublishedAt   sql.NullTime
 }
 
 type User struct {
+	ID               uuid.UUID
+	Nickname         string
+	Img              sql.NullString
+	Country          sql.NullString
+	City             sql.NullString
+	CreatedAt        sql.NullTime
+	UpdatedAt        sql.NullTime
+	Deleted          sql.NullBool
+	Version          int32
+	NicknameKey      sql.NullString
+	NicknameSkeleton sql.NullString
+	CityID           sql.NullString
+	FollowerCount    int32
+	FollowingCount   int32
+}
+
+type UserAudit struct {
 	ID        uuid.UUID
-	Nickname  string
-	Img       sql.NullString
-	Country   sql.NullString
-	City      sql.NullString
-	CreatedAt sql.NullTime
-	UpdatedAt sql.NullTime
-	Deleted   sql.NullBool
+	UserID    uuid.UUID
+	Actor     string
+	Transport string
+	Operation string
+	Before    json.RawMessage
+	After     json.RawMessage
+	Changes   json.RawMessage
+	CreatedAt time.Time
+	ActorAddr string
 }
 
 type UserClub struct {
-	UserID uuid.UUID
-	ClubID uuid.UUID
+	UserID   uuid.UUID
+	ClubID   uuid.UUID
+	Role     string
+	JoinedAt time.Time
+}
+
+type UserFollow struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+	CreatedAt  time.Time
+}
+
+type Webhook struct {
+	ID         uuid.UUID
+	Url        string
+	EventTypes []string
+	Secret     string
+	Active     bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	WebhookID      uuid.UUID
+	EventType      string
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int32
+	NextAttemptAt  time.Time
+	LastStatusCode sql.NullInt32
+	LastError      sql.NullString
+	CreatedAt      time.Time
+	DeliveredAt    sql.NullTime
 }
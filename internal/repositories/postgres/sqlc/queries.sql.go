@@ -8,13 +8,114 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const addFollowerCount = `-- name: AddFollowerCount :exec
+UPDATE users
+SET follower_count = follower_count + $1::int
+WHERE id = $2
+`
+
+type AddFollowerCountParams struct {
+	Delta int32
+	ID    uuid.UUID
+}
+
+func (q *Queries) AddFollowerCount(ctx context.Context, arg AddFollowerCountParams) error {
+	_, err := q.db.ExecContext(ctx, addFollowerCount, arg.Delta, arg.ID)
+	return err
+}
+
+const addFollowerCountOfFollowees = `-- name: AddFollowerCountOfFollowees :exec
+UPDATE users
+SET follower_count = follower_count + $1::int
+WHERE id IN (
+    SELECT followee_id FROM user_follows
+    WHERE follower_id = $2
+)
+`
+
+type AddFollowerCountOfFolloweesParams struct {
+	Delta  int32
+	UserID uuid.UUID
+}
+
+func (q *Queries) AddFollowerCountOfFollowees(ctx context.Context, arg AddFollowerCountOfFolloweesParams) error {
+	_, err := q.db.ExecContext(ctx, addFollowerCountOfFollowees, arg.Delta, arg.UserID)
+	return err
+}
+
+const addFollowingCount = `-- name: AddFollowingCount :exec
+UPDATE users
+SET following_count = following_count + $1::int
+WHERE id = $2
+`
+
+type AddFollowingCountParams struct {
+	Delta int32
+	ID    uuid.UUID
+}
+
+func (q *Queries) AddFollowingCount(ctx context.Context, arg AddFollowingCountParams) error {
+	_, err := q.db.ExecContext(ctx, addFollowingCount, arg.Delta, arg.ID)
+	return err
+}
+
+const addFollowingCountOfFollowers = `-- name: AddFollowingCountOfFollowers :exec
+UPDATE users
+SET following_count = following_count + $1::int
+WHERE id IN (
+    SELECT follower_id FROM user_follows
+    WHERE followee_id = $2
+)
+`
+
+type AddFollowingCountOfFollowersParams struct {
+	Delta  int32
+	UserID uuid.UUID
+}
+
+func (q *Queries) AddFollowingCountOfFollowers(ctx context.Context, arg AddFollowingCountOfFollowersParams) error {
+	_, err := q.db.ExecContext(ctx, addFollowingCountOfFollowers, arg.Delta, arg.UserID)
+	return err
+}
+
+const addNicknameHistory = `-- name: AddNicknameHistory :exec
+INSERT INTO nickname_history (id, user_id, nickname, nickname_key, nickname_skeleton)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type AddNicknameHistoryParams struct {
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	Nickname         string
+	NicknameKey      sql.NullString
+	NicknameSkeleton sql.NullString
+}
+
+func (q *Queries) AddNicknameHistory(ctx context.Context, arg AddNicknameHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, addNicknameHistory,
+		arg.ID,
+		arg.UserID,
+		arg.Nickname,
+		arg.NicknameKey,
+		arg.NicknameSkeleton,
+	)
+	return err
+}
+
 const addUserClub = `-- name: AddUserClub :exec
-INSERT INTO user_clubs (user_id, club_id)
-VALUES ($1, $2)
+INSERT INTO user_clubs (user_id, club_id, role)
+VALUES (
+    $1,
+    $2,
+    CASE WHEN EXISTS (SELECT 1 FROM user_clubs WHERE club_id = $2) THEN 'member' ELSE 'owner' END
+)
 ON CONFLICT DO NOTHING
 `
 
@@ -28,13 +129,287 @@ func (q *Queries) AddUserClub(ctx context.Context, arg AddUserClubParams) error
 	return err
 }
 
+const claimOutboxEvents = `-- name: ClaimOutboxEvents :many
+SELECT id, user_id, event_type, payload, created_at, attempts, last_error, next_attempt_at, published_at
+FROM outbox
+WHERE published_at IS NULL AND next_attempt_at <= now()
+ORDER BY id
+LIMIT $1
+FOR UPDATE SKIP LOCKED
+`
+
+func (q *Queries) ClaimOutboxEvents(ctx context.Context, limit int32) ([]Outbox, error) {
+	rows, err := q.db.QueryContext(ctx, claimOutboxEvents, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Outbox
+	for rows.Next() {
+		var i Outbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.EventType,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.Attempts,
+			&i.LastError,
+			&i.NextAttemptAt,
+			&i.PublishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const claimWebhookDeliveries = `-- name: ClaimWebhookDeliveries :many
+UPDATE webhook_deliveries
+SET next_attempt_at = $1
+WHERE id IN (
+    SELECT d.id
+    FROM webhook_deliveries d
+    WHERE d.status = 'pending' AND d.next_attempt_at <= now()
+    ORDER BY d.next_attempt_at
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at
+`
+
+type ClaimWebhookDeliveriesParams struct {
+	LeaseUntil time.Time
+	Limit      int32
+}
+
+func (q *Queries) ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, claimWebhookDeliveries, arg.LeaseUntil, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastStatusCode,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const countAuditEntries = `-- name: CountAuditEntries :one
+SELECT COUNT(*)
+FROM user_audit
+WHERE user_id = $1
+`
+
+func (q *Queries) CountAuditEntries(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAuditEntries, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countClubMembers = `-- name: CountClubMembers :one
+SELECT COUNT(*)
+FROM user_clubs uc
+JOIN users u ON u.id = uc.user_id
+WHERE uc.club_id = $1 AND u.deleted = FALSE
+`
+
+func (q *Queries) CountClubMembers(ctx context.Context, clubID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countClubMembers, clubID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countClubRequests = `-- name: CountClubRequests :one
+SELECT COUNT(*)
+FROM club_requests
+WHERE ($1::uuid IS NULL OR club_id = $1)
+  AND ($2::uuid IS NULL OR user_id = $2)
+  AND ($3::text IS NULL OR kind = $3)
+  AND ($4::text IS NULL OR status = $4)
+`
+
+type CountClubRequestsParams struct {
+	ClubID uuid.NullUUID
+	UserID uuid.NullUUID
+	Kind   sql.NullString
+	Status sql.NullString
+}
+
+func (q *Queries) CountClubRequests(ctx context.Context, arg CountClubRequestsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countClubRequests,
+		arg.ClubID,
+		arg.UserID,
+		arg.Kind,
+		arg.Status,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countClubs = `-- name: CountClubs :one
+SELECT COUNT(*)
+FROM clubs
+`
+
+func (q *Queries) CountClubs(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countClubs)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countFollowers = `-- name: CountFollowers :one
+SELECT COUNT(*)
+FROM user_follows f
+JOIN users u ON u.id = f.follower_id
+WHERE f.followee_id = $1 AND u.deleted = FALSE
+`
+
+func (q *Queries) CountFollowers(ctx context.Context, followeeID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFollowers, followeeID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countFollowing = `-- name: CountFollowing :one
+SELECT COUNT(*)
+FROM user_follows f
+JOIN users u ON u.id = f.followee_id
+WHERE f.follower_id = $1 AND u.deleted = FALSE
+`
+
+func (q *Queries) CountFollowing(ctx context.Context, followerID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFollowing, followerID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchUsers = `-- name: CountSearchUsers :one
+SELECT COUNT(*)
+FROM users u
+WHERE u.deleted = FALSE
+  AND ($1::text IS NULL OR u.country = $1)
+  AND ($2::text IS NULL OR u.city = $2)
+  AND ($3::text IS NULL OR u.nickname_key LIKE $3 || '%')
+  AND ($4::uuid IS NULL OR EXISTS (
+      SELECT 1 FROM user_clubs uc
+      WHERE uc.user_id = u.id AND uc.club_id = $4
+  ))
+  AND ($5::text IS NULL OR EXISTS (
+      SELECT 1 FROM user_clubs uc
+      JOIN clubs c ON c.id = uc.club_id
+      WHERE uc.user_id = u.id AND c.name = $5
+  ))
+  AND ($6::timestamptz IS NULL OR u.created_at >= $6)
+  AND ($7::timestamptz IS NULL OR u.created_at < $7)
+`
+
+type CountSearchUsersParams struct {
+	Country        sql.NullString
+	City           sql.NullString
+	NicknamePrefix sql.NullString
+	ClubID         uuid.NullUUID
+	ClubName       sql.NullString
+	CreatedFrom    sql.NullTime
+	CreatedTo      sql.NullTime
+}
+
+func (q *Queries) CountSearchUsers(ctx context.Context, arg CountSearchUsersParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchUsers,
+		arg.Country,
+		arg.City,
+		arg.NicknamePrefix,
+		arg.ClubID,
+		arg.ClubName,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUsersInCities = `-- name: CountUsersInCities :one
+SELECT COUNT(*)
+FROM users
+WHERE deleted = FALSE AND city_id = ANY($1::text[])
+`
+
+func (q *Queries) CountUsersInCities(ctx context.Context, cityIds []string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUsersInCities, pq.Array(cityIds))
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countWebhookDeliveries = `-- name: CountWebhookDeliveries :one
+SELECT COUNT(*)
+FROM webhook_deliveries
+WHERE webhook_id = $1
+`
+
+func (q *Queries) CountWebhookDeliveries(ctx context.Context, webhookID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countWebhookDeliveries, webhookID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countWebhooks = `-- name: CountWebhooks :one
+SELECT COUNT(*)
+FROM webhooks
+`
+
+func (q *Queries) CountWebhooks(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countWebhooks)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createClub = `-- name: CreateClub :one
 INSERT INTO clubs (id, name)
 VALUES ($1, $2)
 ON CONFLICT (name) 
 DO UPDATE 
     SET name = EXCLUDED.name
-RETURNING id, name
+RETURNING id, name, join_policy
 `
 
 type CreateClubParams struct {
@@ -45,22 +420,25 @@ type CreateClubParams struct {
 func (q *Queries) CreateClub(ctx context.Context, arg CreateClubParams) (Club, error) {
 	row := q.db.QueryRowContext(ctx, createClub, arg.ID, arg.Name)
 	var i Club
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(&i.ID, &i.Name, &i.JoinPolicy)
 	return i, err
 }
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (id, nickname, img, country, city)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, nickname, img, country, city, created_at, updated_at, deleted
+INSERT INTO users (id, nickname, img, country, city, created_at, updated_at, nickname_key, nickname_skeleton, city_id)
+VALUES ($1, $2, $3, $4, $5, now(), now(), $6, $7, $8)
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count
 `
 
 type CreateUserParams struct {
-	ID       uuid.UUID
-	Nickname string
-	Img      sql.NullString
-	Country  sql.NullString
-	City     sql.NullString
+	ID               uuid.UUID
+	Nickname         string
+	Img              sql.NullString
+	Country          sql.NullString
+	City             sql.NullString
+	NicknameKey      sql.NullString
+	NicknameSkeleton sql.NullString
+	CityID           sql.NullString
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -70,6 +448,9 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.Img,
 		arg.Country,
 		arg.City,
+		arg.NicknameKey,
+		arg.NicknameSkeleton,
+		arg.CityID,
 	)
 	var i User
 	err := row.Scan(
@@ -81,49 +462,1738 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Deleted,
+		&i.Version,
+		&i.NicknameKey,
+		&i.NicknameSkeleton,
+		&i.CityID,
+		&i.FollowerCount,
+		&i.FollowingCount,
+	)
+	return i, err
+}
+
+const createWebhook = `-- name: CreateWebhook :one
+INSERT INTO webhooks (id, url, event_types, secret, active)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, url, event_types, secret, active, created_at, updated_at
+`
+
+type CreateWebhookParams struct {
+	ID         uuid.UUID
+	Url        string
+	EventTypes []string
+	Secret     string
+	Active     bool
+}
+
+func (q *Queries) CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, createWebhook,
+		arg.ID,
+		arg.Url,
+		pq.Array(arg.EventTypes),
+		arg.Secret,
+		arg.Active,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		pq.Array(&i.EventTypes),
+		&i.Secret,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createWebhookDelivery = `-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (id, webhook_id, event_type, payload)
+VALUES ($1, $2, $3, $4)
+RETURNING id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at
+`
+
+type CreateWebhookDeliveryParams struct {
+	ID        uuid.UUID
+	WebhookID uuid.UUID
+	EventType string
+	Payload   json.RawMessage
+}
+
+func (q *Queries) CreateWebhookDelivery(ctx context.Context, arg CreateWebhookDeliveryParams) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, createWebhookDelivery,
+		arg.ID,
+		arg.WebhookID,
+		arg.EventType,
+		arg.Payload,
+	)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.WebhookID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastStatusCode,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
+
+const decideClubRequest = `-- name: DecideClubRequest :one
+UPDATE club_requests
+SET status = $2, decided_by = $3, decided_at = now()
+WHERE id = $1
+RETURNING id, club_id, user_id, kind, status, created_by, decided_by, created_at, expires_at, decided_at
+`
+
+type DecideClubRequestParams struct {
+	ID        uuid.UUID
+	Status    string
+	DecidedBy sql.NullString
+}
+
+func (q *Queries) DecideClubRequest(ctx context.Context, arg DecideClubRequestParams) (ClubRequest, error) {
+	row := q.db.QueryRowContext(ctx, decideClubRequest, arg.ID, arg.Status, arg.DecidedBy)
+	var i ClubRequest
+	err := row.Scan(
+		&i.ID,
+		&i.ClubID,
+		&i.UserID,
+		&i.Kind,
+		&i.Status,
+		&i.CreatedBy,
+		&i.DecidedBy,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.DecidedAt,
 	)
 	return i, err
 }
 
-const deleteUserClubsByUserID = `-- name: DeleteUserClubsByUserID :exec
+const deleteClub = `-- name: DeleteClub :one
+DELETE FROM clubs
+WHERE id = $1
+RETURNING id, name, join_policy
+`
+
+func (q *Queries) DeleteClub(ctx context.Context, id uuid.UUID) (Club, error) {
+	row := q.db.QueryRowContext(ctx, deleteClub, id)
+	var i Club
+	err := row.Scan(&i.ID, &i.Name, &i.JoinPolicy)
+	return i, err
+}
+
+const deleteFollow = `-- name: DeleteFollow :execrows
+DELETE FROM user_follows
+WHERE follower_id = $1 AND followee_id = $2
+`
+
+type DeleteFollowParams struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+}
+
+func (q *Queries) DeleteFollow(ctx context.Context, arg DeleteFollowParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFollow, arg.FollowerID, arg.FolloweeID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteUserClubs = `-- name: DeleteUserClubs :exec
 DELETE FROM user_clubs
-WHERE user_id = $1
+WHERE user_id = $1 AND club_id = ANY($2::uuid[])
 `
 
-func (q *Queries) DeleteUserClubsByUserID(ctx context.Context, userID uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteUserClubsByUserID, userID)
+type DeleteUserClubsParams struct {
+	UserID  uuid.UUID
+	ClubIds []uuid.UUID
+}
+
+func (q *Queries) DeleteUserClubs(ctx context.Context, arg DeleteUserClubsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteUserClubs, arg.UserID, pq.Array(arg.ClubIds))
 	return err
 }
 
-const findUsers = `-- name: FindUsers :many
-SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted
-FROM users u
-WHERE u.deleted = FALSE
-ORDER BY u.created_at DESC
+const deleteWebhook = `-- name: DeleteWebhook :one
+DELETE FROM webhooks
+WHERE id = $1
+RETURNING id, url, event_types, secret, active, created_at, updated_at
+`
+
+func (q *Queries) DeleteWebhook(ctx context.Context, id uuid.UUID) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, deleteWebhook, id)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		pq.Array(&i.EventTypes),
+		&i.Secret,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const expireClubRequests = `-- name: ExpireClubRequests :execrows
+UPDATE club_requests
+SET status = 'expired', decided_at = expires_at
+WHERE status = 'pending' AND expires_at <= now()
+`
+
+func (q *Queries) ExpireClubRequests(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, expireClubRequests)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const exportUsers = `-- name: ExportUsers :many
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
+FROM users u
+WHERE u.deleted = FALSE
+  AND ($1::text IS NULL OR u.country = $1)
+  AND ($2::text IS NULL OR u.city = $2)
+  AND ($3::text IS NULL OR u.nickname_key LIKE $3 || '%')
+  AND ($4::uuid IS NULL OR EXISTS (
+      SELECT 1 FROM user_clubs uc
+      WHERE uc.user_id = u.id AND uc.club_id = $4
+  ))
+  AND ($5::text IS NULL OR EXISTS (
+      SELECT 1 FROM user_clubs uc
+      JOIN clubs c ON c.id = uc.club_id
+      WHERE uc.user_id = u.id AND c.name = $5
+  ))
+  AND ($6::timestamptz IS NULL OR u.created_at >= $6)
+  AND ($7::timestamptz IS NULL OR u.created_at < $7)
+  AND ($8::timestamptz IS NULL
+       OR (u.created_at, u.id) < ($8, $9::uuid))
+ORDER BY u.created_at DESC, u.id DESC
+LIMIT $10
+`
+
+type ExportUsersParams struct {
+	Country        sql.NullString
+	City           sql.NullString
+	NicknamePrefix sql.NullString
+	ClubID         uuid.NullUUID
+	ClubName       sql.NullString
+	CreatedFrom    sql.NullTime
+	CreatedTo      sql.NullTime
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	Limit          int32
+}
+
+func (q *Queries) ExportUsers(ctx context.Context, arg ExportUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, exportUsers,
+		arg.Country,
+		arg.City,
+		arg.NicknamePrefix,
+		arg.ClubID,
+		arg.ClubName,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Nickname,
+			&i.Img,
+			&i.Country,
+			&i.City,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Deleted,
+			&i.Version,
+			&i.NicknameKey,
+			&i.NicknameSkeleton,
+			&i.CityID,
+			&i.FollowerCount,
+			&i.FollowingCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const findUsers = `-- name: FindUsers :many
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
+FROM users u
+WHERE u.deleted = FALSE
+ORDER BY u.created_at DESC
+`
+
+func (q *Queries) FindUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, findUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Nickname,
+			&i.Img,
+			&i.Country,
+			&i.City,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Deleted,
+			&i.Version,
+			&i.NicknameKey,
+			&i.NicknameSkeleton,
+			&i.CityID,
+			&i.FollowerCount,
+			&i.FollowingCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getClubByID = `-- name: GetClubByID :one
+SELECT id, name, join_policy
+FROM clubs
+WHERE id = $1
+`
+
+func (q *Queries) GetClubByID(ctx context.Context, id uuid.UUID) (Club, error) {
+	row := q.db.QueryRowContext(ctx, getClubByID, id)
+	var i Club
+	err := row.Scan(&i.ID, &i.Name, &i.JoinPolicy)
+	return i, err
+}
+
+const getClubMemberForUpdate = `-- name: GetClubMemberForUpdate :one
+SELECT user_id, club_id, role, joined_at
+FROM user_clubs
+WHERE club_id = $1 AND user_id = $2
+FOR UPDATE
+`
+
+type GetClubMemberForUpdateParams struct {
+	ClubID uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) GetClubMemberForUpdate(ctx context.Context, arg GetClubMemberForUpdateParams) (UserClub, error) {
+	row := q.db.QueryRowContext(ctx, getClubMemberForUpdate, arg.ClubID, arg.UserID)
+	var i UserClub
+	err := row.Scan(
+		&i.UserID,
+		&i.ClubID,
+		&i.Role,
+		&i.JoinedAt,
+	)
+	return i, err
+}
+
+const getClubRequestForUpdate = `-- name: GetClubRequestForUpdate :one
+SELECT id, club_id, user_id, kind, status, created_by, decided_by, created_at, expires_at, decided_at
+FROM club_requests
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetClubRequestForUpdate(ctx context.Context, id uuid.UUID) (ClubRequest, error) {
+	row := q.db.QueryRowContext(ctx, getClubRequestForUpdate, id)
+	var i ClubRequest
+	err := row.Scan(
+		&i.ID,
+		&i.ClubID,
+		&i.UserID,
+		&i.Kind,
+		&i.Status,
+		&i.CreatedBy,
+		&i.DecidedBy,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.DecidedAt,
+	)
+	return i, err
+}
+
+const getClubsByUserID = `-- name: GetClubsByUserID :many
+SELECT c.id, c.name, c.join_policy, uc.role, uc.joined_at
+FROM clubs c
+JOIN user_clubs uc ON uc.club_id = c.id
+WHERE uc.user_id = $1
+`
+
+type GetClubsByUserIDRow struct {
+	ID         uuid.UUID
+	Name       string
+	JoinPolicy string
+	Role       string
+	JoinedAt   time.Time
+}
+
+func (q *Queries) GetClubsByUserID(ctx context.Context, userID uuid.UUID) ([]GetClubsByUserIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getClubsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetClubsByUserIDRow
+	for rows.Next() {
+		var i GetClubsByUserIDRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.JoinPolicy,
+			&i.Role,
+			&i.JoinedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getClubsByUserIDs = `-- name: GetClubsByUserIDs :many
+SELECT uc.user_id, c.id, c.name, c.join_policy, uc.role, uc.joined_at
+FROM user_clubs uc
+JOIN clubs c ON c.id = uc.club_id
+WHERE uc.user_id = ANY($1::uuid[])
+ORDER BY c.name
+`
+
+type GetClubsByUserIDsRow struct {
+	UserID     uuid.UUID
+	ID         uuid.UUID
+	Name       string
+	JoinPolicy string
+	Role       string
+	JoinedAt   time.Time
+}
+
+func (q *Queries) GetClubsByUserIDs(ctx context.Context, userIds []uuid.UUID) ([]GetClubsByUserIDsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getClubsByUserIDs, pq.Array(userIds))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetClubsByUserIDsRow
+	for rows.Next() {
+		var i GetClubsByUserIDsRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.ID,
+			&i.Name,
+			&i.JoinPolicy,
+			&i.Role,
+			&i.JoinedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFollow = `-- name: GetFollow :one
+SELECT follower_id, followee_id, created_at
+FROM user_follows
+WHERE follower_id = $1 AND followee_id = $2
+`
+
+type GetFollowParams struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+}
+
+func (q *Queries) GetFollow(ctx context.Context, arg GetFollowParams) (UserFollow, error) {
+	row := q.db.QueryRowContext(ctx, getFollow, arg.FollowerID, arg.FolloweeID)
+	var i UserFollow
+	err := row.Scan(&i.FollowerID, &i.FolloweeID, &i.CreatedAt)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
+FROM users u
+WHERE u.id = $1
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Nickname,
+		&i.Img,
+		&i.Country,
+		&i.City,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.Version,
+		&i.NicknameKey,
+		&i.NicknameSkeleton,
+		&i.CityID,
+		&i.FollowerCount,
+		&i.FollowingCount,
+	)
+	return i, err
+}
+
+const getUserByIDForUpdate = `-- name: GetUserByIDForUpdate :one
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
+FROM users u
+WHERE u.id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetUserByIDForUpdate(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByIDForUpdate, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Nickname,
+		&i.Img,
+		&i.Country,
+		&i.City,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.Version,
+		&i.NicknameKey,
+		&i.NicknameSkeleton,
+		&i.CityID,
+		&i.FollowerCount,
+		&i.FollowingCount,
+	)
+	return i, err
+}
+
+const getUserByNickname = `-- name: GetUserByNickname :one
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
+FROM users u
+WHERE u.nickname_key = $1 AND u.deleted = FALSE
+`
+
+func (q *Queries) GetUserByNickname(ctx context.Context, nicknameKey sql.NullString) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByNickname, nicknameKey)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Nickname,
+		&i.Img,
+		&i.Country,
+		&i.City,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.Version,
+		&i.NicknameKey,
+		&i.NicknameSkeleton,
+		&i.CityID,
+		&i.FollowerCount,
+		&i.FollowingCount,
+	)
+	return i, err
+}
+
+const getUserByPreviousNickname = `-- name: GetUserByPreviousNickname :one
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
+FROM nickname_history nh
+JOIN users u ON u.id = nh.user_id
+WHERE nh.nickname_key = $1 AND nh.changed_at >= $2 AND u.deleted = FALSE
+ORDER BY nh.changed_at DESC
+LIMIT 1
+`
+
+type GetUserByPreviousNicknameParams struct {
+	NicknameKey sql.NullString
+	ChangedAt   time.Time
+}
+
+func (q *Queries) GetUserByPreviousNickname(ctx context.Context, arg GetUserByPreviousNicknameParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByPreviousNickname, arg.NicknameKey, arg.ChangedAt)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Nickname,
+		&i.Img,
+		&i.Country,
+		&i.City,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.Version,
+		&i.NicknameKey,
+		&i.NicknameSkeleton,
+		&i.CityID,
+		&i.FollowerCount,
+		&i.FollowingCount,
+	)
+	return i, err
+}
+
+const getUserImgByNickname = `-- name: GetUserImgByNickname :one
+SELECT img
+FROM users
+WHERE nickname_key = $1 AND deleted = FALSE
+`
+
+func (q *Queries) GetUserImgByNickname(ctx context.Context, nicknameKey sql.NullString) (sql.NullString, error) {
+	row := q.db.QueryRowContext(ctx, getUserImgByNickname, nicknameKey)
+	var img sql.NullString
+	err := row.Scan(&img)
+	return img, err
+}
+
+const getWebhookByID = `-- name: GetWebhookByID :one
+SELECT id, url, event_types, secret, active, created_at, updated_at
+FROM webhooks
+WHERE id = $1
+`
+
+func (q *Queries) GetWebhookByID(ctx context.Context, id uuid.UUID) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookByID, id)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		pq.Array(&i.EventTypes),
+		&i.Secret,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getWebhookDeliveryByID = `-- name: GetWebhookDeliveryByID :one
+SELECT id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at
+FROM webhook_deliveries
+WHERE id = $1
+`
+
+func (q *Queries) GetWebhookDeliveryByID(ctx context.Context, id uuid.UUID) (WebhookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, getWebhookDeliveryByID, id)
+	var i WebhookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.WebhookID,
+		&i.EventType,
+		&i.Payload,
+		&i.Status,
+		&i.Attempts,
+		&i.NextAttemptAt,
+		&i.LastStatusCode,
+		&i.LastError,
+		&i.CreatedAt,
+		&i.DeliveredAt,
+	)
+	return i, err
+}
+
+const insertAuditEntry = `-- name: InsertAuditEntry :exec
+INSERT INTO user_audit (id, user_id, actor, actor_addr, transport, operation, before, after, changes)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+`
+
+type InsertAuditEntryParams struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Actor     string
+	ActorAddr string
+	Transport string
+	Operation string
+	Before    json.RawMessage
+	After     json.RawMessage
+	Changes   json.RawMessage
+}
+
+func (q *Queries) InsertAuditEntry(ctx context.Context, arg InsertAuditEntryParams) error {
+	_, err := q.db.ExecContext(ctx, insertAuditEntry,
+		arg.ID,
+		arg.UserID,
+		arg.Actor,
+		arg.ActorAddr,
+		arg.Transport,
+		arg.Operation,
+		arg.Before,
+		arg.After,
+		arg.Changes,
+	)
+	return err
+}
+
+const insertClub = `-- name: InsertClub :one
+INSERT INTO clubs (id, name, join_policy)
+VALUES ($1, $2, $3)
+RETURNING id, name, join_policy
+`
+
+type InsertClubParams struct {
+	ID         uuid.UUID
+	Name       string
+	JoinPolicy string
+}
+
+func (q *Queries) InsertClub(ctx context.Context, arg InsertClubParams) (Club, error) {
+	row := q.db.QueryRowContext(ctx, insertClub, arg.ID, arg.Name, arg.JoinPolicy)
+	var i Club
+	err := row.Scan(&i.ID, &i.Name, &i.JoinPolicy)
+	return i, err
+}
+
+const insertClubRequest = `-- name: InsertClubRequest :one
+INSERT INTO club_requests (id, club_id, user_id, kind, created_by, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, club_id, user_id, kind, status, created_by, decided_by, created_at, expires_at, decided_at
+`
+
+type InsertClubRequestParams struct {
+	ID        uuid.UUID
+	ClubID    uuid.UUID
+	UserID    uuid.UUID
+	Kind      string
+	CreatedBy string
+	ExpiresAt time.Time
+}
+
+func (q *Queries) InsertClubRequest(ctx context.Context, arg InsertClubRequestParams) (ClubRequest, error) {
+	row := q.db.QueryRowContext(ctx, insertClubRequest,
+		arg.ID,
+		arg.ClubID,
+		arg.UserID,
+		arg.Kind,
+		arg.CreatedBy,
+		arg.ExpiresAt,
+	)
+	var i ClubRequest
+	err := row.Scan(
+		&i.ID,
+		&i.ClubID,
+		&i.UserID,
+		&i.Kind,
+		&i.Status,
+		&i.CreatedBy,
+		&i.DecidedBy,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.DecidedAt,
+	)
+	return i, err
+}
+
+const insertFollow = `-- name: InsertFollow :execrows
+INSERT INTO user_follows (follower_id, followee_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type InsertFollowParams struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+}
+
+func (q *Queries) InsertFollow(ctx context.Context, arg InsertFollowParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, insertFollow, arg.FollowerID, arg.FolloweeID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const insertOutboxEvent = `-- name: InsertOutboxEvent :exec
+INSERT INTO outbox (user_id, event_type, payload)
+VALUES ($1, $2, $3)
+`
+
+type InsertOutboxEventParams struct {
+	UserID    uuid.UUID
+	EventType string
+	Payload   json.RawMessage
+}
+
+func (q *Queries) InsertOutboxEvent(ctx context.Context, arg InsertOutboxEventParams) error {
+	_, err := q.db.ExecContext(ctx, insertOutboxEvent, arg.UserID, arg.EventType, arg.Payload)
+	return err
+}
+
+const isClubManager = `-- name: IsClubManager :one
+SELECT EXISTS (
+    SELECT 1 FROM user_clubs uc
+    JOIN users u ON u.id = uc.user_id
+    WHERE uc.club_id = $1 AND uc.user_id = $2
+      AND uc.role IN ('owner', 'moderator') AND u.deleted = FALSE
+)
+`
+
+type IsClubManagerParams struct {
+	ClubID uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) IsClubManager(ctx context.Context, arg IsClubManagerParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isClubManager, arg.ClubID, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isClubMember = `-- name: IsClubMember :one
+SELECT EXISTS (
+    SELECT 1 FROM user_clubs
+    WHERE club_id = $1 AND user_id = $2
+)
+`
+
+type IsClubMemberParams struct {
+	ClubID uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) IsClubMember(ctx context.Context, arg IsClubMemberParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isClubMember, arg.ClubID, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isNicknameConfusable = `-- name: IsNicknameConfusable :one
+SELECT EXISTS (
+    SELECT 1
+    FROM users
+    WHERE nickname_skeleton = $1 AND id <> $2 AND deleted = FALSE
+    UNION ALL
+    SELECT 1
+    FROM nickname_history
+    WHERE nickname_skeleton = $1 AND user_id <> $2
+      AND changed_at >= $3
+)
+`
+
+type IsNicknameConfusableParams struct {
+	NicknameSkeleton sql.NullString
+	UserID           uuid.UUID
+	ChangedAt        time.Time
+}
+
+func (q *Queries) IsNicknameConfusable(ctx context.Context, arg IsNicknameConfusableParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isNicknameConfusable, arg.NicknameSkeleton, arg.UserID, arg.ChangedAt)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isNicknameReserved = `-- name: IsNicknameReserved :one
+SELECT EXISTS (
+    SELECT 1
+    FROM nickname_history
+    WHERE nickname_key = $1 AND user_id <> $2 AND changed_at >= $3
+)
+`
+
+type IsNicknameReservedParams struct {
+	NicknameKey sql.NullString
+	UserID      uuid.UUID
+	ChangedAt   time.Time
+}
+
+func (q *Queries) IsNicknameReserved(ctx context.Context, arg IsNicknameReservedParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isNicknameReserved, arg.NicknameKey, arg.UserID, arg.ChangedAt)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isNicknameTaken = `-- name: IsNicknameTaken :one
+SELECT EXISTS (
+    SELECT 1
+    FROM users
+    WHERE nickname_key = $1 AND id <> $2 AND deleted = FALSE
+)
+`
+
+type IsNicknameTakenParams struct {
+	NicknameKey sql.NullString
+	ID          uuid.UUID
+}
+
+func (q *Queries) IsNicknameTaken(ctx context.Context, arg IsNicknameTakenParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isNicknameTaken, arg.NicknameKey, arg.ID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listAuditEntries = `-- name: ListAuditEntries :many
+SELECT id, user_id, actor, transport, operation, before, after, changes, created_at, actor_addr
+FROM user_audit
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListAuditEntriesParams struct {
+	UserID uuid.UUID
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListAuditEntries(ctx context.Context, arg ListAuditEntriesParams) ([]UserAudit, error) {
+	rows, err := q.db.QueryContext(ctx, listAuditEntries, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserAudit
+	for rows.Next() {
+		var i UserAudit
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Actor,
+			&i.Transport,
+			&i.Operation,
+			&i.Before,
+			&i.After,
+			&i.Changes,
+			&i.CreatedAt,
+			&i.ActorAddr,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listClubMembers = `-- name: ListClubMembers :many
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count, uc.role, uc.joined_at
+FROM users u
+JOIN user_clubs uc ON uc.user_id = u.id
+WHERE uc.club_id = $1 AND u.deleted = FALSE
+ORDER BY CASE uc.role WHEN 'owner' THEN 0 WHEN 'moderator' THEN 1 ELSE 2 END, u.nickname
+LIMIT $2 OFFSET $3
+`
+
+type ListClubMembersRow struct {
+	ID               uuid.UUID
+	Nickname         string
+	Img              sql.NullString
+	Country          sql.NullString
+	City             sql.NullString
+	CreatedAt        sql.NullTime
+	UpdatedAt        sql.NullTime
+	Deleted          sql.NullBool
+	Version          int32
+	NicknameKey      sql.NullString
+	NicknameSkeleton sql.NullString
+	CityID           sql.NullString
+	FollowerCount    int32
+	FollowingCount   int32
+	Role             string
+	JoinedAt         time.Time
+}
+
+type ListClubMembersParams struct {
+	ClubID uuid.UUID
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListClubMembers(ctx context.Context, arg ListClubMembersParams) ([]ListClubMembersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listClubMembers, arg.ClubID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListClubMembersRow
+	for rows.Next() {
+		var i ListClubMembersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Nickname,
+			&i.Img,
+			&i.Country,
+			&i.City,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Deleted,
+			&i.Version,
+			&i.NicknameKey,
+			&i.NicknameSkeleton,
+			&i.CityID,
+			&i.FollowerCount,
+			&i.FollowingCount,
+			&i.Role,
+			&i.JoinedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listClubRequests = `-- name: ListClubRequests :many
+SELECT id, club_id, user_id, kind, status, created_by, decided_by, created_at, expires_at, decided_at
+FROM club_requests
+WHERE ($1::uuid IS NULL OR club_id = $1)
+  AND ($2::uuid IS NULL OR user_id = $2)
+  AND ($3::text IS NULL OR kind = $3)
+  AND ($4::text IS NULL OR status = $4)
+ORDER BY created_at DESC, id DESC
+LIMIT $5 OFFSET $6
+`
+
+type ListClubRequestsParams struct {
+	ClubID uuid.NullUUID
+	UserID uuid.NullUUID
+	Kind   sql.NullString
+	Status sql.NullString
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListClubRequests(ctx context.Context, arg ListClubRequestsParams) ([]ClubRequest, error) {
+	rows, err := q.db.QueryContext(ctx, listClubRequests,
+		arg.ClubID,
+		arg.UserID,
+		arg.Kind,
+		arg.Status,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ClubRequest
+	for rows.Next() {
+		var i ClubRequest
+		if err := rows.Scan(
+			&i.ID,
+			&i.ClubID,
+			&i.UserID,
+			&i.Kind,
+			&i.Status,
+			&i.CreatedBy,
+			&i.DecidedBy,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.DecidedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listClubs = `-- name: ListClubs :many
+SELECT id, name, join_policy
+FROM clubs
+ORDER BY name
+LIMIT $1 OFFSET $2
+`
+
+type ListClubsParams struct {
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListClubs(ctx context.Context, arg ListClubsParams) ([]Club, error) {
+	rows, err := q.db.QueryContext(ctx, listClubs, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Club
+	for rows.Next() {
+		var i Club
+		if err := rows.Scan(&i.ID, &i.Name, &i.JoinPolicy); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDistinctCountries = `-- name: ListDistinctCountries :many
+SELECT DISTINCT country
+FROM users
+WHERE country IS NOT NULL AND country <> ''
+ORDER BY country
+`
+
+func (q *Queries) ListDistinctCountries(ctx context.Context) ([]sql.NullString, error) {
+	rows, err := q.db.QueryContext(ctx, listDistinctCountries)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []sql.NullString
+	for rows.Next() {
+		var country sql.NullString
+		if err := rows.Scan(&country); err != nil {
+			return nil, err
+		}
+		items = append(items, country)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFollowers = `-- name: ListFollowers :many
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count, f.created_at AS followed_at
+FROM user_follows f
+JOIN users u ON u.id = f.follower_id
+WHERE f.followee_id = $1 AND u.deleted = FALSE
+ORDER BY f.created_at DESC, u.id DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListFollowersRow struct {
+	ID               uuid.UUID
+	Nickname         string
+	Img              sql.NullString
+	Country          sql.NullString
+	City             sql.NullString
+	CreatedAt        sql.NullTime
+	UpdatedAt        sql.NullTime
+	Deleted          sql.NullBool
+	Version          int32
+	NicknameKey      sql.NullString
+	NicknameSkeleton sql.NullString
+	CityID           sql.NullString
+	FollowerCount    int32
+	FollowingCount   int32
+	FollowedAt       time.Time
+}
+
+type ListFollowersParams struct {
+	FolloweeID uuid.UUID
+	Limit      int32
+	Offset     int32
+}
+
+func (q *Queries) ListFollowers(ctx context.Context, arg ListFollowersParams) ([]ListFollowersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFollowers, arg.FolloweeID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFollowersRow
+	for rows.Next() {
+		var i ListFollowersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Nickname,
+			&i.Img,
+			&i.Country,
+			&i.City,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Deleted,
+			&i.Version,
+			&i.NicknameKey,
+			&i.NicknameSkeleton,
+			&i.CityID,
+			&i.FollowerCount,
+			&i.FollowingCount,
+			&i.FollowedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFollowing = `-- name: ListFollowing :many
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count, f.created_at AS followed_at
+FROM user_follows f
+JOIN users u ON u.id = f.followee_id
+WHERE f.follower_id = $1 AND u.deleted = FALSE
+ORDER BY f.created_at DESC, u.id DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListFollowingRow struct {
+	ID               uuid.UUID
+	Nickname         string
+	Img              sql.NullString
+	Country          sql.NullString
+	City             sql.NullString
+	CreatedAt        sql.NullTime
+	UpdatedAt        sql.NullTime
+	Deleted          sql.NullBool
+	Version          int32
+	NicknameKey      sql.NullString
+	NicknameSkeleton sql.NullString
+	CityID           sql.NullString
+	FollowerCount    int32
+	FollowingCount   int32
+	FollowedAt       time.Time
+}
+
+type ListFollowingParams struct {
+	FollowerID uuid.UUID
+	Limit      int32
+	Offset     int32
+}
+
+func (q *Queries) ListFollowing(ctx context.Context, arg ListFollowingParams) ([]ListFollowingRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFollowing, arg.FollowerID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFollowingRow
+	for rows.Next() {
+		var i ListFollowingRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Nickname,
+			&i.Img,
+			&i.Country,
+			&i.City,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Deleted,
+			&i.Version,
+			&i.NicknameKey,
+			&i.NicknameSkeleton,
+			&i.CityID,
+			&i.FollowerCount,
+			&i.FollowingCount,
+			&i.FollowedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNicknameHistoryWithoutKey = `-- name: ListNicknameHistoryWithoutKey :many
+SELECT id, nickname
+FROM nickname_history
+WHERE nickname_key IS NULL AND id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListNicknameHistoryWithoutKeyRow struct {
+	ID       uuid.UUID
+	Nickname string
+}
+
+type ListNicknameHistoryWithoutKeyParams struct {
+	ID    uuid.UUID
+	Limit int32
+}
+
+func (q *Queries) ListNicknameHistoryWithoutKey(ctx context.Context, arg ListNicknameHistoryWithoutKeyParams) ([]ListNicknameHistoryWithoutKeyRow, error) {
+	rows, err := q.db.QueryContext(ctx, listNicknameHistoryWithoutKey, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListNicknameHistoryWithoutKeyRow
+	for rows.Next() {
+		var i ListNicknameHistoryWithoutKeyRow
+		if err := rows.Scan(&i.ID, &i.Nickname); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
+FROM users u
+WHERE u.deleted = FALSE
+ORDER BY u.created_at DESC, u.id DESC
+LIMIT $1 OFFSET $2
+`
+
+type ListUsersParams struct {
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsers, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Nickname,
+			&i.Img,
+			&i.Country,
+			&i.City,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Deleted,
+			&i.Version,
+			&i.NicknameKey,
+			&i.NicknameSkeleton,
+			&i.CityID,
+			&i.FollowerCount,
+			&i.FollowingCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersAfter = `-- name: ListUsersAfter :many
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
+FROM users u
+WHERE u.deleted = FALSE
+  AND (u.created_at, u.id) < ($1::timestamptz, $2::uuid)
+ORDER BY u.created_at DESC, u.id DESC
+LIMIT $3
+`
+
+type ListUsersAfterParams struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+	Limit     int32
+}
+
+func (q *Queries) ListUsersAfter(ctx context.Context, arg ListUsersAfterParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersAfter, arg.CreatedAt, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Nickname,
+			&i.Img,
+			&i.Country,
+			&i.City,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Deleted,
+			&i.Version,
+			&i.NicknameKey,
+			&i.NicknameSkeleton,
+			&i.CityID,
+			&i.FollowerCount,
+			&i.FollowingCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersInCities = `-- name: ListUsersInCities :many
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
+FROM users u
+JOIN unnest($1::text[]) WITH ORDINALITY AS c(id, rank) ON c.id = u.city_id
+WHERE u.deleted = FALSE
+ORDER BY c.rank, u.created_at, u.id
+LIMIT $2 OFFSET $3
+`
+
+type ListUsersInCitiesParams struct {
+	CityIds []string
+	Limit   int32
+	Offset  int32
+}
+
+func (q *Queries) ListUsersInCities(ctx context.Context, arg ListUsersInCitiesParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersInCities, pq.Array(arg.CityIds), arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.Nickname,
+			&i.Img,
+			&i.Country,
+			&i.City,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Deleted,
+			&i.Version,
+			&i.NicknameKey,
+			&i.NicknameSkeleton,
+			&i.CityID,
+			&i.FollowerCount,
+			&i.FollowingCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersWithoutCityID = `-- name: ListUsersWithoutCityID :many
+SELECT id, country, city
+FROM users
+WHERE city_id IS NULL AND city IS NOT NULL AND city <> '' AND id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListUsersWithoutCityIDRow struct {
+	ID      uuid.UUID
+	Country sql.NullString
+	City    sql.NullString
+}
+
+type ListUsersWithoutCityIDParams struct {
+	ID    uuid.UUID
+	Limit int32
+}
+
+func (q *Queries) ListUsersWithoutCityID(ctx context.Context, arg ListUsersWithoutCityIDParams) ([]ListUsersWithoutCityIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersWithoutCityID, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersWithoutCityIDRow
+	for rows.Next() {
+		var i ListUsersWithoutCityIDRow
+		if err := rows.Scan(&i.ID, &i.Country, &i.City); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersWithoutNicknameKey = `-- name: ListUsersWithoutNicknameKey :many
+SELECT id, nickname
+FROM users
+WHERE nickname_key IS NULL AND id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListUsersWithoutNicknameKeyRow struct {
+	ID       uuid.UUID
+	Nickname string
+}
+
+type ListUsersWithoutNicknameKeyParams struct {
+	ID    uuid.UUID
+	Limit int32
+}
+
+func (q *Queries) ListUsersWithoutNicknameKey(ctx context.Context, arg ListUsersWithoutNicknameKeyParams) ([]ListUsersWithoutNicknameKeyRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersWithoutNicknameKey, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersWithoutNicknameKeyRow
+	for rows.Next() {
+		var i ListUsersWithoutNicknameKeyRow
+		if err := rows.Scan(&i.ID, &i.Nickname); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhookDeliveries = `-- name: ListWebhookDeliveries :many
+SELECT id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at
+FROM webhook_deliveries
+WHERE webhook_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListWebhookDeliveriesParams struct {
+	WebhookID uuid.UUID
+	Limit     int32
+	Offset    int32
+}
+
+func (q *Queries) ListWebhookDeliveries(ctx context.Context, arg ListWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhookDeliveries, arg.WebhookID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebhookDelivery
+	for rows.Next() {
+		var i WebhookDelivery
+		if err := rows.Scan(
+			&i.ID,
+			&i.WebhookID,
+			&i.EventType,
+			&i.Payload,
+			&i.Status,
+			&i.Attempts,
+			&i.NextAttemptAt,
+			&i.LastStatusCode,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhooks = `-- name: ListWebhooks :many
+SELECT id, url, event_types, secret, active, created_at, updated_at
+FROM webhooks
+ORDER BY created_at, id
+LIMIT $1 OFFSET $2
+`
+
+type ListWebhooksParams struct {
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListWebhooks(ctx context.Context, arg ListWebhooksParams) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhooks, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			pq.Array(&i.EventTypes),
+			&i.Secret,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebhooksForEvent = `-- name: ListWebhooksForEvent :many
+SELECT id, url, event_types, secret, active, created_at, updated_at
+FROM webhooks
+WHERE active = TRUE AND $1::text = ANY(event_types)
+`
+
+func (q *Queries) ListWebhooksForEvent(ctx context.Context, eventType string) ([]Webhook, error) {
+	rows, err := q.db.QueryContext(ctx, listWebhooksForEvent, eventType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Webhook
+	for rows.Next() {
+		var i Webhook
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			pq.Array(&i.EventTypes),
+			&i.Secret,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockClubOwners = `-- name: LockClubOwners :many
+SELECT user_id
+FROM user_clubs
+WHERE club_id = $1 AND role = 'owner'
+ORDER BY user_id
+FOR UPDATE
 `
 
-func (q *Queries) FindUsers(ctx context.Context) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, findUsers)
+func (q *Queries) LockClubOwners(ctx context.Context, clubID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, lockClubOwners, clubID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []User
+	var items []uuid.UUID
 	for rows.Next() {
-		var i User
-		if err := rows.Scan(
-			&i.ID,
-			&i.Nickname,
-			&i.Img,
-			&i.Country,
-			&i.City,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Deleted,
-		); err != nil {
+		var user_id uuid.UUID
+		if err := rows.Scan(&user_id); err != nil {
 			return nil, err
 		}
-		items = append(items, i)
+		items = append(items, user_id)
 	}
 	if err := rows.Close(); err != nil {
 		return nil, err
@@ -134,23 +2204,29 @@ func (q *Queries) FindUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
-const getClubsByUserID = `-- name: GetClubsByUserID :many
-SELECT c.id, c.name
-FROM clubs c
-JOIN user_clubs uc ON uc.club_id = c.id
-WHERE uc.user_id = $1
+const lockUsers = `-- name: LockUsers :many
+SELECT id, deleted
+FROM users
+WHERE id = ANY($1::uuid[])
+ORDER BY id
+FOR UPDATE
 `
 
-func (q *Queries) GetClubsByUserID(ctx context.Context, userID uuid.UUID) ([]Club, error) {
-	rows, err := q.db.QueryContext(ctx, getClubsByUserID, userID)
+type LockUsersRow struct {
+	ID      uuid.UUID
+	Deleted sql.NullBool
+}
+
+func (q *Queries) LockUsers(ctx context.Context, ids []uuid.UUID) ([]LockUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, lockUsers, pq.Array(ids))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Club
+	var items []LockUsersRow
 	for rows.Next() {
-		var i Club
-		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+		var i LockUsersRow
+		if err := rows.Scan(&i.ID, &i.Deleted); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -164,14 +2240,72 @@ func (q *Queries) GetClubsByUserID(ctx context.Context, userID uuid.UUID) ([]Clu
 	return items, nil
 }
 
-const getUserByID = `-- name: GetUserByID :one
-SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted
-FROM users u
-WHERE u.id = $1
+const markOutboxEventFailed = `-- name: MarkOutboxEventFailed :exec
+UPDATE outbox
+SET attempts = attempts + 1,
+    last_error = $1,
+    next_attempt_at = $2
+WHERE id = $3
 `
 
-func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByID, id)
+type MarkOutboxEventFailedParams struct {
+	LastError     sql.NullString
+	NextAttemptAt time.Time
+	ID            int64
+}
+
+func (q *Queries) MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) error {
+	_, err := q.db.ExecContext(ctx, markOutboxEventFailed, arg.LastError, arg.NextAttemptAt, arg.ID)
+	return err
+}
+
+const markOutboxEventPublished = `-- name: MarkOutboxEventPublished :exec
+UPDATE outbox
+SET published_at = now(),
+    attempts = attempts + 1,
+    last_error = NULL
+WHERE id = $1
+`
+
+func (q *Queries) MarkOutboxEventPublished(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markOutboxEventPublished, id)
+	return err
+}
+
+const patchUser = `-- name: PatchUser :one
+UPDATE users
+SET country = CASE WHEN $1::boolean THEN $2::text ELSE country END,
+    city = CASE WHEN $3::boolean THEN $4::text ELSE city END,
+    city_id = CASE WHEN $5::boolean THEN $6::text ELSE city_id END,
+    updated_at = now(),
+    version = version + 1
+WHERE id = $7 AND deleted = FALSE
+  AND ($8::int IS NULL OR version = $8)
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count
+`
+
+type PatchUserParams struct {
+	SetCountry      bool
+	Country         sql.NullString
+	SetCity         bool
+	City            sql.NullString
+	SetCityID       bool
+	CityID          sql.NullString
+	ID              uuid.UUID
+	ExpectedVersion sql.NullInt32
+}
+
+func (q *Queries) PatchUser(ctx context.Context, arg PatchUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, patchUser,
+		arg.SetCountry,
+		arg.Country,
+		arg.SetCity,
+		arg.City,
+		arg.SetCityID,
+		arg.CityID,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -182,38 +2316,221 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Deleted,
+		&i.Version,
+		&i.NicknameKey,
+		&i.NicknameSkeleton,
+		&i.CityID,
+		&i.FollowerCount,
+		&i.FollowingCount,
 	)
 	return i, err
 }
 
-const getUserImgByNickname = `-- name: GetUserImgByNickname :one
-SELECT img
-FROM users
-WHERE nickname = $1 AND deleted = FALSE
+const promoteClubSuccessors = `-- name: PromoteClubSuccessors :exec
+UPDATE user_clubs uc
+SET role = 'owner'
+FROM (
+    SELECT DISTINCT ON (m.club_id) m.club_id, m.user_id
+    FROM user_clubs m
+    JOIN users u ON u.id = m.user_id
+    WHERE m.club_id = ANY($1::uuid[])
+      AND m.user_id <> $2
+      AND NOT EXISTS (
+          SELECT 1 FROM user_clubs o
+          JOIN users ou ON ou.id = o.user_id
+          WHERE o.club_id = m.club_id AND o.role = 'owner' AND o.user_id <> $2
+            AND ou.deleted = FALSE
+      )
+    ORDER BY m.club_id, u.deleted, CASE m.role WHEN 'moderator' THEN 0 ELSE 1 END, m.joined_at, m.user_id
+) successor
+WHERE uc.club_id = successor.club_id AND uc.user_id = successor.user_id
 `
 
-func (q *Queries) GetUserImgByNickname(ctx context.Context, nickname string) (sql.NullString, error) {
-	row := q.db.QueryRowContext(ctx, getUserImgByNickname, nickname)
-	var img sql.NullString
-	err := row.Scan(&img)
-	return img, err
+type PromoteClubSuccessorsParams struct {
+	ClubIds []uuid.UUID
+	UserID  uuid.UUID
 }
 
-const listUsers = `-- name: ListUsers :many
-SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted
+func (q *Queries) PromoteClubSuccessors(ctx context.Context, arg PromoteClubSuccessorsParams) error {
+	_, err := q.db.ExecContext(ctx, promoteClubSuccessors, pq.Array(arg.ClubIds), arg.UserID)
+	return err
+}
+
+const purgeUser = `-- name: PurgeUser :one
+DELETE FROM users
+WHERE id = $1
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count
+`
+
+func (q *Queries) PurgeUser(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, purgeUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Nickname,
+		&i.Img,
+		&i.Country,
+		&i.City,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.Version,
+		&i.NicknameKey,
+		&i.NicknameSkeleton,
+		&i.CityID,
+		&i.FollowerCount,
+		&i.FollowingCount,
+	)
+	return i, err
+}
+
+const recordWebhookDeliveryAttempt = `-- name: RecordWebhookDeliveryAttempt :exec
+UPDATE webhook_deliveries
+SET attempts = attempts + 1,
+    status = $1,
+    last_status_code = $2,
+    last_error = $3,
+    next_attempt_at = $4,
+    delivered_at = CASE WHEN $1 = 'succeeded' THEN now() ELSE delivered_at END
+WHERE id = $5
+`
+
+type RecordWebhookDeliveryAttemptParams struct {
+	Status         string
+	LastStatusCode sql.NullInt32
+	LastError      sql.NullString
+	NextAttemptAt  time.Time
+	ID             uuid.UUID
+}
+
+func (q *Queries) RecordWebhookDeliveryAttempt(ctx context.Context, arg RecordWebhookDeliveryAttemptParams) error {
+	_, err := q.db.ExecContext(ctx, recordWebhookDeliveryAttempt,
+		arg.Status,
+		arg.LastStatusCode,
+		arg.LastError,
+		arg.NextAttemptAt,
+		arg.ID,
+	)
+	return err
+}
+
+const renameClub = `-- name: RenameClub :one
+UPDATE clubs
+SET name = $2
+WHERE id = $1
+RETURNING id, name, join_policy
+`
+
+type RenameClubParams struct {
+	ID   uuid.UUID
+	Name string
+}
+
+func (q *Queries) RenameClub(ctx context.Context, arg RenameClubParams) (Club, error) {
+	row := q.db.QueryRowContext(ctx, renameClub, arg.ID, arg.Name)
+	var i Club
+	err := row.Scan(&i.ID, &i.Name, &i.JoinPolicy)
+	return i, err
+}
+
+const replaceUserCountry = `-- name: ReplaceUserCountry :execrows
+UPDATE users
+SET country = $1,
+    updated_at = now(),
+    version = version + 1
+WHERE country = $2
+`
+
+type ReplaceUserCountryParams struct {
+	Code    sql.NullString
+	Country sql.NullString
+}
+
+func (q *Queries) ReplaceUserCountry(ctx context.Context, arg ReplaceUserCountryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, replaceUserCountry, arg.Code, arg.Country)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET deleted = FALSE,
+    updated_at = now(),
+    version = version + 1
+WHERE id = $1 AND deleted = TRUE
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, restoreUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Nickname,
+		&i.Img,
+		&i.Country,
+		&i.City,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.Version,
+		&i.NicknameKey,
+		&i.NicknameSkeleton,
+		&i.CityID,
+		&i.FollowerCount,
+		&i.FollowingCount,
+	)
+	return i, err
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT u.id, u.nickname, u.img, u.country, u.city, u.created_at, u.updated_at, u.deleted, u.version, u.nickname_key, u.nickname_skeleton, u.city_id, u.follower_count, u.following_count
 FROM users u
 WHERE u.deleted = FALSE
-ORDER BY u.created_at DESC
-LIMIT $1 OFFSET $2
+  AND ($1::text IS NULL OR u.country = $1)
+  AND ($2::text IS NULL OR u.city = $2)
+  AND ($3::text IS NULL OR u.nickname_key LIKE $3 || '%')
+  AND ($4::uuid IS NULL OR EXISTS (
+      SELECT 1 FROM user_clubs uc
+      WHERE uc.user_id = u.id AND uc.club_id = $4
+  ))
+  AND ($5::text IS NULL OR EXISTS (
+      SELECT 1 FROM user_clubs uc
+      JOIN clubs c ON c.id = uc.club_id
+      WHERE uc.user_id = u.id AND c.name = $5
+  ))
+  AND ($6::timestamptz IS NULL OR u.created_at >= $6)
+  AND ($7::timestamptz IS NULL OR u.created_at < $7)
+ORDER BY u.created_at DESC, u.id DESC
+LIMIT $8 OFFSET $9
 `
 
-type ListUsersParams struct {
-	Limit  int32
-	Offset int32
+type SearchUsersParams struct {
+	Country        sql.NullString
+	City           sql.NullString
+	NicknamePrefix sql.NullString
+	ClubID         uuid.NullUUID
+	ClubName       sql.NullString
+	CreatedFrom    sql.NullTime
+	CreatedTo      sql.NullTime
+	Limit          int32
+	Offset         int32
 }
 
-func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
-	rows, err := q.db.QueryContext(ctx, listUsers, arg.Limit, arg.Offset)
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, searchUsers,
+		arg.Country,
+		arg.City,
+		arg.NicknamePrefix,
+		arg.ClubID,
+		arg.ClubName,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -230,6 +2547,12 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Deleted,
+			&i.Version,
+			&i.NicknameKey,
+			&i.NicknameSkeleton,
+			&i.CityID,
+			&i.FollowerCount,
+			&i.FollowingCount,
 		); err != nil {
 			return nil, err
 		}
@@ -244,12 +2567,109 @@ func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, e
 	return items, nil
 }
 
+const setClubJoinPolicy = `-- name: SetClubJoinPolicy :one
+UPDATE clubs
+SET join_policy = $2
+WHERE id = $1
+RETURNING id, name, join_policy
+`
+
+type SetClubJoinPolicyParams struct {
+	ID         uuid.UUID
+	JoinPolicy string
+}
+
+func (q *Queries) SetClubJoinPolicy(ctx context.Context, arg SetClubJoinPolicyParams) (Club, error) {
+	row := q.db.QueryRowContext(ctx, setClubJoinPolicy, arg.ID, arg.JoinPolicy)
+	var i Club
+	err := row.Scan(&i.ID, &i.Name, &i.JoinPolicy)
+	return i, err
+}
+
+const setClubMemberRole = `-- name: SetClubMemberRole :one
+UPDATE user_clubs
+SET role = $3
+WHERE club_id = $1 AND user_id = $2
+RETURNING user_id, club_id, role, joined_at
+`
+
+type SetClubMemberRoleParams struct {
+	ClubID uuid.UUID
+	UserID uuid.UUID
+	Role   string
+}
+
+func (q *Queries) SetClubMemberRole(ctx context.Context, arg SetClubMemberRoleParams) (UserClub, error) {
+	row := q.db.QueryRowContext(ctx, setClubMemberRole, arg.ClubID, arg.UserID, arg.Role)
+	var i UserClub
+	err := row.Scan(
+		&i.UserID,
+		&i.ClubID,
+		&i.Role,
+		&i.JoinedAt,
+	)
+	return i, err
+}
+
+const setNicknameHistoryKey = `-- name: SetNicknameHistoryKey :exec
+UPDATE nickname_history
+SET nickname_key = $2,
+    nickname_skeleton = $3
+WHERE id = $1
+`
+
+type SetNicknameHistoryKeyParams struct {
+	ID               uuid.UUID
+	NicknameKey      sql.NullString
+	NicknameSkeleton sql.NullString
+}
+
+func (q *Queries) SetNicknameHistoryKey(ctx context.Context, arg SetNicknameHistoryKeyParams) error {
+	_, err := q.db.ExecContext(ctx, setNicknameHistoryKey, arg.ID, arg.NicknameKey, arg.NicknameSkeleton)
+	return err
+}
+
+const setUserCityID = `-- name: SetUserCityID :exec
+UPDATE users
+SET city_id = $2
+WHERE id = $1
+`
+
+type SetUserCityIDParams struct {
+	ID     uuid.UUID
+	CityID sql.NullString
+}
+
+func (q *Queries) SetUserCityID(ctx context.Context, arg SetUserCityIDParams) error {
+	_, err := q.db.ExecContext(ctx, setUserCityID, arg.ID, arg.CityID)
+	return err
+}
+
+const setUserNicknameKey = `-- name: SetUserNicknameKey :exec
+UPDATE users
+SET nickname_key = $2,
+    nickname_skeleton = $3
+WHERE id = $1
+`
+
+type SetUserNicknameKeyParams struct {
+	ID               uuid.UUID
+	NicknameKey      sql.NullString
+	NicknameSkeleton sql.NullString
+}
+
+func (q *Queries) SetUserNicknameKey(ctx context.Context, arg SetUserNicknameKeyParams) error {
+	_, err := q.db.ExecContext(ctx, setUserNicknameKey, arg.ID, arg.NicknameKey, arg.NicknameSkeleton)
+	return err
+}
+
 const softDeleteUser = `-- name: SoftDeleteUser :one
 UPDATE users
 SET deleted = TRUE,
-    updated_at = now()
+    updated_at = now(),
+    version = version + 1
 WHERE id = $1
-RETURNING id, nickname, img, country, city, created_at, updated_at, deleted
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count
 `
 
 func (q *Queries) SoftDeleteUser(ctx context.Context, id uuid.UUID) (User, error) {
@@ -264,27 +2684,74 @@ func (q *Queries) SoftDeleteUser(ctx context.Context, id uuid.UUID) (User, error
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Deleted,
+		&i.Version,
+		&i.NicknameKey,
+		&i.NicknameSkeleton,
+		&i.CityID,
+		&i.FollowerCount,
+		&i.FollowingCount,
 	)
 	return i, err
 }
 
-const updateUser = `-- name: UpdateUser :one
+const touchUser = `-- name: TouchUser :one
 UPDATE users
-SET country = $2,
-    city = $3,
-    updated_at = now()
+SET updated_at = now(),
+    version = version + 1
 WHERE id = $1 AND deleted = FALSE
-RETURNING id, nickname, img, country, city, created_at, updated_at, deleted
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count
+`
+
+func (q *Queries) TouchUser(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, touchUser, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Nickname,
+		&i.Img,
+		&i.Country,
+		&i.City,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.Version,
+		&i.NicknameKey,
+		&i.NicknameSkeleton,
+		&i.CityID,
+		&i.FollowerCount,
+		&i.FollowingCount,
+	)
+	return i, err
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE users
+SET country = $1,
+    city = $2,
+    city_id = $3,
+    updated_at = now(),
+    version = version + 1
+WHERE id = $4 AND deleted = FALSE
+  AND ($5::int IS NULL OR version = $5)
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count
 `
 
 type UpdateUserParams struct {
-	ID      uuid.UUID
-	Country sql.NullString
-	City    sql.NullString
+	Country         sql.NullString
+	City            sql.NullString
+	CityID          sql.NullString
+	ID              uuid.UUID
+	ExpectedVersion sql.NullInt32
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, updateUser, arg.ID, arg.Country, arg.City)
+	row := q.db.QueryRowContext(ctx, updateUser,
+		arg.Country,
+		arg.City,
+		arg.CityID,
+		arg.ID,
+		arg.ExpectedVersion,
+	)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -295,6 +2762,12 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Deleted,
+		&i.Version,
+		&i.NicknameKey,
+		&i.NicknameSkeleton,
+		&i.CityID,
+		&i.FollowerCount,
+		&i.FollowingCount,
 	)
 	return i, err
 }
@@ -302,9 +2775,10 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 const updateUserImg = `-- name: UpdateUserImg :one
 UPDATE users
 SET img = $2,
-    updated_at = now()
+    updated_at = now(),
+    version = version + 1
 WHERE id = $1 AND deleted = FALSE
-RETURNING id, nickname, img, country, city, created_at, updated_at, deleted
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count
 `
 
 type UpdateUserImgParams struct {
@@ -324,6 +2798,97 @@ func (q *Queries) UpdateUserImg(ctx context.Context, arg UpdateUserImgParams) (U
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Deleted,
+		&i.Version,
+		&i.NicknameKey,
+		&i.NicknameSkeleton,
+		&i.CityID,
+		&i.FollowerCount,
+		&i.FollowingCount,
+	)
+	return i, err
+}
+
+const updateUserNickname = `-- name: UpdateUserNickname :one
+UPDATE users
+SET nickname = $2,
+    nickname_key = $3,
+    nickname_skeleton = $4,
+    updated_at = now(),
+    version = version + 1
+WHERE id = $1 AND deleted = FALSE
+RETURNING id, nickname, img, country, city, created_at, updated_at, deleted, version, nickname_key, nickname_skeleton, city_id, follower_count, following_count
+`
+
+type UpdateUserNicknameParams struct {
+	ID               uuid.UUID
+	Nickname         string
+	NicknameKey      sql.NullString
+	NicknameSkeleton sql.NullString
+}
+
+func (q *Queries) UpdateUserNickname(ctx context.Context, arg UpdateUserNicknameParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserNickname,
+		arg.ID,
+		arg.Nickname,
+		arg.NicknameKey,
+		arg.NicknameSkeleton,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Nickname,
+		&i.Img,
+		&i.Country,
+		&i.City,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Deleted,
+		&i.Version,
+		&i.NicknameKey,
+		&i.NicknameSkeleton,
+		&i.CityID,
+		&i.FollowerCount,
+		&i.FollowingCount,
+	)
+	return i, err
+}
+
+const updateWebhook = `-- name: UpdateWebhook :one
+UPDATE webhooks
+SET url = $1,
+    event_types = $2,
+    secret = COALESCE($3, secret),
+    active = $4,
+    updated_at = now()
+WHERE id = $5
+RETURNING id, url, event_types, secret, active, created_at, updated_at
+`
+
+type UpdateWebhookParams struct {
+	Url        string
+	EventTypes []string
+	Secret     sql.NullString
+	Active     bool
+	ID         uuid.UUID
+}
+
+func (q *Queries) UpdateWebhook(ctx context.Context, arg UpdateWebhookParams) (Webhook, error) {
+	row := q.db.QueryRowContext(ctx, updateWebhook,
+		arg.Url,
+		pq.Array(arg.EventTypes),
+		arg.Secret,
+		arg.Active,
+		arg.ID,
+	)
+	var i Webhook
+	err := row.Scan(
+		&i.ID,
+		&i.Url,
+		pq.Array(&i.EventTypes),
+		&i.Secret,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
@@ -1,10 +1,15 @@
 package postgres
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	model "github.com/demkowo/users/internal/models"
@@ -16,15 +21,41 @@ import (
 
 type Users interface {
 	Add(ctx context.Context, user model.User) (model.User, *resp.Err)
+	AddBatch(ctx context.Context, users []model.User) ([]model.User, []*resp.Err, *resp.Err)
+	Export(ctx context.Context, filter model.UserFilter, fn func(model.User) error) *resp.Err
 	Find(ctx context.Context) ([]model.User, *resp.Err)
 	List(ctx context.Context, limit, offset int32) ([]model.User, *resp.Err)
+	ListAfter(ctx context.Context, created time.Time, id uuid.UUID, limit int32) ([]model.User, *resp.Err)
+	Search(ctx context.Context, filter model.UserFilter, limit, offset int32) ([]model.User, int64, *resp.Err)
 	GetImgByNickname(ctx context.Context, nickname string) (string, *resp.Err)
 	GetByID(ctx context.Context, id uuid.UUID) (model.User, *resp.Err)
+	GetByNickname(ctx context.Context, nickname string) (model.User, *resp.Err)
+	GetByPreviousNickname(ctx context.Context, nickname string, since time.Time) (model.User, *resp.Err)
+	NicknameConflict(ctx context.Context, nickname string, userID uuid.UUID, since time.Time) (string, *resp.Err)
+	ChangeNickname(ctx context.Context, userID uuid.UUID, nickname string, since time.Time) (model.User, *resp.Err)
 	Update(ctx context.Context, user model.User) (model.User, *resp.Err)
+	Patch(ctx context.Context, user model.User, fields []string) (model.User, *resp.Err)
 	UpdateImg(ctx context.Context, userID uuid.UUID, img string) (model.User, *resp.Err)
-	Delete(ctx context.Context, userID uuid.UUID) (model.User, *resp.Err)
+	Delete(ctx context.Context, userID uuid.UUID) (*model.User, *resp.Err)
+	Restore(ctx context.Context, userID uuid.UUID, since time.Time) (model.User, *resp.Err)
+	Purge(ctx context.Context, userID uuid.UUID) (model.User, *resp.Err)
+	ListAuditEntries(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]model.AuditEntry, int64, *resp.Err)
+	Follow(ctx context.Context, followerID, followeeID uuid.UUID) (model.Follow, *resp.Err)
+	Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) *resp.Err
+	ListFollowers(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]model.FollowUser, int64, *resp.Err)
+	ListFollowing(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]model.FollowUser, int64, *resp.Err)
+	BackfillNicknameKeys(ctx context.Context) (int, []model.User, *resp.Err)
+	NormalizeCountries(ctx context.Context) (int64, []string, *resp.Err)
+	FindInCities(ctx context.Context, cityIDs []string, limit, offset int32) ([]model.User, int64, *resp.Err)
+	BackfillCityIDs(ctx context.Context) (int, []string, *resp.Err)
 }
 
+// exportChunkSize is the number of users read per query by Export.
+const exportChunkSize = 500
+
+// backfillBatchSize is the number of rows read per query by the backfills.
+const backfillBatchSize = 500
+
 type users struct {
 	db *sql.DB
 	q  *sqlc.Queries
@@ -51,57 +82,170 @@ func (r *users) Add(ctx context.Context, user model.User) (model.User, *resp.Err
 
 	qtx := r.q.WithTx(tx)
 
-	u, err := qtx.CreateUser(ctx, sqlc.CreateUserParams{
-		ID:       user.ID,
-		Nickname: user.Nickname,
-		Img:      nullString(user.Img),
-		Country:  nullString(user.Country),
-		City:     nullString(user.City),
-	})
+	u, clubs, err := insertUser(ctx, qtx, user)
+	if err != nil {
+		_ = tx.Rollback()
+		return model.User{}, userClubsError(err, "failed to add user")
+	}
+
+	added := toDomainUser(u, clubs)
+	err = addOutboxEvent(ctx, qtx, model.UserEventCreated, added)
+	if err == nil {
+		err = addAuditEntry(ctx, qtx, model.AuditOpAdd, nil, &added)
+	}
 	if err != nil {
 		_ = tx.Rollback()
 		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to add user", []interface{}{err.Error()})
 	}
 
-	for _, c := range user.Clubs {
-		cl, err := qtx.CreateClub(ctx, sqlc.CreateClubParams{ID: c.ID, Name: c.Name})
-		if err != nil {
+	if err := tx.Commit(); err != nil {
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to add user", []interface{}{err.Error()})
+	}
+
+	return added, nil
+}
+
+// AddBatch inserts users in one transaction with a savepoint per row, so failed rows are
+// reported at their index while the rest is committed.
+func (r *users) AddBatch(ctx context.Context, users []model.User) ([]model.User, []*resp.Err, *resp.Err) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, resp.Error(http.StatusInternalServerError, "failed to import users", []interface{}{err.Error()})
+	}
+	defer func() {
+		if p := recover(); p != nil {
 			_ = tx.Rollback()
-			return model.User{}, resp.Error(http.StatusInternalServerError, "failed to add user", []interface{}{err.Error()})
+			panic(p)
 		}
-		if err := qtx.AddUserClub(ctx, sqlc.AddUserClubParams{
-			UserID: u.ID,
-			ClubID: cl.ID,
-		}); err != nil {
+	}()
+
+	qtx := r.q.WithTx(tx)
+	saved := make([]model.User, len(users))
+	rowErrs := make([]*resp.Err, len(users))
+
+	for i, user := range users {
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT import_row"); err != nil {
+			_ = tx.Rollback()
+			return nil, nil, resp.Error(http.StatusInternalServerError, "failed to import users", []interface{}{err.Error()})
+		}
+
+		u, clubs, err := insertUser(ctx, qtx, user)
+		if err == nil {
+			saved[i] = toDomainUser(u, clubs)
+			err = addOutboxEvent(ctx, qtx, model.UserEventCreated, saved[i])
+		}
+		if err == nil {
+			err = addAuditEntry(ctx, qtx, model.AuditOpImport, nil, &saved[i])
+		}
+		if err != nil {
+			saved[i] = model.User{}
+			if _, e := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT import_row"); e != nil {
+				_ = tx.Rollback()
+				return nil, nil, resp.Error(http.StatusInternalServerError, "failed to import users", []interface{}{e.Error()})
+			}
+			if isUniqueViolation(err) {
+				rowErrs[i] = resp.Error(http.StatusConflict, "failed to add user", []interface{}{"nickname is already taken"})
+				continue
+			}
+			rowErrs[i] = userClubsError(err, "failed to add user")
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT import_row"); err != nil {
 			_ = tx.Rollback()
-			return model.User{}, resp.Error(http.StatusInternalServerError, "failed to add user", []interface{}{err.Error()})
+			return nil, nil, resp.Error(http.StatusInternalServerError, "failed to import users", []interface{}{err.Error()})
 		}
 	}
 
 	if err := tx.Commit(); err != nil {
-		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to add user", []interface{}{err.Error()})
+		return nil, nil, resp.Error(http.StatusInternalServerError, "failed to import users", []interface{}{err.Error()})
 	}
 
-	clubs, err := r.q.GetClubsByUserID(ctx, u.ID)
+	return saved, rowErrs, nil
+}
+
+func (r *users) Delete(ctx context.Context, userID uuid.UUID) (*model.User, *resp.Err) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to add user", []interface{}{err.Error()})
+		return nil, resp.Error(http.StatusInternalServerError, "failed to delete user", []interface{}{err.Error()})
 	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
 
-	return toDomainUser(u, clubs), nil
-}
+	qtx := r.q.WithTx(tx)
 
-func (r *users) Delete(ctx context.Context, userID uuid.UUID) (model.User, *resp.Err) {
-	u, err := r.q.SoftDeleteUser(ctx, userID)
+	before, err := lockedUser(ctx, qtx, userID)
 	if err != nil {
-		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to delete user", []interface{}{err.Error()})
+		_ = tx.Rollback()
+		return nil, resp.Error(http.StatusInternalServerError, "failed to delete user", []interface{}{err.Error()})
+	}
+	if before == nil {
+		_ = tx.Rollback()
+		return nil, resp.Error(http.StatusNotFound, "failed to delete user", []interface{}{"user not found"})
+	}
+	if before.Deleted {
+		_ = tx.Rollback()
+		return nil, nil
 	}
 
-	clubs, err := r.q.GetClubsByUserID(ctx, u.ID)
+	u, err := qtx.SoftDeleteUser(ctx, userID)
 	if err != nil {
-		return toDomainUser(u, []sqlc.Club{}), nil
+		_ = tx.Rollback()
+		return nil, resp.Error(http.StatusInternalServerError, "failed to delete user", []interface{}{err.Error()})
 	}
 
-	return toDomainUser(u, clubs), nil
+	if err := addNeighbourFollowCounts(ctx, qtx, userID, -1); err != nil {
+		_ = tx.Rollback()
+		return nil, resp.Error(http.StatusInternalServerError, "failed to delete user", []interface{}{err.Error()})
+	}
+
+	deleted, err := changedUser(ctx, qtx, u)
+	if err == nil {
+		err = addOutboxEvent(ctx, qtx, model.UserEventDeleted, deleted)
+	}
+	if err == nil {
+		err = addAuditEntry(ctx, qtx, model.AuditOpDelete, before, &deleted)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, resp.Error(http.StatusInternalServerError, "failed to delete user", []interface{}{err.Error()})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, resp.Error(http.StatusInternalServerError, "failed to delete user", []interface{}{err.Error()})
+	}
+
+	return &deleted, nil
+}
+
+// Export calls fn for every active user matching filter, newest first, from a consistent
+// snapshot read in chunks. An error returned by fn stops the export.
+func (r *users) Export(ctx context.Context, filter model.UserFilter, fn func(model.User) error) *resp.Err {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return resp.Error(http.StatusInternalServerError, "failed to export users", []interface{}{err.Error()})
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := exportUsers(ctx, r.q.WithTx(tx), filter, fn); err != nil {
+		_ = tx.Rollback()
+		return resp.Error(http.StatusInternalServerError, "failed to export users", []interface{}{err.Error()})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return resp.Error(http.StatusInternalServerError, "failed to export users", []interface{}{err.Error()})
+	}
+
+	return nil
 }
 
 func (r *users) Find(ctx context.Context) ([]model.User, *resp.Err) {
@@ -123,9 +267,63 @@ func (r *users) List(ctx context.Context, limit, offset int32) ([]model.User, *r
 	return r.attachClubs(ctx, us)
 }
 
+// ListAfter returns the page of active users that follows the user identified
+// by (created, id) in (created_at, id) descending order.
+func (r *users) ListAfter(ctx context.Context, created time.Time, id uuid.UUID, limit int32) ([]model.User, *resp.Err) {
+	us, err := r.q.ListUsersAfter(ctx, sqlc.ListUsersAfterParams{
+		CreatedAt: created,
+		ID:        id,
+		Limit:     limit,
+	})
+	if err != nil {
+		return nil, resp.Error(http.StatusInternalServerError, "failed to list users", []interface{}{err.Error()})
+	}
+	return r.attachClubs(ctx, us)
+}
+
+func (r *users) Search(ctx context.Context, filter model.UserFilter, limit, offset int32) ([]model.User, int64, *resp.Err) {
+	us, err := r.q.SearchUsers(ctx, sqlc.SearchUsersParams{
+		Country:        nullString(filter.Country),
+		City:           nullString(filter.City),
+		NicknamePrefix: nullString(escapeLike(model.NicknameKey(filter.NicknamePrefix))),
+		ClubID:         nullUUID(filter.ClubID),
+		ClubName:       nullString(filter.ClubName),
+		CreatedFrom:    nullTime(filter.CreatedFrom),
+		CreatedTo:      nullTime(filter.CreatedTo),
+		Limit:          limit,
+		Offset:         offset,
+	})
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to search users", []interface{}{err.Error()})
+	}
+
+	total, err := r.q.CountSearchUsers(ctx, sqlc.CountSearchUsersParams{
+		Country:        nullString(filter.Country),
+		City:           nullString(filter.City),
+		NicknamePrefix: nullString(escapeLike(model.NicknameKey(filter.NicknamePrefix))),
+		ClubID:         nullUUID(filter.ClubID),
+		ClubName:       nullString(filter.ClubName),
+		CreatedFrom:    nullTime(filter.CreatedFrom),
+		CreatedTo:      nullTime(filter.CreatedTo),
+	})
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to search users", []interface{}{err.Error()})
+	}
+
+	found, e := r.attachClubs(ctx, us)
+	if e != nil {
+		return nil, 0, e
+	}
+
+	return found, total, nil
+}
+
 func (r *users) GetImgByNickname(ctx context.Context, nickname string) (string, *resp.Err) {
-	img, err := r.q.GetUserImgByNickname(ctx, nickname)
+	img, err := r.q.GetUserImgByNickname(ctx, nullString(model.NicknameKey(nickname)))
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", resp.Error(http.StatusNotFound, "failed to get users image", []interface{}{"user not found"})
+		}
 		return "", resp.Error(http.StatusInternalServerError, "failed to get users image", []interface{}{err.Error()})
 	}
 	return nullStringToString(img), nil
@@ -134,10 +332,13 @@ func (r *users) GetImgByNickname(ctx context.Context, nickname string) (string,
 func (r *users) GetByID(ctx context.Context, id uuid.UUID) (model.User, *resp.Err) {
 	u, err := r.q.GetUserByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, resp.Error(http.StatusNotFound, "failed to get user", []interface{}{"user not found"})
+		}
 		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to get user", []interface{}{err.Error()})
 	}
 	if nullBoolToBool(u.Deleted) {
-		return model.User{}, resp.Error(http.StatusInternalServerError, "user not found", nil)
+		return model.User{}, resp.Error(http.StatusNotFound, "failed to get user", []interface{}{"user not found"})
 	}
 
 	clubs, err := r.q.GetClubsByUserID(ctx, u.ID)
@@ -148,140 +349,1469 @@ func (r *users) GetByID(ctx context.Context, id uuid.UUID) (model.User, *resp.Er
 	return toDomainUser(u, clubs), nil
 }
 
-func (r *users) Update(ctx context.Context, user model.User) (model.User, *resp.Err) {
-	tx, err := r.db.BeginTx(ctx, nil)
+func (r *users) GetByNickname(ctx context.Context, nickname string) (model.User, *resp.Err) {
+	u, err := r.q.GetUserByNickname(ctx, nullString(model.NicknameKey(nickname)))
 	if err != nil {
-		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
-	}
-	defer func() {
-		if p := recover(); p != nil {
-			_ = tx.Rollback()
-			panic(p)
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, resp.Error(http.StatusNotFound, "failed to get user", []interface{}{"user not found"})
 		}
-	}()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to get user", []interface{}{err.Error()})
+	}
 
-	qtx := r.q.WithTx(tx)
+	clubs, err := r.q.GetClubsByUserID(ctx, u.ID)
+	if err != nil {
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to get user", []interface{}{err.Error()})
+	}
 
-	u, err := qtx.UpdateUser(ctx, sqlc.UpdateUserParams{
-		ID:      user.ID,
-		Country: nullString(user.Country),
-		City:    nullString(user.City),
+	return toDomainUser(u, clubs), nil
+}
+
+func (r *users) GetByPreviousNickname(ctx context.Context, nickname string, since time.Time) (model.User, *resp.Err) {
+	u, err := r.q.GetUserByPreviousNickname(ctx, sqlc.GetUserByPreviousNicknameParams{
+		NicknameKey: nullString(model.NicknameKey(nickname)),
+		ChangedAt:   since,
 	})
 	if err != nil {
-		_ = tx.Rollback()
-		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, resp.Error(http.StatusNotFound, "failed to get user", []interface{}{"user not found"})
+		}
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to get user", []interface{}{err.Error()})
 	}
 
-	if err := qtx.DeleteUserClubsByUserID(ctx, u.ID); err != nil {
-		_ = tx.Rollback()
-		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+	clubs, err := r.q.GetClubsByUserID(ctx, u.ID)
+	if err != nil {
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to get user", []interface{}{err.Error()})
 	}
 
-	for _, c := range user.Clubs {
-		cl, err := qtx.CreateClub(ctx, sqlc.CreateClubParams{ID: c.ID, Name: c.Name})
+	return toDomainUser(u, clubs), nil
+}
+
+// NicknameConflict tells why the user can't take nickname, or returns "" when
+// they can.
+func (r *users) NicknameConflict(ctx context.Context, nickname string, userID uuid.UUID, since time.Time) (string, *resp.Err) {
+	conflict, err := nicknameConflict(ctx, r.q, nickname, userID, since)
+	if err != nil {
+		return "", resp.Error(http.StatusInternalServerError, "failed to check nickname", []interface{}{err.Error()})
+	}
+	return conflict, nil
+}
+
+// BackfillNicknameKeys fills in missing nickname keys and returns how many, and the users
+// whose key is taken.
+func (r *users) BackfillNicknameKeys(ctx context.Context) (int, []model.User, *resp.Err) {
+	filled := 0
+	var conflicts []model.User
+
+	for after := uuid.Nil; ; {
+		us, err := r.q.ListUsersWithoutNicknameKey(ctx, sqlc.ListUsersWithoutNicknameKeyParams{
+			ID:    after,
+			Limit: backfillBatchSize,
+		})
 		if err != nil {
-			_ = tx.Rollback()
-			return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+			return filled, conflicts, resp.Error(http.StatusInternalServerError, "failed to backfill nickname keys", []interface{}{err.Error()})
 		}
-		if err := qtx.AddUserClub(ctx, sqlc.AddUserClubParams{
-			UserID: u.ID,
-			ClubID: cl.ID,
-		}); err != nil {
-			_ = tx.Rollback()
-			return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+
+		for _, u := range us {
+			err := r.q.SetUserNicknameKey(ctx, sqlc.SetUserNicknameKeyParams{
+				ID:               u.ID,
+				NicknameKey:      nullString(model.NicknameKey(u.Nickname)),
+				NicknameSkeleton: nullString(model.NicknameSkeleton(u.Nickname)),
+			})
+			if err != nil {
+				if isUniqueViolation(err) {
+					conflicts = append(conflicts, model.User{ID: u.ID, Nickname: u.Nickname})
+					continue
+				}
+				return filled, conflicts, resp.Error(http.StatusInternalServerError, "failed to backfill nickname keys", []interface{}{err.Error()})
+			}
+			filled++
+		}
+
+		if len(us) < backfillBatchSize {
+			break
 		}
+		after = us[len(us)-1].ID
 	}
 
-	if err := tx.Commit(); err != nil {
-		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+	for after := uuid.Nil; ; {
+		hs, err := r.q.ListNicknameHistoryWithoutKey(ctx, sqlc.ListNicknameHistoryWithoutKeyParams{
+			ID:    after,
+			Limit: backfillBatchSize,
+		})
+		if err != nil {
+			return filled, conflicts, resp.Error(http.StatusInternalServerError, "failed to backfill nickname keys", []interface{}{err.Error()})
+		}
+
+		for _, h := range hs {
+			if err := r.q.SetNicknameHistoryKey(ctx, sqlc.SetNicknameHistoryKeyParams{
+				ID:               h.ID,
+				NicknameKey:      nullString(model.NicknameKey(h.Nickname)),
+				NicknameSkeleton: nullString(model.NicknameSkeleton(h.Nickname)),
+			}); err != nil {
+				return filled, conflicts, resp.Error(http.StatusInternalServerError, "failed to backfill nickname keys", []interface{}{err.Error()})
+			}
+			filled++
+		}
+
+		if len(hs) < backfillBatchSize {
+			break
+		}
+		after = hs[len(hs)-1].ID
 	}
 
-	clubs, err := r.q.GetClubsByUserID(ctx, u.ID)
+	return filled, conflicts, nil
+}
+
+// NormalizeCountries replaces free-text countries with alpha-2 codes and returns how many
+// users changed, and the values it couldn't resolve.
+func (r *users) NormalizeCountries(ctx context.Context) (int64, []string, *resp.Err) {
+	countries, err := r.q.ListDistinctCountries(ctx)
 	if err != nil {
-		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+		return 0, nil, resp.Error(http.StatusInternalServerError, "failed to normalize countries", []interface{}{err.Error()})
 	}
 
-	return toDomainUser(u, clubs), nil
+	var (
+		changed  int64
+		unmapped []string
+	)
+	for _, country := range countries {
+		code, ok := model.ParseCountry(country.String)
+		if !ok {
+			unmapped = append(unmapped, country.String)
+			continue
+		}
+		if code == country.String {
+			continue
+		}
+
+		n, err := r.q.ReplaceUserCountry(ctx, sqlc.ReplaceUserCountryParams{
+			Code:    nullString(code),
+			Country: country,
+		})
+		if err != nil {
+			return changed, unmapped, resp.Error(http.StatusInternalServerError, "failed to normalize countries", []interface{}{err.Error()})
+		}
+		changed += n
+	}
+
+	return changed, unmapped, nil
 }
 
-func (r *users) UpdateImg(ctx context.Context, userID uuid.UUID, img string) (model.User, *resp.Err) {
-	u, err := r.q.UpdateUserImg(ctx, sqlc.UpdateUserImgParams{
-		ID:  userID,
-		Img: nullString(img),
+// FindInCities returns a page of the active users living in the cities, in the
+// order of the cities, and how many there are in all.
+func (r *users) FindInCities(ctx context.Context, cityIDs []string, limit, offset int32) ([]model.User, int64, *resp.Err) {
+	us, err := r.q.ListUsersInCities(ctx, sqlc.ListUsersInCitiesParams{
+		CityIds: cityIDs,
+		Limit:   limit,
+		Offset:  offset,
 	})
 	if err != nil {
-		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update users image", []interface{}{err.Error()})
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to find nearby users", []interface{}{err.Error()})
 	}
 
-	clubs, err := r.q.GetClubsByUserID(ctx, u.ID)
+	total, err := r.q.CountUsersInCities(ctx, cityIDs)
 	if err != nil {
-		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update users image", []interface{}{err.Error()})
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to find nearby users", []interface{}{err.Error()})
 	}
 
-	return toDomainUser(u, clubs), nil
+	found, e := r.attachClubs(ctx, us)
+	if e != nil {
+		return nil, 0, e
+	}
+
+	return found, total, nil
 }
 
-func (r *users) attachClubs(ctx context.Context, us []sqlc.User) ([]model.User, *resp.Err) {
+// BackfillCityIDs resolves the cities of older users and returns how many, and the cities
+// it couldn't find.
+func (r *users) BackfillCityIDs(ctx context.Context) (int, []string, *resp.Err) {
+	resolved := 0
+	var unresolved []string
+	seen := make(map[string]bool)
 
-	fmt.Println(us)
-	domainUsers := toDomainUsers(us)
-	for i, u := range us {
-		clubs, err := r.q.GetClubsByUserID(ctx, u.ID)
+	for after := uuid.Nil; ; {
+		us, err := r.q.ListUsersWithoutCityID(ctx, sqlc.ListUsersWithoutCityIDParams{
+			ID:    after,
+			Limit: backfillBatchSize,
+		})
 		if err != nil {
-			return nil, resp.Error(http.StatusInternalServerError, "failed to attach clubs", []interface{}{err.Error()})
+			return resolved, unresolved, resp.Error(http.StatusInternalServerError, "failed to backfill city ids", []interface{}{err.Error()})
+		}
+
+		for _, u := range us {
+			id := cityID(u.Country.String, u.City.String)
+			if id == "" {
+				place := u.City.String + ", " + u.Country.String
+				if !seen[place] {
+					seen[place] = true
+					unresolved = append(unresolved, place)
+				}
+				continue
+			}
+
+			if err := r.q.SetUserCityID(ctx, sqlc.SetUserCityIDParams{
+				ID:     u.ID,
+				CityID: nullString(id),
+			}); err != nil {
+				return resolved, unresolved, resp.Error(http.StatusInternalServerError, "failed to backfill city ids", []interface{}{err.Error()})
+			}
+			resolved++
 		}
-		domainUsers[i].Clubs = clubsToDomain(clubs)
+
+		if len(us) < backfillBatchSize {
+			break
+		}
+		after = us[len(us)-1].ID
 	}
-	return domainUsers, nil
+
+	return resolved, unresolved, nil
 }
 
-func toDomainUser(u sqlc.User, clubs []sqlc.Club) model.User {
-	return model.User{
-		ID:       u.ID,
-		Nickname: u.Nickname,
-		Img:      nullStringToString(u.Img),
-		Country:  nullStringToString(u.Country),
-		City:     nullStringToString(u.City),
-		Created:  nullTimeToTime(u.CreatedAt),
-		Updated:  nullTimeToTime(u.UpdatedAt),
-		Deleted:  nullBoolToBool(u.Deleted),
-		Clubs:    clubsToDomain(clubs),
+func (r *users) ChangeNickname(ctx context.Context, userID uuid.UUID, nickname string, since time.Time) (model.User, *resp.Err) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to change nickname", []interface{}{err.Error()})
 	}
-}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
 
-func toDomainUsers(us []sqlc.User) []model.User {
-	users := make([]model.User, len(us))
-	for i, u := range us {
-		users[i] = model.User{
-			ID:       u.ID,
-			Nickname: u.Nickname,
-			Img:      nullStringToString(u.Img),
-			Country:  nullStringToString(u.Country),
-			City:     nullStringToString(u.City),
-			Created:  nullTimeToTime(u.CreatedAt),
-			Updated:  nullTimeToTime(u.UpdatedAt),
-			Deleted:  nullBoolToBool(u.Deleted),
+	qtx := r.q.WithTx(tx)
+
+	current, err := qtx.GetUserByIDForUpdate(ctx, userID)
+	if err != nil || nullBoolToBool(current.Deleted) {
+		_ = tx.Rollback()
+		if err == nil || errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, resp.Error(http.StatusNotFound, "failed to change nickname", []interface{}{"user not found"})
 		}
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to change nickname", []interface{}{err.Error()})
 	}
-	return users
-}
 
-func clubsToDomain(cs []sqlc.Club) []model.Club {
-	clubs := make([]model.Club, len(cs))
-	for i, c := range cs {
-		clubs[i] = model.Club{
-			ID:   c.ID,
-			Name: c.Name,
+	if current.Nickname == nickname {
+		_ = tx.Rollback()
+		clubs, err := r.q.GetClubsByUserID(ctx, current.ID)
+		if err != nil {
+			return model.User{}, resp.Error(http.StatusInternalServerError, "failed to change nickname", []interface{}{err.Error()})
 		}
+		return toDomainUser(current, clubs), nil
 	}
-	return clubs
-}
 
-func nullString(s string) sql.NullString {
-	if s == "" {
-		return sql.NullString{Valid: false}
+	before, err := changedUser(ctx, qtx, current)
+	if err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to change nickname", []interface{}{err.Error()})
 	}
-	return sql.NullString{String: s, Valid: true}
+
+	conflict, err := nicknameConflict(ctx, qtx, nickname, userID, since)
+	if err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to change nickname", []interface{}{err.Error()})
+	}
+	if conflict != "" {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusConflict, "failed to change nickname", []interface{}{model.FieldError{Field: "nickname", Message: conflict}})
+	}
+
+	if err := qtx.AddNicknameHistory(ctx, sqlc.AddNicknameHistoryParams{
+		ID:               uuid.New(),
+		UserID:           current.ID,
+		Nickname:         current.Nickname,
+		NicknameKey:      nullString(model.NicknameKey(current.Nickname)),
+		NicknameSkeleton: nullString(model.NicknameSkeleton(current.Nickname)),
+	}); err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to change nickname", []interface{}{err.Error()})
+	}
+
+	u, err := qtx.UpdateUserNickname(ctx, sqlc.UpdateUserNicknameParams{
+		ID:               current.ID,
+		Nickname:         nickname,
+		NicknameKey:      nullString(model.NicknameKey(nickname)),
+		NicknameSkeleton: nullString(model.NicknameSkeleton(nickname)),
+	})
+	if err != nil {
+		_ = tx.Rollback()
+		if isUniqueViolation(err) {
+			return model.User{}, resp.Error(http.StatusConflict, "failed to change nickname", []interface{}{"nickname is already taken"})
+		}
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to change nickname", []interface{}{err.Error()})
+	}
+
+	changed, err := changedUser(ctx, qtx, u)
+	if err == nil {
+		err = addOutboxEvent(ctx, qtx, model.UserEventUpdated, changed)
+	}
+	if err == nil {
+		err = addAuditEntry(ctx, qtx, model.AuditOpChangeNickname, &before, &changed)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to change nickname", []interface{}{err.Error()})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to change nickname", []interface{}{err.Error()})
+	}
+
+	return changed, nil
+}
+
+func (r *users) Update(ctx context.Context, user model.User) (model.User, *resp.Err) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	qtx := r.q.WithTx(tx)
+
+	before, err := lockedUser(ctx, qtx, user.ID)
+	if err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+	}
+
+	u, err := qtx.UpdateUser(ctx, sqlc.UpdateUserParams{
+		ID:              user.ID,
+		Country:         nullString(user.Country),
+		City:            nullString(user.City),
+		CityID:          nullString(cityID(user.Country, user.City)),
+		ExpectedVersion: nullInt32(user.Version),
+	})
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, r.updateMissError(ctx, user.ID)
+		}
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+	}
+
+	if err := replaceUserClubs(ctx, qtx, u.ID, user.Clubs); err != nil {
+		_ = tx.Rollback()
+		return model.User{}, userClubsError(err, "failed to update user")
+	}
+
+	changed, err := changedUser(ctx, qtx, u)
+	if err == nil {
+		err = addOutboxEvent(ctx, qtx, model.UserEventUpdated, changed)
+	}
+	if err == nil {
+		err = addAuditEntry(ctx, qtx, model.AuditOpUpdate, before, &changed)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+	}
+
+	return changed, nil
+}
+
+// Patch updates only the given fields (see model.UserField*) of the user,
+// leaving the rest untouched.
+func (r *users) Patch(ctx context.Context, user model.User, fields []string) (model.User, *resp.Err) {
+	set := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		set[f] = true
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	qtx := r.q.WithTx(tx)
+
+	before, err := lockedUser(ctx, qtx, user.ID)
+	if err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+	}
+
+	// The city is resolved again when either of the fields it depends on
+	// changes, taking the other one from the stored user.
+	setCityID := set[model.UserFieldCountry] || set[model.UserFieldCity]
+	country, city := user.Country, user.City
+	if before != nil {
+		if !set[model.UserFieldCountry] {
+			country = before.Country
+		}
+		if !set[model.UserFieldCity] {
+			city = before.City
+		}
+	}
+
+	u, err := qtx.PatchUser(ctx, sqlc.PatchUserParams{
+		SetCountry:      set[model.UserFieldCountry],
+		Country:         nullString(user.Country),
+		SetCity:         set[model.UserFieldCity],
+		City:            nullString(user.City),
+		SetCityID:       setCityID,
+		CityID:          nullString(cityID(country, city)),
+		ID:              user.ID,
+		ExpectedVersion: nullInt32(user.Version),
+	})
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, r.updateMissError(ctx, user.ID)
+		}
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+	}
+
+	if set[model.UserFieldClubs] {
+		if err := replaceUserClubs(ctx, qtx, u.ID, user.Clubs); err != nil {
+			_ = tx.Rollback()
+			return model.User{}, userClubsError(err, "failed to update user")
+		}
+	}
+
+	changed, err := changedUser(ctx, qtx, u)
+	if err == nil {
+		err = addOutboxEvent(ctx, qtx, model.UserEventUpdated, changed)
+	}
+	if err == nil {
+		err = addAuditEntry(ctx, qtx, model.AuditOpPatch, before, &changed)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+	}
+
+	return changed, nil
+}
+
+func (r *users) UpdateImg(ctx context.Context, userID uuid.UUID, img string) (model.User, *resp.Err) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update users image", []interface{}{err.Error()})
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	qtx := r.q.WithTx(tx)
+
+	before, err := lockedUser(ctx, qtx, userID)
+	if err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update users image", []interface{}{err.Error()})
+	}
+
+	u, err := qtx.UpdateUserImg(ctx, sqlc.UpdateUserImgParams{
+		ID:  userID,
+		Img: nullString(img),
+	})
+	if err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update users image", []interface{}{err.Error()})
+	}
+
+	updated, err := changedUser(ctx, qtx, u)
+	if err == nil {
+		err = addOutboxEvent(ctx, qtx, model.UserEventImageChanged, updated)
+	}
+	if err == nil {
+		err = addAuditEntry(ctx, qtx, model.AuditOpUpdateImg, before, &updated)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update users image", []interface{}{err.Error()})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to update users image", []interface{}{err.Error()})
+	}
+
+	return updated, nil
+}
+
+func (r *users) Restore(ctx context.Context, userID uuid.UUID, since time.Time) (model.User, *resp.Err) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to restore user", []interface{}{err.Error()})
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	qtx := r.q.WithTx(tx)
+
+	current, err := qtx.GetUserByIDForUpdate(ctx, userID)
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, resp.Error(http.StatusNotFound, "failed to restore user", []interface{}{"user not found"})
+		}
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to restore user", []interface{}{err.Error()})
+	}
+	if !nullBoolToBool(current.Deleted) {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusConflict, "failed to restore user", []interface{}{"user is not deleted"})
+	}
+
+	before, err := changedUser(ctx, qtx, current)
+	if err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to restore user", []interface{}{err.Error()})
+	}
+
+	conflict, err := nicknameConflict(ctx, qtx, current.Nickname, current.ID, since)
+	if err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to restore user", []interface{}{err.Error()})
+	}
+	if conflict != "" {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusConflict, "failed to restore user", []interface{}{conflict})
+	}
+
+	u, err := qtx.RestoreUser(ctx, userID)
+	if err != nil {
+		_ = tx.Rollback()
+		if isUniqueViolation(err) {
+			return model.User{}, resp.Error(http.StatusConflict, "failed to restore user", []interface{}{"nickname is already taken"})
+		}
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to restore user", []interface{}{err.Error()})
+	}
+
+	if err := addNeighbourFollowCounts(ctx, qtx, userID, 1); err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to restore user", []interface{}{err.Error()})
+	}
+
+	changed, err := changedUser(ctx, qtx, u)
+	if err == nil {
+		err = addOutboxEvent(ctx, qtx, model.UserEventUpdated, changed)
+	}
+	if err == nil {
+		err = addAuditEntry(ctx, qtx, model.AuditOpRestore, &before, &changed)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to restore user", []interface{}{err.Error()})
+	}
+
+	if err := tx.Commit(); err != nil {
+		if isUniqueViolation(err) {
+			return model.User{}, resp.Error(http.StatusConflict, "failed to restore user", []interface{}{"nickname is already taken"})
+		}
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to restore user", []interface{}{err.Error()})
+	}
+
+	return changed, nil
+}
+
+func (r *users) Purge(ctx context.Context, userID uuid.UUID) (model.User, *resp.Err) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to purge user", []interface{}{err.Error()})
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	qtx := r.q.WithTx(tx)
+
+	current, err := qtx.GetUserByIDForUpdate(ctx, userID)
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, resp.Error(http.StatusNotFound, "failed to purge user", []interface{}{"user not found"})
+		}
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to purge user", []interface{}{err.Error()})
+	}
+
+	// the follows of a soft-deleted user have already been uncounted
+	if !nullBoolToBool(current.Deleted) {
+		if err := addNeighbourFollowCounts(ctx, qtx, userID, -1); err != nil {
+			_ = tx.Rollback()
+			return model.User{}, resp.Error(http.StatusInternalServerError, "failed to purge user", []interface{}{err.Error()})
+		}
+	}
+
+	clubs, err := qtx.GetClubsByUserID(ctx, userID)
+	if err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to purge user", []interface{}{err.Error()})
+	}
+
+	clubIDs := make([]uuid.UUID, len(clubs))
+	for i, c := range clubs {
+		clubIDs[i] = c.ID
+	}
+	if err := leaveClubs(ctx, qtx, userID, clubIDs); err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to purge user", []interface{}{err.Error()})
+	}
+
+	u, err := qtx.PurgeUser(ctx, userID)
+	if err != nil {
+		_ = tx.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, resp.Error(http.StatusNotFound, "failed to purge user", []interface{}{"user not found"})
+		}
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to purge user", []interface{}{err.Error()})
+	}
+
+	purged := toDomainUser(u, clubs)
+	// a soft-deleted user has already been announced as deleted
+	if !purged.Deleted {
+		if err := addOutboxEvent(ctx, qtx, model.UserEventDeleted, purged); err != nil {
+			_ = tx.Rollback()
+			return model.User{}, resp.Error(http.StatusInternalServerError, "failed to purge user", []interface{}{err.Error()})
+		}
+	}
+
+	if err := addAuditEntry(ctx, qtx, model.AuditOpPurge, &purged, nil); err != nil {
+		_ = tx.Rollback()
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to purge user", []interface{}{err.Error()})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.User{}, resp.Error(http.StatusInternalServerError, "failed to purge user", []interface{}{err.Error()})
+	}
+
+	return purged, nil
+}
+
+// ListAuditEntries returns the audit trail of the user, newest first. It is
+// kept after the user has been purged.
+func (r *users) ListAuditEntries(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]model.AuditEntry, int64, *resp.Err) {
+	as, err := r.q.ListAuditEntries(ctx, sqlc.ListAuditEntriesParams{
+		UserID: userID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list audit entries", []interface{}{err.Error()})
+	}
+
+	total, err := r.q.CountAuditEntries(ctx, userID)
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list audit entries", []interface{}{err.Error()})
+	}
+
+	entries := make([]model.AuditEntry, 0, len(as))
+	for _, a := range as {
+		entry, err := auditEntryToDomain(a)
+		if err != nil {
+			return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list audit entries", []interface{}{err.Error()})
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, total, nil
+}
+
+// Follow makes the follower follow the followee; both must be active users.
+// Following a user again changes nothing and returns the existing follow.
+func (r *users) Follow(ctx context.Context, followerID, followeeID uuid.UUID) (model.Follow, *resp.Err) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return model.Follow{}, resp.Error(http.StatusInternalServerError, "failed to follow user", []interface{}{err.Error()})
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	qtx := r.q.WithTx(tx)
+
+	deleted, err := lockFollowUsers(ctx, qtx, followerID, followeeID)
+	if err != nil {
+		_ = tx.Rollback()
+		return model.Follow{}, resp.Error(http.StatusInternalServerError, "failed to follow user", []interface{}{err.Error()})
+	}
+	if d, ok := deleted[followerID]; !ok || d {
+		_ = tx.Rollback()
+		return model.Follow{}, resp.Error(http.StatusNotFound, "failed to follow user", []interface{}{"user not found"})
+	}
+	if d, ok := deleted[followeeID]; !ok || d {
+		_ = tx.Rollback()
+		return model.Follow{}, resp.Error(http.StatusNotFound, "failed to follow user", []interface{}{"followee not found"})
+	}
+
+	n, err := qtx.InsertFollow(ctx, sqlc.InsertFollowParams{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	})
+	if err == nil && n > 0 {
+		err = addFollowCounts(ctx, qtx, followerID, followeeID, 1, 1)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return model.Follow{}, resp.Error(http.StatusInternalServerError, "failed to follow user", []interface{}{err.Error()})
+	}
+
+	f, err := qtx.GetFollow(ctx, sqlc.GetFollowParams{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	})
+	if err != nil {
+		_ = tx.Rollback()
+		return model.Follow{}, resp.Error(http.StatusInternalServerError, "failed to follow user", []interface{}{err.Error()})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return model.Follow{}, resp.Error(http.StatusInternalServerError, "failed to follow user", []interface{}{err.Error()})
+	}
+
+	return model.Follow{
+		FollowerID: f.FollowerID,
+		FolloweeID: f.FolloweeID,
+		Since:      f.CreatedAt,
+	}, nil
+}
+
+// Unfollow makes the follower stop following the followee, if they do.
+func (r *users) Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) *resp.Err {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return resp.Error(http.StatusInternalServerError, "failed to unfollow user", []interface{}{err.Error()})
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	qtx := r.q.WithTx(tx)
+
+	deleted, err := lockFollowUsers(ctx, qtx, followerID, followeeID)
+	if err != nil {
+		_ = tx.Rollback()
+		return resp.Error(http.StatusInternalServerError, "failed to unfollow user", []interface{}{err.Error()})
+	}
+	if d, ok := deleted[followerID]; !ok || d {
+		_ = tx.Rollback()
+		return resp.Error(http.StatusNotFound, "failed to unfollow user", []interface{}{"user not found"})
+	}
+	followeeDeleted, ok := deleted[followeeID]
+	if !ok {
+		_ = tx.Rollback()
+		return resp.Error(http.StatusNotFound, "failed to unfollow user", []interface{}{"followee not found"})
+	}
+
+	n, err := qtx.DeleteFollow(ctx, sqlc.DeleteFollowParams{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+	})
+	if err == nil && n > 0 {
+		// a soft-deleted followee isn't counted among the users followed
+		following := int32(-1)
+		if followeeDeleted {
+			following = 0
+		}
+		err = addFollowCounts(ctx, qtx, followerID, followeeID, following, -1)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return resp.Error(http.StatusInternalServerError, "failed to unfollow user", []interface{}{err.Error()})
+	}
+
+	if err := tx.Commit(); err != nil {
+		return resp.Error(http.StatusInternalServerError, "failed to unfollow user", []interface{}{err.Error()})
+	}
+
+	return nil
+}
+
+// ListFollowers returns the active users following the user, most recent
+// follow first.
+func (r *users) ListFollowers(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]model.FollowUser, int64, *resp.Err) {
+	if err := r.activeUserError(ctx, userID, "failed to list followers"); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.q.ListFollowers(ctx, sqlc.ListFollowersParams{
+		FolloweeID: userID,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list followers", []interface{}{err.Error()})
+	}
+
+	total, err := r.q.CountFollowers(ctx, userID)
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list followers", []interface{}{err.Error()})
+	}
+
+	users, e := followUsers(ctx, r.q, rows)
+	if e != nil {
+		return nil, 0, e
+	}
+
+	return users, total, nil
+}
+
+// ListFollowing returns the active users the user follows, most recent follow
+// first.
+func (r *users) ListFollowing(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]model.FollowUser, int64, *resp.Err) {
+	if err := r.activeUserError(ctx, userID, "failed to list followed users"); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.q.ListFollowing(ctx, sqlc.ListFollowingParams{
+		FollowerID: userID,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list followed users", []interface{}{err.Error()})
+	}
+
+	total, err := r.q.CountFollowing(ctx, userID)
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list followed users", []interface{}{err.Error()})
+	}
+
+	followed := make([]sqlc.ListFollowersRow, len(rows))
+	for i, row := range rows {
+		followed[i] = sqlc.ListFollowersRow(row)
+	}
+
+	users, e := followUsers(ctx, r.q, followed)
+	if e != nil {
+		return nil, 0, e
+	}
+
+	return users, total, nil
+}
+
+// updateMissError tells a missing user from a stale version when an update missed.
+func (r *users) updateMissError(ctx context.Context, userID uuid.UUID) *resp.Err {
+	u, err := r.q.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return resp.Error(http.StatusNotFound, "failed to update user", []interface{}{"user not found"})
+		}
+		return resp.Error(http.StatusInternalServerError, "failed to update user", []interface{}{err.Error()})
+	}
+	if nullBoolToBool(u.Deleted) {
+		return resp.Error(http.StatusNotFound, "failed to update user", []interface{}{"user not found"})
+	}
+
+	return resp.Error(http.StatusConflict, "failed to update user", []interface{}{
+		fmt.Sprintf("user has been modified concurrently, current version is %d", u.Version),
+	})
+}
+
+// activeUserError returns 404 with message when there is no active user with the ID.
+func (r *users) activeUserError(ctx context.Context, userID uuid.UUID, message string) *resp.Err {
+	u, err := r.q.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return resp.Error(http.StatusNotFound, message, []interface{}{"user not found"})
+		}
+		return resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+	}
+	if nullBoolToBool(u.Deleted) {
+		return resp.Error(http.StatusNotFound, message, []interface{}{"user not found"})
+	}
+
+	return nil
+}
+
+func (r *users) attachClubs(ctx context.Context, us []sqlc.User) ([]model.User, *resp.Err) {
+	return attachClubs(ctx, r.q, us)
+}
+
+func attachClubs(ctx context.Context, q *sqlc.Queries, us []sqlc.User) ([]model.User, *resp.Err) {
+	domainUsers := toDomainUsers(us)
+	for i, u := range us {
+		clubs, err := q.GetClubsByUserID(ctx, u.ID)
+		if err != nil {
+			return nil, resp.Error(http.StatusInternalServerError, "failed to attach clubs", []interface{}{err.Error()})
+		}
+		domainUsers[i].Clubs = userClubsToDomain(clubs)
+	}
+	return domainUsers, nil
+}
+
+// exportUsers streams the matching active users to fn, newest first, a chunk at a time.
+func exportUsers(ctx context.Context, q *sqlc.Queries, filter model.UserFilter, fn func(model.User) error) error {
+	params := sqlc.ExportUsersParams{
+		Country:        nullString(filter.Country),
+		City:           nullString(filter.City),
+		NicknamePrefix: nullString(escapeLike(model.NicknameKey(filter.NicknamePrefix))),
+		ClubID:         nullUUID(filter.ClubID),
+		ClubName:       nullString(filter.ClubName),
+		CreatedFrom:    nullTime(filter.CreatedFrom),
+		CreatedTo:      nullTime(filter.CreatedTo),
+		Limit:          exportChunkSize,
+	}
+
+	for {
+		us, err := q.ExportUsers(ctx, params)
+		if err != nil {
+			return err
+		}
+		if len(us) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, len(us))
+		for i, u := range us {
+			ids[i] = u.ID
+		}
+		rows, err := q.GetClubsByUserIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		clubs := make(map[uuid.UUID][]sqlc.GetClubsByUserIDRow, len(us))
+		for _, row := range rows {
+			clubs[row.UserID] = append(clubs[row.UserID], sqlc.GetClubsByUserIDRow{
+				ID:         row.ID,
+				Name:       row.Name,
+				JoinPolicy: row.JoinPolicy,
+				Role:       row.Role,
+				JoinedAt:   row.JoinedAt,
+			})
+		}
+
+		for _, u := range us {
+			if err := fn(toDomainUser(u, clubs[u.ID])); err != nil {
+				return err
+			}
+		}
+
+		if len(us) < exportChunkSize {
+			return nil
+		}
+		last := us[len(us)-1]
+		params.AfterCreatedAt = last.CreatedAt
+		params.AfterID = nullUUID(last.ID)
+	}
+}
+
+// changedUser loads the clubs of u within the transaction of a change.
+func changedUser(ctx context.Context, q *sqlc.Queries, u sqlc.User) (model.User, error) {
+	clubs, err := q.GetClubsByUserID(ctx, u.ID)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return toDomainUser(u, clubs), nil
+}
+
+// addOutboxEvent records the change of user in the outbox, in its transaction.
+func addOutboxEvent(ctx context.Context, q *sqlc.Queries, eventType string, user model.User) error {
+	payload, err := json.Marshal(user)
+	if err != nil {
+		return err
+	}
+
+	return q.InsertOutboxEvent(ctx, sqlc.InsertOutboxEventParams{
+		UserID:    user.ID,
+		EventType: eventType,
+		Payload:   payload,
+	})
+}
+
+// addAuditEntry records the change from before to after, made by the actor of ctx.
+func addAuditEntry(ctx context.Context, q *sqlc.Queries, operation string, before, after *model.User) error {
+	actor := model.AuditActorFrom(ctx)
+
+	userID := uuid.Nil
+	if after != nil {
+		userID = after.ID
+	} else if before != nil {
+		userID = before.ID
+	}
+
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+	changes, err := json.Marshal(userChanges(before, after))
+	if err != nil {
+		return err
+	}
+
+	return q.InsertAuditEntry(ctx, sqlc.InsertAuditEntryParams{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Actor:     actor.ID,
+		ActorAddr: actor.Addr,
+		Transport: actor.Transport,
+		Operation: operation,
+		Before:    beforeJSON,
+		After:     afterJSON,
+		Changes:   changes,
+	})
+}
+
+// userChanges returns the fields, by JSON name, that differ between before and after.
+func userChanges(before, after *model.User) map[string]model.AuditChange {
+	var b, a model.User
+	if before != nil {
+		b = *before
+	}
+	if after != nil {
+		a = *after
+	}
+
+	changes := make(map[string]model.AuditChange)
+	for _, f := range []struct {
+		name     string
+		from, to string
+	}{
+		{"nickname", b.Nickname, a.Nickname},
+		{"img", b.Img, a.Img},
+		{"country", b.Country, a.Country},
+		{"city", b.City, a.City},
+	} {
+		if f.from != f.to {
+			changes[f.name] = model.AuditChange{From: f.from, To: f.to}
+		}
+	}
+
+	if from, to := clubNames(b.Clubs), clubNames(a.Clubs); !slices.Equal(from, to) {
+		changes["clubs"] = model.AuditChange{From: from, To: to}
+	}
+	if b.Deleted != a.Deleted {
+		changes["deleted"] = model.AuditChange{From: b.Deleted, To: a.Deleted}
+	}
+
+	return changes
+}
+
+func clubNames(clubs []model.Club) []string {
+	names := make([]string, 0, len(clubs))
+	for _, c := range clubs {
+		names = append(names, c.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// lockedUser locks the user's row and returns the user, or nil when there is none.
+func lockedUser(ctx context.Context, q *sqlc.Queries, userID uuid.UUID) (*model.User, error) {
+	u, err := q.GetUserByIDForUpdate(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	user, err := changedUser(ctx, q, u)
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// lockFollowUsers locks both users in id order and reports whether each is deleted.
+func lockFollowUsers(ctx context.Context, q *sqlc.Queries, followerID, followeeID uuid.UUID) (map[uuid.UUID]bool, error) {
+	rows, err := q.LockUsers(ctx, []uuid.UUID{followerID, followeeID})
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make(map[uuid.UUID]bool, len(rows))
+	for _, row := range rows {
+		deleted[row.ID] = nullBoolToBool(row.Deleted)
+	}
+	return deleted, nil
+}
+
+// addFollowCounts adds to the following count of the follower and the followers of the followee.
+func addFollowCounts(ctx context.Context, q *sqlc.Queries, followerID, followeeID uuid.UUID, following, followers int32) error {
+	if following != 0 {
+		if err := q.AddFollowingCount(ctx, sqlc.AddFollowingCountParams{Delta: following, ID: followerID}); err != nil {
+			return err
+		}
+	}
+	if followers != 0 {
+		if err := q.AddFollowerCount(ctx, sqlc.AddFollowerCountParams{Delta: followers, ID: followeeID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addNeighbourFollowCounts adds delta to the follow counts of the users linked to the user.
+func addNeighbourFollowCounts(ctx context.Context, q *sqlc.Queries, userID uuid.UUID, delta int32) error {
+	if err := q.AddFollowerCountOfFollowees(ctx, sqlc.AddFollowerCountOfFolloweesParams{Delta: delta, UserID: userID}); err != nil {
+		return err
+	}
+	return q.AddFollowingCountOfFollowers(ctx, sqlc.AddFollowingCountOfFollowersParams{Delta: delta, UserID: userID})
+}
+
+// followUsers converts the rows of a follow list, with the clubs of every user.
+func followUsers(ctx context.Context, q *sqlc.Queries, rows []sqlc.ListFollowersRow) ([]model.FollowUser, *resp.Err) {
+	us := make([]sqlc.User, len(rows))
+	for i, f := range rows {
+		us[i] = sqlc.User{
+			ID:               f.ID,
+			Nickname:         f.Nickname,
+			Img:              f.Img,
+			Country:          f.Country,
+			City:             f.City,
+			CreatedAt:        f.CreatedAt,
+			UpdatedAt:        f.UpdatedAt,
+			Deleted:          f.Deleted,
+			Version:          f.Version,
+			NicknameKey:      f.NicknameKey,
+			NicknameSkeleton: f.NicknameSkeleton,
+			CityID:           f.CityID,
+			FollowerCount:    f.FollowerCount,
+			FollowingCount:   f.FollowingCount,
+		}
+	}
+
+	users, err := attachClubs(ctx, q, us)
+	if err != nil {
+		return nil, err
+	}
+
+	followUsers := make([]model.FollowUser, len(rows))
+	for i, f := range rows {
+		followUsers[i] = model.FollowUser{
+			User:  users[i],
+			Since: f.FollowedAt,
+		}
+	}
+	return followUsers, nil
+}
+
+// cityID returns the ID of the gazetteer city the country and city resolve to, or "".
+func cityID(country, city string) string {
+	if c, ok := model.ParseCity(city, country); ok {
+		return c.ID
+	}
+	return ""
+}
+
+// insertUser creates the user and links it to its clubs, creating the missing ones.
+func insertUser(ctx context.Context, q *sqlc.Queries, user model.User) (sqlc.User, []sqlc.GetClubsByUserIDRow, error) {
+	u, err := q.CreateUser(ctx, sqlc.CreateUserParams{
+		ID:               user.ID,
+		Nickname:         user.Nickname,
+		Img:              nullString(user.Img),
+		Country:          nullString(user.Country),
+		City:             nullString(user.City),
+		NicknameKey:      nullString(model.NicknameKey(user.Nickname)),
+		NicknameSkeleton: nullString(model.NicknameSkeleton(user.Nickname)),
+		CityID:           nullString(cityID(user.Country, user.City)),
+	})
+	if err != nil {
+		return sqlc.User{}, nil, err
+	}
+
+	for _, c := range user.Clubs {
+		cl, err := q.CreateClub(ctx, sqlc.CreateClubParams{ID: c.ID, Name: c.Name})
+		if err != nil {
+			return sqlc.User{}, nil, err
+		}
+		if cl.JoinPolicy == model.ClubJoinInviteOnly {
+			return sqlc.User{}, nil, &inviteOnlyClubError{club: cl.Name}
+		}
+		if err := q.AddUserClub(ctx, sqlc.AddUserClubParams{
+			UserID: u.ID,
+			ClubID: cl.ID,
+		}); err != nil {
+			return sqlc.User{}, nil, err
+		}
+	}
+
+	clubs, err := q.GetClubsByUserID(ctx, u.ID)
+	if err != nil {
+		return sqlc.User{}, nil, err
+	}
+
+	return u, clubs, nil
+}
+
+// replaceUserClubs makes clubs the user's memberships; kept ones keep their role.
+func replaceUserClubs(ctx context.Context, q *sqlc.Queries, userID uuid.UUID, clubs []model.Club) error {
+	current, err := q.GetClubsByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	member := make(map[uuid.UUID]bool, len(current))
+	for _, c := range current {
+		member[c.ID] = true
+	}
+
+	kept := make(map[uuid.UUID]bool, len(clubs))
+	for _, c := range clubs {
+		cl, err := q.CreateClub(ctx, sqlc.CreateClubParams{ID: c.ID, Name: c.Name})
+		if err != nil {
+			return err
+		}
+		if cl.JoinPolicy == model.ClubJoinInviteOnly && !member[cl.ID] {
+			return &inviteOnlyClubError{club: cl.Name}
+		}
+		if err := q.AddUserClub(ctx, sqlc.AddUserClubParams{
+			UserID: userID,
+			ClubID: cl.ID,
+		}); err != nil {
+			return err
+		}
+		kept[cl.ID] = true
+	}
+
+	var left []uuid.UUID
+	for _, c := range current {
+		if !kept[c.ID] {
+			left = append(left, c.ID)
+		}
+	}
+	if len(left) == 0 {
+		return nil
+	}
+
+	return leaveClubs(ctx, q, userID, left)
+}
+
+// inviteOnlyClubError is returned when saving clubs would join an invite-only one.
+type inviteOnlyClubError struct {
+	club string
+}
+
+func (e *inviteOnlyClubError) Error() string {
+	return fmt.Sprintf("club %s is invite-only", e.club)
+}
+
+// userClubsError is 403 for an invite-only club and 500 otherwise.
+func userClubsError(err error, message string) *resp.Err {
+	var inviteOnly *inviteOnlyClubError
+	if errors.As(err, &inviteOnly) {
+		return resp.Error(http.StatusForbidden, message, []interface{}{model.FieldError{
+			Field:   "clubs",
+			Message: inviteOnly.Error() + ", join it through an invitation or a join request",
+		}})
+	}
+	return resp.Error(http.StatusInternalServerError, message, []interface{}{err.Error()})
+}
+
+// leaveClubs ends the user's memberships, handing over clubs they last owned.
+func leaveClubs(ctx context.Context, q *sqlc.Queries, userID uuid.UUID, clubIDs []uuid.UUID) error {
+	slices.SortFunc(clubIDs, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+	for _, clubID := range clubIDs {
+		if _, err := q.LockClubOwners(ctx, clubID); err != nil {
+			return err
+		}
+	}
+
+	if err := q.PromoteClubSuccessors(ctx, sqlc.PromoteClubSuccessorsParams{
+		ClubIds: clubIDs,
+		UserID:  userID,
+	}); err != nil {
+		return err
+	}
+
+	return q.DeleteUserClubs(ctx, sqlc.DeleteUserClubsParams{
+		UserID:  userID,
+		ClubIds: clubIDs,
+	})
+}
+
+// nicknameConflict returns why nickname clashes with a current or recent one, or "".
+func nicknameConflict(ctx context.Context, q *sqlc.Queries, nickname string, userID uuid.UUID, since time.Time) (string, error) {
+	key := nullString(model.NicknameKey(nickname))
+
+	taken, err := q.IsNicknameTaken(ctx, sqlc.IsNicknameTakenParams{
+		NicknameKey: key,
+		ID:          userID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if !taken {
+		taken, err = q.IsNicknameReserved(ctx, sqlc.IsNicknameReservedParams{
+			NicknameKey: key,
+			UserID:      userID,
+			ChangedAt:   since,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	if taken {
+		return "nickname is already taken", nil
+	}
+
+	confusable, err := q.IsNicknameConfusable(ctx, sqlc.IsNicknameConfusableParams{
+		NicknameSkeleton: nullString(model.NicknameSkeleton(nickname)),
+		UserID:           userID,
+		ChangedAt:        since,
+	})
+	if err != nil {
+		return "", err
+	}
+	if confusable {
+		return "nickname is too similar to an existing one", nil
+	}
+
+	return "", nil
+}
+
+func toDomainUser(u sqlc.User, clubs []sqlc.GetClubsByUserIDRow) model.User {
+	return model.User{
+		ID:        u.ID,
+		Nickname:  u.Nickname,
+		Img:       nullStringToString(u.Img),
+		Country:   nullStringToString(u.Country),
+		City:      nullStringToString(u.City),
+		CityID:    nullStringToString(u.CityID),
+		Created:   nullTimeToTime(u.CreatedAt),
+		Updated:   nullTimeToTime(u.UpdatedAt),
+		Deleted:   nullBoolToBool(u.Deleted),
+		Version:   u.Version,
+		Followers: u.FollowerCount,
+		Following: u.FollowingCount,
+		Clubs:     userClubsToDomain(clubs),
+	}
+}
+
+func toDomainUsers(us []sqlc.User) []model.User {
+	users := make([]model.User, len(us))
+	for i, u := range us {
+		users[i] = model.User{
+			ID:        u.ID,
+			Nickname:  u.Nickname,
+			Img:       nullStringToString(u.Img),
+			Country:   nullStringToString(u.Country),
+			City:      nullStringToString(u.City),
+			CityID:    nullStringToString(u.CityID),
+			Created:   nullTimeToTime(u.CreatedAt),
+			Updated:   nullTimeToTime(u.UpdatedAt),
+			Deleted:   nullBoolToBool(u.Deleted),
+			Version:   u.Version,
+			Followers: u.FollowerCount,
+			Following: u.FollowingCount,
+		}
+	}
+	return users
+}
+
+func auditEntryToDomain(a sqlc.UserAudit) (model.AuditEntry, error) {
+	entry := model.AuditEntry{
+		ID:        a.ID,
+		UserID:    a.UserID,
+		Actor:     a.Actor,
+		ActorAddr: a.ActorAddr,
+		Transport: a.Transport,
+		Operation: a.Operation,
+		Created:   a.CreatedAt,
+	}
+
+	if err := json.Unmarshal(a.Before, &entry.Before); err != nil {
+		return model.AuditEntry{}, err
+	}
+	if err := json.Unmarshal(a.After, &entry.After); err != nil {
+		return model.AuditEntry{}, err
+	}
+	if err := json.Unmarshal(a.Changes, &entry.Changes); err != nil {
+		return model.AuditEntry{}, err
+	}
+
+	return entry, nil
+}
+
+func clubsToDomain(cs []sqlc.Club) []model.Club {
+	clubs := make([]model.Club, len(cs))
+	for i, c := range cs {
+		clubs[i] = model.Club{
+			ID:         c.ID,
+			Name:       c.Name,
+			JoinPolicy: c.JoinPolicy,
+		}
+	}
+	return clubs
+}
+
+func userClubsToDomain(cs []sqlc.GetClubsByUserIDRow) []model.Club {
+	clubs := make([]model.Club, len(cs))
+	for i, c := range cs {
+		joined := c.JoinedAt
+		clubs[i] = model.Club{
+			ID:         c.ID,
+			Name:       c.Name,
+			JoinPolicy: c.JoinPolicy,
+			Role:       c.Role,
+			Joined:     &joined,
+		}
+	}
+	return clubs
+}
+
+func nullString(s string) sql.NullString {
+	if s == "" {
+		return sql.NullString{Valid: false}
+	}
+	return sql.NullString{String: s, Valid: true}
+}
+
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	if id == uuid.Nil {
+		return uuid.NullUUID{Valid: false}
+	}
+	return uuid.NullUUID{UUID: id, Valid: true}
+}
+
+func nullInt32(i int32) sql.NullInt32 {
+	if i == 0 {
+		return sql.NullInt32{Valid: false}
+	}
+	return sql.NullInt32{Int32: i, Valid: true}
+}
+
+func nullTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{Valid: false}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+// escapeLike escapes the LIKE wildcards so s is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
 }
 
 func nullStringToString(ns sql.NullString) string {
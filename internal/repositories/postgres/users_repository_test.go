@@ -0,0 +1,445 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	model "github.com/demkowo/users/internal/models"
+	"github.com/demkowo/utils/resp"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// fakeDB is a database/sql connector that answers the statements the tests
+// run and logs the ones that matter to them: BEGIN, COMMIT, ROLLBACK, the
+// savepoints, CreateUser with the nickname it inserts, the club lookups and
+// the outbox claims and marks.
+type fakeDB struct {
+	log []string
+
+	// taken nicknames fail CreateUser with a unique violation.
+	taken map[string]bool
+	// inviteOnly club names are returned by CreateClub as invite-only.
+	inviteOnly map[string]bool
+	// fail makes the statements it holds fail.
+	fail map[string]bool
+	// outbox holds the events ClaimOutboxEvents claims.
+	outbox []model.OutboxEvent
+	// managers are the users IsClubManager finds owning or moderating any club.
+	managers map[string]bool
+	// stored is the user GetUserByID and GetUserByIDForUpdate find, if any.
+	// Conditional updates never match it, as if its version had moved on.
+	stored *model.User
+}
+
+func (db *fakeDB) Connect(ctx context.Context) (driver.Conn, error) { return &fakeConn{db: db}, nil }
+func (db *fakeDB) Driver() driver.Driver                            { return fakeDriver{} }
+
+func (db *fakeDB) record(stmt string) error {
+	db.log = append(db.log, stmt)
+	if db.fail[stmt] {
+		return errors.New(stmt + " failed")
+	}
+	return nil
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	return nil, errors.New("open the fake database with sql.OpenDB")
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := c.db.record("BEGIN"); err != nil {
+		return nil, err
+	}
+	return &fakeTx{db: c.db}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	var stmt string
+	switch name := queryName(query); {
+	case strings.Contains(query, "SAVEPOINT"):
+		stmt = query
+	case name == "MarkOutboxEventPublished":
+		stmt = fmt.Sprintf("%s %d", name, args[0].Value)
+	case name == "MarkOutboxEventFailed":
+		stmt = fmt.Sprintf("%s %d %v", name, args[2].Value, args[0].Value)
+	}
+	if stmt != "" {
+		if err := c.db.record(stmt); err != nil {
+			return nil, err
+		}
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	switch queryName(query) {
+	case "CreateUser":
+		nickname := args[1].Value.(string)
+		if err := c.db.record("CreateUser " + nickname); err != nil {
+			return nil, err
+		}
+		if c.db.taken[nickname] {
+			return nil, &pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint"}
+		}
+		now := time.Now()
+		return &fakeRows{
+			columns: userColumns,
+			values: [][]driver.Value{{args[0].Value, nickname, args[2].Value, args[3].Value, args[4].Value, now, now, false, int64(1),
+				args[5].Value, args[6].Value, args[7].Value, int64(0), int64(0)}},
+		}, nil
+	case "UpdateUser", "PatchUser":
+		return &fakeRows{columns: userColumns}, nil
+	case "GetUserByID", "GetUserByIDForUpdate":
+		rows := &fakeRows{columns: userColumns}
+		if u := c.db.stored; u != nil {
+			rows.values = [][]driver.Value{{u.ID.String(), u.Nickname, nil, nil, nil, u.Created, u.Updated, u.Deleted, int64(u.Version),
+				nil, nil, nil, int64(0), int64(0)}}
+		}
+		return rows, nil
+	case "CreateClub":
+		name := args[1].Value.(string)
+		policy := model.ClubJoinOpen
+		if c.db.inviteOnly[name] {
+			policy = model.ClubJoinInviteOnly
+		}
+		return &fakeRows{
+			columns: []string{"id", "name", "join_policy"},
+			values:  [][]driver.Value{{args[0].Value, name, policy}},
+		}, nil
+	case "GetClubByID", "SetClubJoinPolicy":
+		if err := c.db.record(queryName(query)); err != nil {
+			return nil, err
+		}
+		policy := model.ClubJoinOpen
+		if len(args) > 1 {
+			policy = args[1].Value.(string)
+		}
+		return &fakeRows{
+			columns: []string{"id", "name", "join_policy"},
+			values:  [][]driver.Value{{args[0].Value, "club", policy}},
+		}, nil
+	case "IsClubManager":
+		userID := args[1].Value.(string)
+		return &fakeRows{columns: []string{"exists"}, values: [][]driver.Value{{c.db.managers[userID]}}}, nil
+	case "ClaimOutboxEvents":
+		if err := c.db.record("ClaimOutboxEvents"); err != nil {
+			return nil, err
+		}
+		rows := &fakeRows{columns: []string{"id", "user_id", "event_type", "payload", "created_at", "attempts", "last_error", "next_attempt_at", "published_at"}}
+		for _, e := range c.db.outbox {
+			rows.values = append(rows.values, []driver.Value{e.ID, e.UserID.String(), e.Type, []byte(e.Payload), e.Created, int64(e.Attempts), nil, e.Created, nil})
+		}
+		return rows, nil
+	case "GetClubsByUserID":
+		return &fakeRows{columns: []string{"id", "name", "join_policy", "role", "joined_at"}}, nil
+	}
+	return nil, errors.New("unexpected query: " + query)
+}
+
+// userColumns are the columns of the queries returning users.
+var userColumns = []string{"id", "nickname", "img", "country", "city", "created_at", "updated_at", "deleted", "version",
+	"nickname_key", "nickname_skeleton", "city_id", "follower_count", "following_count"}
+
+// queryName is the sqlc name of the query, as given in its "-- name:" line.
+func queryName(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) < 3 || fields[0] != "--" || fields[1] != "name:" {
+		return ""
+	}
+	return fields[2]
+}
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (tx *fakeTx) Commit() error   { return tx.db.record("COMMIT") }
+func (tx *fakeTx) Rollback() error { return tx.db.record("ROLLBACK") }
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func TestAddBatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		db        fakeDB
+		users     []model.User
+		wantSaved []string
+		wantCodes []int
+		wantErr   bool
+		wantLog   []string
+	}{
+		{
+			name:      "all rows saved",
+			users:     []model.User{{Nickname: "john"}, {Nickname: "anna"}},
+			wantSaved: []string{"john", "anna"},
+			wantCodes: []int{0, 0},
+			wantLog: []string{
+				"BEGIN",
+				"SAVEPOINT import_row", "CreateUser john", "RELEASE SAVEPOINT import_row",
+				"SAVEPOINT import_row", "CreateUser anna", "RELEASE SAVEPOINT import_row",
+				"COMMIT",
+			},
+		},
+		{
+			name:      "taken nickname is rolled back alone",
+			db:        fakeDB{taken: map[string]bool{"anna": true}},
+			users:     []model.User{{Nickname: "john"}, {Nickname: "anna"}, {Nickname: "mark"}},
+			wantSaved: []string{"john", "", "mark"},
+			wantCodes: []int{0, http.StatusConflict, 0},
+			wantLog: []string{
+				"BEGIN",
+				"SAVEPOINT import_row", "CreateUser john", "RELEASE SAVEPOINT import_row",
+				"SAVEPOINT import_row", "CreateUser anna", "ROLLBACK TO SAVEPOINT import_row",
+				"SAVEPOINT import_row", "CreateUser mark", "RELEASE SAVEPOINT import_row",
+				"COMMIT",
+			},
+		},
+		{
+			name: "invite-only club is rolled back alone",
+			db:   fakeDB{inviteOnly: map[string]bool{"Insiders": true}},
+			users: []model.User{
+				{Nickname: "john", Clubs: []model.Club{{ID: uuid.New(), Name: "Insiders"}}},
+				{Nickname: "anna"},
+			},
+			wantSaved: []string{"", "anna"},
+			wantCodes: []int{http.StatusForbidden, 0},
+			wantLog: []string{
+				"BEGIN",
+				"SAVEPOINT import_row", "CreateUser john", "ROLLBACK TO SAVEPOINT import_row",
+				"SAVEPOINT import_row", "CreateUser anna", "RELEASE SAVEPOINT import_row",
+				"COMMIT",
+			},
+		},
+		{
+			name:    "failing savepoint aborts the batch",
+			db:      fakeDB{fail: map[string]bool{"RELEASE SAVEPOINT import_row": true}},
+			users:   []model.User{{Nickname: "john"}, {Nickname: "anna"}},
+			wantErr: true,
+			wantLog: []string{
+				"BEGIN",
+				"SAVEPOINT import_row", "CreateUser john", "RELEASE SAVEPOINT import_row",
+				"ROLLBACK",
+			},
+		},
+		{
+			name: "failing rollback to savepoint aborts the batch",
+			db: fakeDB{
+				taken: map[string]bool{"john": true},
+				fail:  map[string]bool{"ROLLBACK TO SAVEPOINT import_row": true},
+			},
+			users:   []model.User{{Nickname: "john"}, {Nickname: "anna"}},
+			wantErr: true,
+			wantLog: []string{
+				"BEGIN",
+				"SAVEPOINT import_row", "CreateUser john", "ROLLBACK TO SAVEPOINT import_row",
+				"ROLLBACK",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fdb := &tt.db
+			db := sql.OpenDB(fdb)
+			defer db.Close()
+
+			for i := range tt.users {
+				tt.users[i].ID = uuid.New()
+			}
+
+			saved, rowErrs, err := NewUsers(db).AddBatch(context.Background(), tt.users)
+			if !reflect.DeepEqual(fdb.log, tt.wantLog) {
+				t.Errorf("statements = %q, want %q", fdb.log, tt.wantLog)
+			}
+			if tt.wantErr {
+				if err == nil || err.Code != http.StatusInternalServerError {
+					t.Fatalf("err = %+v, want a 500", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+
+			for i, u := range tt.users {
+				code := 0
+				if rowErrs[i] != nil {
+					code = rowErrs[i].Code
+				}
+				if code != tt.wantCodes[i] {
+					t.Errorf("row %d error = %+v, want code %d", i, rowErrs[i], tt.wantCodes[i])
+				}
+				if saved[i].Nickname != tt.wantSaved[i] {
+					t.Errorf("row %d saved as %q, want %q", i, saved[i].Nickname, tt.wantSaved[i])
+				}
+				if tt.wantSaved[i] != "" && saved[i].ID != u.ID {
+					t.Errorf("row %d saved with id %s, want %s", i, saved[i].ID, u.ID)
+				}
+			}
+		})
+	}
+}
+
+func TestConditionalUpdateMiss(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name      string
+		stored    *model.User
+		wantCode  int
+		wantCause string
+	}{
+		{
+			name:      "stale version",
+			stored:    &model.User{ID: id, Nickname: "john", Version: 5},
+			wantCode:  http.StatusConflict,
+			wantCause: "user has been modified concurrently, current version is 5",
+		},
+		{
+			name:      "deleted user",
+			stored:    &model.User{ID: id, Nickname: "john", Version: 5, Deleted: true},
+			wantCode:  http.StatusNotFound,
+			wantCause: "user not found",
+		},
+		{
+			name:      "missing user",
+			wantCode:  http.StatusNotFound,
+			wantCause: "user not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sql.OpenDB(&fakeDB{stored: tt.stored})
+			defer db.Close()
+			r := NewUsers(db)
+			user := model.User{ID: id, Country: "PL", Version: 4}
+
+			_, updateErr := r.Update(context.Background(), user)
+			_, patchErr := r.Patch(context.Background(), user, []string{model.UserFieldCountry})
+
+			for op, err := range map[string]*resp.Err{"Update": updateErr, "Patch": patchErr} {
+				if err == nil || err.Code != tt.wantCode || !reflect.DeepEqual(err.Causes, []interface{}{tt.wantCause}) {
+					t.Errorf("%s err = %+v, want %d %q", op, err, tt.wantCode, tt.wantCause)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteUnchanged(t *testing.T) {
+	id := uuid.New()
+
+	tests := []struct {
+		name     string
+		stored   *model.User
+		wantCode int
+	}{
+		{name: "deleted user", stored: &model.User{ID: id, Nickname: "john", Version: 5, Deleted: true}},
+		{name: "missing user", wantCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fdb := &fakeDB{stored: tt.stored}
+			db := sql.OpenDB(fdb)
+			defer db.Close()
+
+			u, err := NewUsers(db).Delete(context.Background(), id)
+			if u != nil {
+				t.Errorf("deleted %+v, want nothing", u)
+			}
+			code := 0
+			if err != nil {
+				code = err.Code
+			}
+			if code != tt.wantCode {
+				t.Errorf("err = %+v, want code %d", err, tt.wantCode)
+			}
+			if want := []string{"BEGIN", "ROLLBACK"}; !reflect.DeepEqual(fdb.log, want) {
+				t.Errorf("statements = %q, want %q", fdb.log, want)
+			}
+		})
+	}
+}
+
+func TestSetJoinPolicyManagers(t *testing.T) {
+	clubID := uuid.New()
+	manager := uuid.New()
+
+	tests := []struct {
+		name      string
+		actor     string
+		wantCode  int
+		wantQuery []string
+	}{
+		{name: "owner or moderator", actor: manager.String(), wantQuery: []string{"GetClubByID", "SetClubJoinPolicy"}},
+		{name: "other user", actor: uuid.NewString(), wantCode: http.StatusForbidden, wantQuery: []string{"GetClubByID"}},
+		{name: "not a user", actor: "10.0.0.1", wantCode: http.StatusForbidden, wantQuery: []string{"GetClubByID"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fdb := &fakeDB{managers: map[string]bool{manager.String(): true}}
+			db := sql.OpenDB(fdb)
+			defer db.Close()
+
+			ctx := model.WithAuditActor(context.Background(), model.AuditActor{ID: tt.actor, Transport: model.AuditTransportGin})
+			c, err := NewClubs(db).SetJoinPolicy(ctx, clubID, model.ClubJoinInviteOnly)
+			code := 0
+			if err != nil {
+				code = err.Code
+			}
+			if code != tt.wantCode {
+				t.Fatalf("err = %+v, want code %d", err, tt.wantCode)
+			}
+			if err == nil && c.JoinPolicy != model.ClubJoinInviteOnly {
+				t.Errorf("join policy = %q, want %q", c.JoinPolicy, model.ClubJoinInviteOnly)
+			}
+			if !reflect.DeepEqual(fdb.log, tt.wantQuery) {
+				t.Errorf("statements = %q, want %q", fdb.log, tt.wantQuery)
+			}
+		})
+	}
+}
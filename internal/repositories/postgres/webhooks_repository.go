@@ -0,0 +1,251 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	model "github.com/demkowo/users/internal/models"
+	"github.com/demkowo/users/internal/repositories/postgres/sqlc"
+	"github.com/demkowo/utils/resp"
+	"github.com/google/uuid"
+)
+
+type Webhooks interface {
+	Add(ctx context.Context, webhook model.Webhook) (model.Webhook, *resp.Err)
+	Delete(ctx context.Context, id uuid.UUID) (model.Webhook, *resp.Err)
+	GetByID(ctx context.Context, id uuid.UUID) (model.Webhook, *resp.Err)
+	List(ctx context.Context, limit, offset int32) ([]model.Webhook, int64, *resp.Err)
+	ListForEvent(ctx context.Context, eventType string) ([]model.Webhook, *resp.Err)
+	Update(ctx context.Context, webhook model.Webhook) (model.Webhook, *resp.Err)
+	AddDelivery(ctx context.Context, delivery model.WebhookDelivery) (model.WebhookDelivery, *resp.Err)
+	ClaimDeliveries(ctx context.Context, limit int32, leaseUntil time.Time) ([]model.WebhookDelivery, *resp.Err)
+	GetDelivery(ctx context.Context, id uuid.UUID) (model.WebhookDelivery, *resp.Err)
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit, offset int32) ([]model.WebhookDelivery, int64, *resp.Err)
+	RecordAttempt(ctx context.Context, delivery model.WebhookDelivery) *resp.Err
+}
+
+type webhooks struct {
+	db *sql.DB
+	q  *sqlc.Queries
+}
+
+func NewWebhooks(db *sql.DB) Webhooks {
+	return &webhooks{
+		db: db,
+		q:  sqlc.New(db),
+	}
+}
+
+func (r *webhooks) Add(ctx context.Context, webhook model.Webhook) (model.Webhook, *resp.Err) {
+	w, err := r.q.CreateWebhook(ctx, sqlc.CreateWebhookParams{
+		ID:         webhook.ID,
+		Url:        webhook.URL,
+		EventTypes: webhook.EventTypes,
+		Secret:     webhook.Secret,
+		Active:     webhook.Active,
+	})
+	if err != nil {
+		return model.Webhook{}, resp.Error(http.StatusInternalServerError, "failed to add webhook", []interface{}{err.Error()})
+	}
+
+	return webhookToDomain(w), nil
+}
+
+func (r *webhooks) Delete(ctx context.Context, id uuid.UUID) (model.Webhook, *resp.Err) {
+	w, err := r.q.DeleteWebhook(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Webhook{}, resp.Error(http.StatusNotFound, "failed to delete webhook", []interface{}{"webhook not found"})
+		}
+		return model.Webhook{}, resp.Error(http.StatusInternalServerError, "failed to delete webhook", []interface{}{err.Error()})
+	}
+
+	return webhookToDomain(w), nil
+}
+
+func (r *webhooks) GetByID(ctx context.Context, id uuid.UUID) (model.Webhook, *resp.Err) {
+	w, err := r.q.GetWebhookByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Webhook{}, resp.Error(http.StatusNotFound, "failed to get webhook", []interface{}{"webhook not found"})
+		}
+		return model.Webhook{}, resp.Error(http.StatusInternalServerError, "failed to get webhook", []interface{}{err.Error()})
+	}
+
+	return webhookToDomain(w), nil
+}
+
+func (r *webhooks) List(ctx context.Context, limit, offset int32) ([]model.Webhook, int64, *resp.Err) {
+	ws, err := r.q.ListWebhooks(ctx, sqlc.ListWebhooksParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list webhooks", []interface{}{err.Error()})
+	}
+
+	total, err := r.q.CountWebhooks(ctx)
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list webhooks", []interface{}{err.Error()})
+	}
+
+	return webhooksToDomain(ws), total, nil
+}
+
+// ListForEvent returns the active webhooks subscribed to eventType.
+func (r *webhooks) ListForEvent(ctx context.Context, eventType string) ([]model.Webhook, *resp.Err) {
+	ws, err := r.q.ListWebhooksForEvent(ctx, eventType)
+	if err != nil {
+		return nil, resp.Error(http.StatusInternalServerError, "failed to list webhooks", []interface{}{err.Error()})
+	}
+
+	return webhooksToDomain(ws), nil
+}
+
+// Update replaces the webhook's settings. An empty secret keeps the current one.
+func (r *webhooks) Update(ctx context.Context, webhook model.Webhook) (model.Webhook, *resp.Err) {
+	w, err := r.q.UpdateWebhook(ctx, sqlc.UpdateWebhookParams{
+		Url:        webhook.URL,
+		EventTypes: webhook.EventTypes,
+		Secret:     nullString(webhook.Secret),
+		Active:     webhook.Active,
+		ID:         webhook.ID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.Webhook{}, resp.Error(http.StatusNotFound, "failed to update webhook", []interface{}{"webhook not found"})
+		}
+		return model.Webhook{}, resp.Error(http.StatusInternalServerError, "failed to update webhook", []interface{}{err.Error()})
+	}
+
+	return webhookToDomain(w), nil
+}
+
+func (r *webhooks) AddDelivery(ctx context.Context, delivery model.WebhookDelivery) (model.WebhookDelivery, *resp.Err) {
+	d, err := r.q.CreateWebhookDelivery(ctx, sqlc.CreateWebhookDeliveryParams{
+		ID:        delivery.ID,
+		WebhookID: delivery.WebhookID,
+		EventType: delivery.EventType,
+		Payload:   delivery.Payload,
+	})
+	if err != nil {
+		if isUniqueViolation(err) {
+			return model.WebhookDelivery{}, resp.Error(http.StatusConflict, "failed to add webhook delivery", []interface{}{"delivery already exists"})
+		}
+		return model.WebhookDelivery{}, resp.Error(http.StatusInternalServerError, "failed to add webhook delivery", []interface{}{err.Error()})
+	}
+
+	return deliveryToDomain(d), nil
+}
+
+// ClaimDeliveries picks up to limit due deliveries and postpones them until leaseUntil, so
+// no other dispatcher takes them meanwhile.
+func (r *webhooks) ClaimDeliveries(ctx context.Context, limit int32, leaseUntil time.Time) ([]model.WebhookDelivery, *resp.Err) {
+	ds, err := r.q.ClaimWebhookDeliveries(ctx, sqlc.ClaimWebhookDeliveriesParams{
+		LeaseUntil: leaseUntil,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, resp.Error(http.StatusInternalServerError, "failed to claim webhook deliveries", []interface{}{err.Error()})
+	}
+
+	return deliveriesToDomain(ds), nil
+}
+
+func (r *webhooks) GetDelivery(ctx context.Context, id uuid.UUID) (model.WebhookDelivery, *resp.Err) {
+	d, err := r.q.GetWebhookDeliveryByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.WebhookDelivery{}, resp.Error(http.StatusNotFound, "failed to get webhook delivery", []interface{}{"delivery not found"})
+		}
+		return model.WebhookDelivery{}, resp.Error(http.StatusInternalServerError, "failed to get webhook delivery", []interface{}{err.Error()})
+	}
+
+	return deliveryToDomain(d), nil
+}
+
+func (r *webhooks) ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit, offset int32) ([]model.WebhookDelivery, int64, *resp.Err) {
+	ds, err := r.q.ListWebhookDeliveries(ctx, sqlc.ListWebhookDeliveriesParams{
+		WebhookID: webhookID,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list webhook deliveries", []interface{}{err.Error()})
+	}
+
+	total, err := r.q.CountWebhookDeliveries(ctx, webhookID)
+	if err != nil {
+		return nil, 0, resp.Error(http.StatusInternalServerError, "failed to list webhook deliveries", []interface{}{err.Error()})
+	}
+
+	return deliveriesToDomain(ds), total, nil
+}
+
+// RecordAttempt saves the outcome of an attempt to send the delivery: its new
+// status, the response code or error and when to try again.
+func (r *webhooks) RecordAttempt(ctx context.Context, delivery model.WebhookDelivery) *resp.Err {
+	if err := r.q.RecordWebhookDeliveryAttempt(ctx, sqlc.RecordWebhookDeliveryAttemptParams{
+		Status:         delivery.Status,
+		LastStatusCode: nullInt32(delivery.LastStatusCode),
+		LastError:      nullString(delivery.LastError),
+		NextAttemptAt:  delivery.NextAttempt,
+		ID:             delivery.ID,
+	}); err != nil {
+		return resp.Error(http.StatusInternalServerError, "failed to record webhook delivery", []interface{}{err.Error()})
+	}
+
+	return nil
+}
+
+func webhookToDomain(w sqlc.Webhook) model.Webhook {
+	return model.Webhook{
+		ID:         w.ID,
+		URL:        w.Url,
+		EventTypes: w.EventTypes,
+		Secret:     w.Secret,
+		Active:     w.Active,
+		Created:    w.CreatedAt,
+		Updated:    w.UpdatedAt,
+	}
+}
+
+func webhooksToDomain(ws []sqlc.Webhook) []model.Webhook {
+	res := make([]model.Webhook, 0, len(ws))
+	for _, w := range ws {
+		res = append(res, webhookToDomain(w))
+	}
+	return res
+}
+
+func deliveryToDomain(d sqlc.WebhookDelivery) model.WebhookDelivery {
+	var delivered *time.Time
+	if d.DeliveredAt.Valid {
+		delivered = &d.DeliveredAt.Time
+	}
+
+	return model.WebhookDelivery{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		NextAttempt:    d.NextAttemptAt,
+		LastStatusCode: d.LastStatusCode.Int32,
+		LastError:      nullStringToString(d.LastError),
+		Created:        d.CreatedAt,
+		Delivered:      delivered,
+	}
+}
+
+func deliveriesToDomain(ds []sqlc.WebhookDelivery) []model.WebhookDelivery {
+	res := make([]model.WebhookDelivery, 0, len(ds))
+	for _, d := range ds {
+		res = append(res, deliveryToDomain(d))
+	}
+	return res
+}
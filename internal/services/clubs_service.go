@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	model "github.com/demkowo/users/internal/models"
+	"github.com/demkowo/utils/resp"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+type ClubsRepo interface {
+	Add(ctx context.Context, club model.Club) (model.Club, *resp.Err)
+	AddRequest(ctx context.Context, request model.ClubRequest) (model.ClubRequest, *model.User, *resp.Err)
+	DecideRequest(ctx context.Context, clubID, requestID uuid.UUID, status string) (model.ClubRequest, *model.User, *resp.Err)
+	Delete(ctx context.Context, id uuid.UUID) (model.Club, *resp.Err)
+	GetByID(ctx context.Context, id uuid.UUID) (model.Club, *resp.Err)
+	List(ctx context.Context, limit, offset int32) ([]model.Club, int64, *resp.Err)
+	ListMembers(ctx context.Context, clubID uuid.UUID, limit, offset int32) ([]model.ClubMember, int64, *resp.Err)
+	ListRequests(ctx context.Context, filter model.ClubRequestFilter, limit, offset int32) ([]model.ClubRequest, int64, *resp.Err)
+	Rename(ctx context.Context, id uuid.UUID, name string) (model.Club, *resp.Err)
+	SetJoinPolicy(ctx context.Context, id uuid.UUID, policy string) (model.Club, *resp.Err)
+	SetMemberRole(ctx context.Context, clubID, userID uuid.UUID, role string) (model.ClubMember, bool, *resp.Err)
+}
+
+type Clubs interface {
+	AcceptRequest(ctx context.Context, clubID, requestID uuid.UUID) (*model.ClubRequest, *resp.Err)
+	Add(ctx context.Context, club *model.Club) *resp.Err
+	Delete(ctx context.Context, id uuid.UUID) *resp.Err
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Club, *resp.Err)
+	Invite(ctx context.Context, clubID, userID uuid.UUID) (*model.ClubRequest, *resp.Err)
+	List(ctx context.Context, limit, offset int32) ([]model.Club, int64, *resp.Err)
+	ListMembers(ctx context.Context, clubID uuid.UUID, limit, offset int32) ([]model.ClubMember, int64, *resp.Err)
+	ListRequests(ctx context.Context, filter model.ClubRequestFilter, limit, offset int32) ([]model.ClubRequest, int64, *resp.Err)
+	RejectRequest(ctx context.Context, clubID, requestID uuid.UUID) (*model.ClubRequest, *resp.Err)
+	Rename(ctx context.Context, id uuid.UUID, name string) (*model.Club, *resp.Err)
+	RequestToJoin(ctx context.Context, clubID, userID uuid.UUID) (*model.ClubRequest, *resp.Err)
+	SetJoinPolicy(ctx context.Context, id uuid.UUID, policy string) (*model.Club, *resp.Err)
+	SetMemberRole(ctx context.Context, clubID, userID uuid.UUID, role string) (*model.ClubMember, *resp.Err)
+}
+
+// ClubsConfig holds the tunables of the clubs service.
+type ClubsConfig struct {
+	// RequestTTL is how long join requests and invitations stay pending
+	// before they expire.
+	RequestTTL time.Duration
+}
+
+type clubs struct {
+	repo   ClubsRepo
+	events Events
+	conf   ClubsConfig
+}
+
+func NewClubs(repo ClubsRepo, events Events, conf ClubsConfig) Clubs {
+	log.Trace()
+
+	if conf.RequestTTL <= 0 {
+		conf.RequestTTL = 14 * 24 * time.Hour
+	}
+
+	return &clubs{
+		repo:   repo,
+		events: events,
+		conf:   conf,
+	}
+}
+
+// AcceptRequest accepts a pending join request or invitation of the club,
+// making its user a member.
+func (s *clubs) AcceptRequest(ctx context.Context, clubID, requestID uuid.UUID) (*model.ClubRequest, *resp.Err) {
+	log.Trace()
+
+	return s.decideRequest(ctx, clubID, requestID, model.ClubRequestAccepted)
+}
+
+func (s *clubs) Add(ctx context.Context, club *model.Club) *resp.Err {
+	log.Trace()
+
+	club.Name = strings.TrimSpace(club.Name)
+	if club.Name == "" {
+		return resp.Error(http.StatusBadRequest, "failed to add club", []interface{}{"club name is required"})
+	}
+
+	policy, errs := normalizeJoinPolicy(club.JoinPolicy, model.ClubJoinOpen)
+	if errs != nil {
+		return resp.Error(http.StatusBadRequest, "failed to add club", fieldCauses(errs))
+	}
+	club.JoinPolicy = policy
+
+	c, err := s.repo.Add(ctx, *club)
+	if err != nil {
+		return err
+	}
+
+	*club = c
+	return nil
+}
+
+func (s *clubs) Delete(ctx context.Context, id uuid.UUID) *resp.Err {
+	log.Trace()
+
+	if _, err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *clubs) GetByID(ctx context.Context, id uuid.UUID) (*model.Club, *resp.Err) {
+	log.Trace()
+
+	c, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// Invite invites the user to the club. The invitation is pending until the
+// user accepts or rejects it, or until it expires.
+func (s *clubs) Invite(ctx context.Context, clubID, userID uuid.UUID) (*model.ClubRequest, *resp.Err) {
+	log.Trace()
+
+	return s.addRequest(ctx, clubID, userID, model.ClubRequestInvitation)
+}
+
+func (s *clubs) List(ctx context.Context, limit, offset int32) ([]model.Club, int64, *resp.Err) {
+	log.Trace()
+
+	return s.repo.List(ctx, limit, offset)
+}
+
+func (s *clubs) ListMembers(ctx context.Context, clubID uuid.UUID, limit, offset int32) ([]model.ClubMember, int64, *resp.Err) {
+	log.Trace()
+
+	if _, err := s.repo.GetByID(ctx, clubID); err != nil {
+		return nil, 0, err
+	}
+
+	members, total, err := s.repo.ListMembers(ctx, clubID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range members {
+		localizeUser(ctx, &members[i].User)
+	}
+	return members, total, nil
+}
+
+// ListRequests returns the join requests and invitations matching the filter,
+// newest first. When the filter names a club, the club must exist.
+func (s *clubs) ListRequests(ctx context.Context, filter model.ClubRequestFilter, limit, offset int32) ([]model.ClubRequest, int64, *resp.Err) {
+	log.Trace()
+
+	filter.Kind = strings.ToLower(strings.TrimSpace(filter.Kind))
+	filter.Status = strings.ToLower(strings.TrimSpace(filter.Status))
+
+	var errs []model.FieldError
+	if filter.Kind != "" && !slices.Contains(model.ClubRequestKinds, filter.Kind) {
+		errs = append(errs, model.FieldError{Field: "kind", Message: "kind must be one of " + strings.Join(model.ClubRequestKinds, ", ")})
+	}
+	if filter.Status != "" && !slices.Contains(model.ClubRequestStatuses, filter.Status) {
+		errs = append(errs, model.FieldError{Field: "status", Message: "status must be one of " + strings.Join(model.ClubRequestStatuses, ", ")})
+	}
+	if errs != nil {
+		return nil, 0, resp.Error(http.StatusBadRequest, "failed to list club requests", fieldCauses(errs))
+	}
+
+	if filter.ClubID != uuid.Nil {
+		if _, err := s.repo.GetByID(ctx, filter.ClubID); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return s.repo.ListRequests(ctx, filter, limit, offset)
+}
+
+// RejectRequest rejects a pending join request or invitation of the club.
+func (s *clubs) RejectRequest(ctx context.Context, clubID, requestID uuid.UUID) (*model.ClubRequest, *resp.Err) {
+	log.Trace()
+
+	return s.decideRequest(ctx, clubID, requestID, model.ClubRequestRejected)
+}
+
+func (s *clubs) Rename(ctx context.Context, id uuid.UUID, name string) (*model.Club, *resp.Err) {
+	log.Trace()
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, resp.Error(http.StatusBadRequest, "failed to rename club", []interface{}{"club name is required"})
+	}
+
+	c, err := s.repo.Rename(ctx, id, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// RequestToJoin asks for the user to join the club; in an open club it is accepted at once.
+func (s *clubs) RequestToJoin(ctx context.Context, clubID, userID uuid.UUID) (*model.ClubRequest, *resp.Err) {
+	log.Trace()
+
+	return s.addRequest(ctx, clubID, userID, model.ClubRequestJoin)
+}
+
+// SetJoinPolicy opens the club to anyone or makes it invite-only.
+func (s *clubs) SetJoinPolicy(ctx context.Context, id uuid.UUID, policy string) (*model.Club, *resp.Err) {
+	log.Trace()
+
+	policy, errs := normalizeJoinPolicy(policy, "")
+	if errs != nil {
+		return nil, resp.Error(http.StatusBadRequest, "failed to change club join policy", fieldCauses(errs))
+	}
+
+	c, err := s.repo.SetJoinPolicy(ctx, id, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// SetMemberRole promotes or demotes a member of the club to role, one of
+// model.ClubRoles. The last owner of a club cannot be demoted.
+func (s *clubs) SetMemberRole(ctx context.Context, clubID, userID uuid.UUID, role string) (*model.ClubMember, *resp.Err) {
+	log.Trace()
+
+	role = strings.ToLower(strings.TrimSpace(role))
+	if !slices.Contains(model.ClubRoles, role) {
+		return nil, resp.Error(http.StatusBadRequest, "failed to change member role", fieldCauses([]model.FieldError{{
+			Field:   "role",
+			Message: "role must be one of " + strings.Join(model.ClubRoles, ", "),
+		}}))
+	}
+
+	if _, err := s.repo.GetByID(ctx, clubID); err != nil {
+		return nil, err
+	}
+
+	m, changed, err := s.repo.SetMemberRole(ctx, clubID, userID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	if changed {
+		s.publish(m.User)
+	}
+	localizeUser(ctx, &m.User)
+	return &m, nil
+}
+
+func (s *clubs) addRequest(ctx context.Context, clubID, userID uuid.UUID, kind string) (*model.ClubRequest, *resp.Err) {
+	cr, joined, err := s.repo.AddRequest(ctx, model.ClubRequest{
+		ID:      uuid.New(),
+		ClubID:  clubID,
+		UserID:  userID,
+		Kind:    kind,
+		Expires: time.Now().Add(s.conf.RequestTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if joined != nil {
+		s.publish(*joined)
+	}
+	return &cr, nil
+}
+
+func (s *clubs) decideRequest(ctx context.Context, clubID, requestID uuid.UUID, status string) (*model.ClubRequest, *resp.Err) {
+	cr, joined, err := s.repo.DecideRequest(ctx, clubID, requestID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	if joined != nil {
+		s.publish(*joined)
+	}
+	return &cr, nil
+}
+
+// publish announces that the clubs of the user changed.
+func (s *clubs) publish(u model.User) {
+	s.events.Publish(model.UserEvent{
+		Type:   model.UserEventUpdated,
+		UserID: u.ID,
+		User:   u,
+	})
+}
+
+// normalizeJoinPolicy returns policy in its canonical form, or def when it is empty.
+func normalizeJoinPolicy(policy, def string) (string, []model.FieldError) {
+	policy = strings.ToLower(strings.TrimSpace(policy))
+	if policy == "" && def != "" {
+		return def, nil
+	}
+	if !slices.Contains(model.ClubJoinPolicies, policy) {
+		return "", []model.FieldError{{Field: "join_policy", Message: "join_policy must be one of " + strings.Join(model.ClubJoinPolicies, ", ")}}
+	}
+	return policy, nil
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+
+	model "github.com/demkowo/users/internal/models"
+)
+
+// normalizeCountry returns the alpha-2 code of an optional country code or name.
+func normalizeCountry(country string) (string, []model.FieldError) {
+	if country == "" {
+		return "", nil
+	}
+
+	code, ok := model.ParseCountry(country)
+	if !ok {
+		return "", []model.FieldError{{Field: "country", Message: "country must be an ISO 3166-1 country code or name"}}
+	}
+
+	return code, nil
+}
+
+// localizeUser names the user's country in the language of the request.
+func localizeUser(ctx context.Context, u *model.User) {
+	if u.Country != "" {
+		u.CountryName = model.CountryName(u.Country, model.LocaleFrom(ctx))
+	}
+}
+
+func localizeUsers(ctx context.Context, us []model.User) {
+	for i := range us {
+		localizeUser(ctx, &us[i])
+	}
+}
+
+func localizeFollowUsers(ctx context.Context, us []model.FollowUser) {
+	for i := range us {
+		localizeUser(ctx, &us[i].User)
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	model "github.com/demkowo/users/internal/models"
+	"github.com/demkowo/utils/resp"
+	log "github.com/sirupsen/logrus"
+)
+
+// subscriberBuffer is how far a subscriber may lag behind before it is dropped.
+const subscriberBuffer = 64
+
+type Events interface {
+	Publish(event model.UserEvent) model.UserEvent
+	Subscribe(ctx context.Context, afterID uint64) (<-chan model.UserEvent, *resp.Err)
+}
+
+// EventsConfig holds the tunables of the events service.
+type EventsConfig struct {
+	// History is the number of recent events kept in memory for subscribers
+	// that resume from a last-seen event id.
+	History int
+}
+
+type events struct {
+	mu      sync.Mutex
+	lastID  uint64
+	history []model.UserEvent
+	size    int
+	subs    map[chan model.UserEvent]struct{}
+}
+
+// NewEvents returns an in-process broker of user events. Event ids start from
+// the current time in microseconds, so they keep growing across restarts and a
+// client resuming with an id from before a restart is told to resync instead
+// of silently missing events.
+func NewEvents(conf EventsConfig) Events {
+	log.Trace()
+
+	size := conf.History
+	if size <= 0 {
+		size = 1000
+	}
+
+	return &events{
+		lastID:  uint64(time.Now().UnixMicro()),
+		history: make([]model.UserEvent, 0, size),
+		size:    size,
+		subs:    make(map[chan model.UserEvent]struct{}),
+	}
+}
+
+// Publish assigns the next id and time to the event and fans it out, dropping subscribers
+// that can't keep up.
+func (e *events) Publish(event model.UserEvent) model.UserEvent {
+	log.Trace()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.lastID++
+	event.ID = e.lastID
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	if len(e.history) == e.size {
+		copy(e.history, e.history[1:])
+		e.history = e.history[:len(e.history)-1]
+	}
+	e.history = append(e.history, event)
+
+	for ch := range e.subs {
+		select {
+		case ch <- event:
+		default:
+			log.Warn("dropping user events subscriber that fell behind")
+			delete(e.subs, ch)
+			close(ch)
+		}
+	}
+
+	return event
+}
+
+// Subscribe streams events after afterID (only new ones for 0) until ctx is done. It fails
+// with 410 Gone when they are no longer in the history.
+func (e *events) Subscribe(ctx context.Context, afterID uint64) (<-chan model.UserEvent, *resp.Err) {
+	log.Trace()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var replay []model.UserEvent
+	if afterID != 0 && afterID != e.lastID {
+		if afterID > e.lastID || len(e.history) == 0 || afterID < e.history[0].ID-1 {
+			return nil, resp.Error(http.StatusGone, "failed to subscribe to user events", []interface{}{"events after the given id are no longer available"})
+		}
+		for _, ev := range e.history {
+			if ev.ID > afterID {
+				replay = append(replay, ev)
+			}
+		}
+	}
+
+	ch := make(chan model.UserEvent, len(replay)+subscriberBuffer)
+	for _, ev := range replay {
+		ch <- ev
+	}
+	e.subs[ch] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if _, ok := e.subs[ch]; ok {
+			delete(e.subs, ch)
+			close(ch)
+		}
+	}()
+
+	return ch, nil
+}
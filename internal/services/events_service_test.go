@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	model "github.com/demkowo/users/internal/models"
+	"github.com/google/uuid"
+)
+
+// drain reads the events already buffered in ch and reports whether it is
+// closed.
+func drain(ch <-chan model.UserEvent) (ids []uint64, closed bool) {
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return ids, true
+			}
+			ids = append(ids, ev.ID)
+		default:
+			return ids, false
+		}
+	}
+}
+
+func TestSubscribeResume(t *testing.T) {
+	e := NewEvents(EventsConfig{History: 3})
+	var ids []uint64
+	for i := 0; i < 5; i++ {
+		ids = append(ids, e.Publish(model.UserEvent{Type: model.UserEventUpdated, UserID: uuid.New()}).ID)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] != ids[i-1]+1 {
+			t.Fatalf("ids = %d, want consecutive ids", ids)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		afterID  uint64
+		wantGone bool
+		want     []uint64
+	}{
+		{name: "only new events", afterID: 0},
+		{name: "up to date", afterID: ids[4]},
+		{name: "within the history", afterID: ids[3], want: ids[4:]},
+		{name: "right before the history", afterID: ids[1], want: ids[2:]},
+		{name: "before the history", afterID: ids[0], wantGone: true},
+		{name: "from before a restart", afterID: 1, wantGone: true},
+		{name: "ahead of the last event", afterID: ids[4] + 1, wantGone: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			ch, err := e.Subscribe(ctx, tt.afterID)
+			if tt.wantGone {
+				if err == nil || err.Code != http.StatusGone {
+					t.Fatalf("err = %+v, want 410", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+
+			got, closed := drain(ch)
+			if closed {
+				t.Fatal("channel closed, want it open")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("replayed %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscribeLive(t *testing.T) {
+	e := NewEvents(EventsConfig{})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ch, err := e.Subscribe(ctx, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	published := e.Publish(model.UserEvent{Type: model.UserEventCreated, UserID: uuid.New()})
+	if published.Time.IsZero() {
+		t.Error("published event has no time")
+	}
+	if got, _ := drain(ch); !reflect.DeepEqual(got, []uint64{published.ID}) {
+		t.Errorf("received %d, want %d", got, published.ID)
+	}
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("received an event after the subscription ended")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed after the context was cancelled")
+	}
+}
+
+func TestPublishDropsSlowSubscriber(t *testing.T) {
+	e := NewEvents(EventsConfig{History: 2 * subscriberBuffer})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	slow, err := e.Subscribe(ctx, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	fast, err := e.Subscribe(ctx, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	var want []uint64
+	for i := 0; i <= subscriberBuffer; i++ {
+		if i == subscriberBuffer {
+			// fast keeps up, slow doesn't read at all
+			got, _ := drain(fast)
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("fast subscriber received %d, want %d", got, want)
+			}
+		}
+		want = append(want, e.Publish(model.UserEvent{Type: model.UserEventUpdated, UserID: uuid.New()}).ID)
+	}
+
+	got, closed := drain(slow)
+	if !closed {
+		t.Error("slow subscriber still subscribed, want it dropped")
+	}
+	if !reflect.DeepEqual(got, want[:subscriberBuffer]) {
+		t.Errorf("slow subscriber received %d events, want the first %d", len(got), subscriberBuffer)
+	}
+	if got, closed := drain(fast); closed || !reflect.DeepEqual(got, want[subscriberBuffer:]) {
+		t.Errorf("fast subscriber received %d (closed %t), want %d", got, closed, want[subscriberBuffer:])
+	}
+
+	// the dropped subscriber resumes from its last event
+	resumed, err := e.Subscribe(ctx, want[subscriberBuffer-1])
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+	if got, _ := drain(resumed); !reflect.DeepEqual(got, want[subscriberBuffer:]) {
+		t.Errorf("resumed subscriber received %d, want %d", got, want[subscriberBuffer:])
+	}
+}
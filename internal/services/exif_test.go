@@ -0,0 +1,199 @@
+package service
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+// exifTIFF builds the TIFF structure of an EXIF segment whose first IFD holds
+// a Make tag followed by the given orientation.
+func exifTIFF(order binary.ByteOrder, orientation uint16) []byte {
+	var b bytes.Buffer
+	if order == binary.LittleEndian {
+		b.WriteString("II")
+	} else {
+		b.WriteString("MM")
+	}
+	binary.Write(&b, order, uint16(42))
+	binary.Write(&b, order, uint32(8))
+
+	binary.Write(&b, order, uint16(2))
+	// Make, ASCII, 4 bytes held in the value itself.
+	binary.Write(&b, order, []uint16{0x010f, 2})
+	binary.Write(&b, order, uint32(4))
+	b.WriteString("Cam\x00")
+	// Orientation, SHORT, left-justified in the value.
+	binary.Write(&b, order, []uint16{exifOrientationTag, 3})
+	binary.Write(&b, order, uint32(1))
+	binary.Write(&b, order, []uint16{orientation, 0})
+	// No next IFD.
+	binary.Write(&b, order, uint32(0))
+
+	return b.Bytes()
+}
+
+// jpegSegment builds a JPEG marker segment with the given payload.
+func jpegSegment(marker byte, payload []byte) []byte {
+	seg := []byte{0xff, marker, 0, 0}
+	binary.BigEndian.PutUint16(seg[2:], uint16(len(payload)+2))
+	return append(seg, payload...)
+}
+
+// exifJPEG builds the head of a JPEG file: SOI, the given segments and the
+// start of the scan.
+func exifJPEG(segments ...[]byte) []byte {
+	data := []byte{0xff, 0xd8}
+	for _, s := range segments {
+		data = append(data, s...)
+	}
+	data = append(data, jpegSegment(0xda, []byte{1, 1, 0, 0, 63, 0})...)
+	return append(data, 0xff, 0xd9)
+}
+
+func exifSegment(tiff []byte) []byte {
+	return jpegSegment(0xe1, append([]byte("Exif\x00\x00"), tiff...))
+}
+
+var jfifSegment = jpegSegment(0xe0, []byte("JFIF\x00\x01\x02\x00\x00\x01\x00\x01\x00\x00"))
+
+func TestJPEGOrientation(t *testing.T) {
+	type test struct {
+		name string
+		data []byte
+		want int
+	}
+
+	var tests []test
+	for _, order := range []binary.ByteOrder{binary.BigEndian, binary.LittleEndian} {
+		for o := 1; o <= 8; o++ {
+			tests = append(tests, test{
+				name: fmt.Sprintf("%s orientation %d", order, o),
+				data: exifJPEG(jfifSegment, exifSegment(exifTIFF(order, uint16(o)))),
+				want: o,
+			})
+		}
+	}
+
+	full := exifJPEG(exifSegment(exifTIFF(binary.BigEndian, 6)))
+	truncatedIFD := exifTIFF(binary.LittleEndian, 6)
+	truncatedIFD = truncatedIFD[:len(truncatedIFD)-10]
+	farIFD := exifTIFF(binary.BigEndian, 6)
+	binary.BigEndian.PutUint32(farIFD[4:], 1<<16)
+	longAPP1 := exifSegment(exifTIFF(binary.BigEndian, 6))
+	binary.BigEndian.PutUint16(longAPP1[2:], uint16(len(longAPP1)))
+	xmp := jpegSegment(0xe1, []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta/>"))
+
+	tests = append(tests, []test{
+		{name: "EXIF first", data: exifJPEG(exifSegment(exifTIFF(binary.LittleEndian, 3))), want: 3},
+		{name: "EXIF after XMP", data: exifJPEG(xmp, exifSegment(exifTIFF(binary.BigEndian, 8))), want: 8},
+		{name: "no EXIF", data: exifJPEG(jfifSegment), want: 1},
+		{name: "EXIF after the scan", data: append(exifJPEG(jfifSegment), exifSegment(exifTIFF(binary.BigEndian, 6))...), want: 1},
+		{name: "not a JPEG", data: []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR"), want: 1},
+		{name: "empty", data: nil, want: 1},
+		{name: "SOI only", data: []byte{0xff, 0xd8}, want: 1},
+		{name: "truncated APP1 segment", data: full[:20], want: 1},
+		{name: "APP1 size past the end", data: append([]byte{0xff, 0xd8}, longAPP1...), want: 1},
+		{name: "truncated IFD", data: exifJPEG(exifSegment(truncatedIFD)), want: 1},
+		{name: "IFD offset past the end", data: exifJPEG(exifSegment(farIFD)), want: 1},
+		{name: "unknown byte order", data: exifJPEG(exifSegment(append([]byte("XX"), exifTIFF(binary.BigEndian, 6)[2:]...))), want: 1},
+		{name: "orientation out of range", data: exifJPEG(exifSegment(exifTIFF(binary.BigEndian, 9))), want: 1},
+		{name: "zero orientation", data: exifJPEG(exifSegment(exifTIFF(binary.LittleEndian, 0))), want: 1},
+		{name: "segment size below two", data: []byte{0xff, 0xd8, 0xff, 0xe1, 0x00, 0x01, 0x00, 0x00}, want: 1},
+		{name: "garbage between segments", data: []byte{0xff, 0xd8, 0x00, 0xff, 0xe1, 0x00, 0x02, 0xff, 0xd9}, want: 1},
+	}...)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jpegOrientation(tt.data); got != tt.want {
+				t.Errorf("jpegOrientation = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// grid is a small image of gray levels, one string per row with a letter per
+// pixel.
+func grid(rows ...string) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, len(rows[0]), len(rows)))
+	for y, row := range rows {
+		for x, c := range row {
+			img.SetGray(x, y, color.Gray{Y: uint8(c)})
+		}
+	}
+	return img
+}
+
+// rows reads an image back into one string per row with a letter per pixel.
+func rows(img image.Image) []string {
+	b := img.Bounds()
+	var rs []string
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		var row []byte
+		for x := b.Min.X; x < b.Max.X; x++ {
+			row = append(row, color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y)
+		}
+		rs = append(rs, string(row))
+	}
+	return rs
+}
+
+func TestOrient(t *testing.T) {
+	// src is how a camera stores the photo; orient must turn it the way its
+	// EXIF orientation asks for.
+	src := grid(
+		"abc",
+		"def",
+	)
+
+	tests := []struct {
+		orientation int
+		want        []string
+	}{
+		{orientation: 0, want: []string{"abc", "def"}},
+		{orientation: 1, want: []string{"abc", "def"}},
+		// Mirrored horizontally.
+		{orientation: 2, want: []string{"cba", "fed"}},
+		// Rotated by 180°.
+		{orientation: 3, want: []string{"fed", "cba"}},
+		// Mirrored vertically.
+		{orientation: 4, want: []string{"def", "abc"}},
+		// Transposed.
+		{orientation: 5, want: []string{"ad", "be", "cf"}},
+		// Rotated by 90° clockwise.
+		{orientation: 6, want: []string{"da", "eb", "fc"}},
+		// Transversed.
+		{orientation: 7, want: []string{"fc", "eb", "da"}},
+		// Rotated by 90° counter-clockwise.
+		{orientation: 8, want: []string{"cf", "be", "ad"}},
+		{orientation: 9, want: []string{"abc", "def"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.orientation), func(t *testing.T) {
+			if got := rows(orient(src, tt.orientation)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("orient = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrientSubImage(t *testing.T) {
+	src := grid(
+		"xxxx",
+		"xabc",
+		"xdef",
+	).SubImage(image.Rect(1, 1, 4, 3))
+
+	got := orient(src, 6)
+	if b := got.Bounds(); b.Min != (image.Point{}) {
+		t.Errorf("bounds = %v, want them to start at the origin", b)
+	}
+	if want := []string{"da", "eb", "fc"}; !reflect.DeepEqual(rows(got), want) {
+		t.Errorf("orient = %q, want %q", rows(got), want)
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// identiconKeyPrefix starts the keys of identicons, which are drawn on request.
+const identiconKeyPrefix = "identicons/"
+
+// identiconSize is the side, in pixels, of an identicon without a size.
+const identiconSize = 256
+
+// identiconGrid is the number of cells on each side of an identicon.
+const identiconGrid = 5
+
+var identiconBackground = color.RGBA{0xf0, 0xf0, 0xf0, 0xff}
+
+// identiconKey returns the key of the size px identicon of the user; 0 is the default.
+func identiconKey(id uuid.UUID, size int) string {
+	if size == 0 {
+		return identiconKeyPrefix + id.String() + ".png"
+	}
+	return identiconKeyPrefix + id.String() + "_" + strconv.Itoa(size) + ".png"
+}
+
+// parseIdenticonKey returns the user and size of an identicon key.
+func parseIdenticonKey(key string) (uuid.UUID, int, bool) {
+	name, ok := strings.CutPrefix(key, identiconKeyPrefix)
+	if !ok {
+		return uuid.Nil, 0, false
+	}
+	name, ok = strings.CutSuffix(name, ".png")
+	if !ok {
+		return uuid.Nil, 0, false
+	}
+
+	size := identiconSize
+	if i := strings.LastIndexByte(name, '_'); i >= 0 {
+		s, err := strconv.Atoi(name[i+1:])
+		if err != nil || !isAvatarSize(s) {
+			return uuid.Nil, 0, false
+		}
+		name, size = name[:i], s
+	}
+
+	id, err := uuid.Parse(name)
+	if err != nil || identiconKey(id, 0) != identiconKeyPrefix+name+".png" {
+		return uuid.Nil, 0, false
+	}
+
+	return id, size, true
+}
+
+// identicon draws the identicon of the user as a PNG from a hash of the id.
+func identicon(id uuid.UUID, size int) ([]byte, error) {
+	sum := sha256.Sum256(id[:])
+
+	palette := color.Palette{identiconBackground, identiconColor(sum[0], sum[1])}
+	img := image.NewPaletted(image.Rect(0, 0, size, size), palette)
+
+	var cells [identiconGrid][identiconGrid]bool
+	half := (identiconGrid + 1) / 2
+	for y := 0; y < identiconGrid; y++ {
+		for x := 0; x < half; x++ {
+			on := sum[2+y*half+x]&1 == 1
+			cells[y][x] = on
+			cells[y][identiconGrid-1-x] = on
+		}
+	}
+
+	// The cells and the margin share the size, so a cell is size/(grid+1).
+	cell := float64(size) / float64(identiconGrid+1)
+	for py := 0; py < size; py++ {
+		y := int(float64(py)/cell - 0.5)
+		if float64(py) < cell/2 || y >= identiconGrid {
+			continue
+		}
+		for px := 0; px < size; px++ {
+			x := int(float64(px)/cell - 0.5)
+			if float64(px) < cell/2 || x >= identiconGrid {
+				continue
+			}
+			if cells[y][x] {
+				img.SetColorIndex(px, py, 1)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// identiconColor turns two hash bytes into a saturated, mid-light color.
+func identiconColor(h, l byte) color.RGBA {
+	hue := float64(h) / 256 * 6
+	light := 0.45 + float64(l)/255*0.15
+	chroma := (1 - math.Abs(2*light-1)) * 0.65
+	x := chroma * (1 - math.Abs(math.Mod(hue, 2)-1))
+	m := light - chroma/2
+
+	var r, g, b float64
+	switch int(hue) {
+	case 0:
+		r, g, b = chroma, x, 0
+	case 1:
+		r, g, b = x, chroma, 0
+	case 2:
+		r, g, b = 0, chroma, x
+	case 3:
+		r, g, b = 0, x, chroma
+	case 4:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+
+	return color.RGBA{uint8((r + m) * 255), uint8((g + m) * 255), uint8((b + m) * 255), 0xff}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	model "github.com/demkowo/users/internal/models"
+)
+
+// DefaultNicknamePattern allows letters and digits of any script plus '_', '.'
+// and '-', which may not start or end a nickname.
+const DefaultNicknamePattern = `^[\p{L}\p{N}](?:[\p{L}\p{N}_.-]*[\p{L}\p{N}])?$`
+
+// DefaultReservedNicknames are names that would let users pass themselves off
+// as the staff or the service itself.
+var DefaultReservedNicknames = []string{
+	"admin", "administrator", "api", "help", "mod", "moderator", "null", "official",
+	"owner", "root", "security", "staff", "support", "system", "undefined",
+}
+
+// NicknamePolicy is what a nickname must look like. It is checked when a user
+// is added, imported or renamed; nicknames already in use are left alone.
+type NicknamePolicy struct {
+	// MinLength and MaxLength bound the length of a nickname, in characters.
+	MinLength int
+	MaxLength int
+	// Pattern is a regular expression a nickname must match as a whole.
+	Pattern string
+	// Reserved nicknames can't be taken, not even as look-alikes such as
+	// "Admin_1" or "Аdmin" (with a Cyrillic A); see reservedKey.
+	Reserved []string
+}
+
+// nicknamePolicy is a NicknamePolicy ready to check nicknames with.
+type nicknamePolicy struct {
+	minLength int
+	maxLength int
+	pattern   *regexp.Regexp
+	reserved  map[string]bool
+}
+
+func newNicknamePolicy(p NicknamePolicy) (*nicknamePolicy, error) {
+	if p.MinLength <= 0 {
+		p.MinLength = 3
+	}
+	if p.MaxLength <= 0 {
+		p.MaxLength = 32
+	}
+	if p.MinLength > p.MaxLength {
+		return nil, fmt.Errorf("nickname min length %d is greater than max length %d", p.MinLength, p.MaxLength)
+	}
+	if p.Pattern == "" {
+		p.Pattern = DefaultNicknamePattern
+	}
+	if p.Reserved == nil {
+		p.Reserved = DefaultReservedNicknames
+	}
+
+	pattern, err := regexp.Compile(p.Pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nickname pattern: %w", err)
+	}
+
+	reserved := make(map[string]bool, len(p.Reserved))
+	for _, r := range p.Reserved {
+		if r = reservedKey(r); r != "" {
+			reserved[r] = true
+		}
+	}
+
+	return &nicknamePolicy{
+		minLength: p.MinLength,
+		maxLength: p.MaxLength,
+		pattern:   pattern,
+		reserved:  reserved,
+	}, nil
+}
+
+// validate returns an error for every rule nickname breaks, or nil.
+func (p *nicknamePolicy) validate(nickname string) []model.FieldError {
+	if nickname == "" {
+		return []model.FieldError{nicknameError("nickname is required")}
+	}
+
+	var errs []model.FieldError
+
+	if n := utf8.RuneCountInString(nickname); n < p.minLength || n > p.maxLength {
+		errs = append(errs, nicknameError(fmt.Sprintf("nickname must be %d to %d characters long", p.minLength, p.maxLength)))
+	}
+	if !p.pattern.MatchString(nickname) {
+		errs = append(errs, nicknameError(fmt.Sprintf("nickname must match %s", p.pattern)))
+	}
+	if p.reserved[reservedKey(nickname)] {
+		errs = append(errs, nicknameError("nickname is reserved"))
+	}
+
+	return errs
+}
+
+// reservedKey is the form nicknames are compared with the reserved ones in.
+func reservedKey(nickname string) string {
+	key := strings.Map(func(r rune) rune {
+		switch r {
+		case '_', '.', '-':
+			return -1
+		}
+		return r
+	}, strings.TrimRight(strings.TrimSpace(nickname), "0123456789_.-"))
+
+	return model.NicknameSkeleton(model.NicknameKey(key))
+}
+
+func nicknameError(msg string) model.FieldError {
+	return model.FieldError{Field: "nickname", Message: msg}
+}
+
+// fieldCauses turns field errors into the causes of a resp.Err.
+func fieldCauses(errs []model.FieldError) []interface{} {
+	causes := make([]interface{}, len(errs))
+	for i, e := range errs {
+		causes[i] = e
+	}
+	return causes
+}
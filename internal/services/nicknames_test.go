@@ -0,0 +1,108 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNicknamePolicyValidate(t *testing.T) {
+	custom := NicknamePolicy{
+		MinLength: 2,
+		MaxLength: 8,
+		Pattern:   `^[a-z]+$`,
+		Reserved:  []string{"Boss", "chief_"},
+	}
+
+	tests := []struct {
+		name     string
+		policy   NicknamePolicy
+		nickname string
+		want     []string
+	}{
+		{name: "valid", nickname: "john_doe"},
+		{name: "letters of any script", nickname: "Łukasz"},
+		{name: "Cyrillic", nickname: "Иван"},
+		{name: "empty", nickname: "", want: []string{"nickname is required"}},
+		{name: "too short", nickname: "jo", want: []string{"nickname must be 3 to 32 characters long"}},
+		{name: "too long", nickname: strings.Repeat("a", 33), want: []string{"nickname must be 3 to 32 characters long"}},
+		{name: "length in characters", nickname: "żółw"},
+		{name: "leading separator", nickname: "_john", want: []string{"nickname must match " + DefaultNicknamePattern}},
+		{name: "trailing separator", nickname: "john.", want: []string{"nickname must match " + DefaultNicknamePattern}},
+		{name: "space", nickname: "john doe", want: []string{"nickname must match " + DefaultNicknamePattern}},
+		{name: "reserved", nickname: "admin", want: []string{"nickname is reserved"}},
+		{name: "reserved in upper case", nickname: "ADMIN", want: []string{"nickname is reserved"}},
+		{name: "reserved in mixed case", nickname: "Admin", want: []string{"nickname is reserved"}},
+		{name: "reserved in fullwidth forms", nickname: "Ａｄｍｉｎ", want: []string{"nickname is reserved"}},
+		{name: "reserved with a Cyrillic a", nickname: "аdmin", want: []string{"nickname is reserved"}},
+		{name: "reserved with separators and digits", nickname: "Ad_min-01", want: []string{"nickname is reserved"}},
+		{name: "reserved with digits passing for letters", nickname: "r00t", want: []string{"nickname is reserved"}},
+		{name: "reserved name as a part", nickname: "admiral"},
+		{name: "reserved name with a prefix", nickname: "the_admin"},
+		{
+			name:     "every broken rule",
+			nickname: "ad",
+			policy:   NicknamePolicy{Pattern: `^[a-z]{3,}$`, Reserved: []string{"ad"}},
+			want: []string{
+				"nickname must be 3 to 32 characters long",
+				"nickname must match ^[a-z]{3,}$",
+				"nickname is reserved",
+			},
+		},
+		{name: "custom policy valid", policy: custom, nickname: "jo"},
+		{name: "custom policy too long", policy: custom, nickname: "johnathan", want: []string{"nickname must be 2 to 8 characters long"}},
+		{name: "custom policy pattern", policy: custom, nickname: "John", want: []string{"nickname must match ^[a-z]+$"}},
+		{name: "custom policy reserved", policy: custom, nickname: "boss", want: []string{"nickname is reserved"}},
+		{name: "custom policy reserved with separators", policy: custom, nickname: "chief", want: []string{"nickname is reserved"}},
+		{name: "custom policy replaces the defaults", policy: custom, nickname: "admin"},
+		{name: "no reserved nicknames", policy: NicknamePolicy{Reserved: []string{}}, nickname: "admin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := newNicknamePolicy(tt.policy)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, e := range p.validate(tt.nickname) {
+				if e.Field != "nickname" {
+					t.Errorf("error field = %q, want %q", e.Field, "nickname")
+				}
+				got = append(got, e.Message)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("validate(%q) = %q, want %q", tt.nickname, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewNicknamePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  NicknamePolicy
+		wantErr string
+	}{
+		{name: "defaults", policy: NicknamePolicy{}},
+		{name: "min length only", policy: NicknamePolicy{MinLength: 40}, wantErr: "nickname min length 40 is greater than max length 32"},
+		{name: "min greater than max", policy: NicknamePolicy{MinLength: 5, MaxLength: 4}, wantErr: "nickname min length 5 is greater than max length 4"},
+		{name: "invalid pattern", policy: NicknamePolicy{Pattern: `^[a-z`}, wantErr: "invalid nickname pattern"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newNicknamePolicy(tt.policy)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	model "github.com/demkowo/users/internal/models"
+	"github.com/demkowo/utils/resp"
+	log "github.com/sirupsen/logrus"
+)
+
+type OutboxRepo interface {
+	Relay(ctx context.Context, limit int32, publish func(model.OutboxEvent) error, retryAt func(attempts int32) time.Time) (int, *resp.Err)
+}
+
+// Publisher delivers outbox events to downstream systems. Delivery is at least
+// once: an event whose Publish fails, or whose success could not be recorded,
+// is published again later.
+type Publisher interface {
+	Publish(ctx context.Context, event model.OutboxEvent) error
+}
+
+type Outbox interface {
+	Run(ctx context.Context)
+}
+
+// OutboxConfig holds the tunables of the outbox relay.
+type OutboxConfig struct {
+	// PollInterval is how long the relay sleeps when there is nothing to send.
+	PollInterval time.Duration
+	// BatchSize is the number of events claimed at once.
+	BatchSize int32
+	// RetryBackoff is the delay before the first retry of a failed event; it
+	// doubles with every further attempt up to MaxBackoff.
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+}
+
+type outbox struct {
+	repo      OutboxRepo
+	publisher Publisher
+	conf      OutboxConfig
+}
+
+func NewOutbox(repo OutboxRepo, publisher Publisher, conf OutboxConfig) Outbox {
+	log.Trace()
+
+	if conf.PollInterval <= 0 {
+		conf.PollInterval = time.Second
+	}
+	if conf.BatchSize <= 0 {
+		conf.BatchSize = 100
+	}
+	if conf.RetryBackoff <= 0 {
+		conf.RetryBackoff = time.Second
+	}
+	if conf.MaxBackoff < conf.RetryBackoff {
+		conf.MaxBackoff = conf.RetryBackoff
+	}
+
+	return &outbox{
+		repo:      repo,
+		publisher: publisher,
+		conf:      conf,
+	}
+}
+
+// Run relays outbox events to the publisher until ctx is done. Full batches
+// are followed by the next one right away; otherwise it waits PollInterval.
+func (s *outbox) Run(ctx context.Context) {
+	log.Trace()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		wait := s.conf.PollInterval
+		n, err := s.repo.Relay(ctx, s.conf.BatchSize, func(event model.OutboxEvent) error {
+			return s.publisher.Publish(ctx, event)
+		}, s.retryAt)
+		if err != nil {
+			log.Errorf("Failed to relay outbox: %v", err)
+		} else if n == int(s.conf.BatchSize) {
+			wait = 0
+		}
+
+		timer.Reset(wait)
+	}
+}
+
+func (s *outbox) retryAt(attempts int32) time.Time {
+	return time.Now().Add(backoff(s.conf.RetryBackoff, s.conf.MaxBackoff, attempts))
+}
+
+// backoff returns the delay after attempts failures: base, doubling up to max.
+func backoff(base, max time.Duration, attempts int32) time.Duration {
+	delay := base
+	for i := int32(1); i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+
+	return delay
+}
+
+type publishers []Publisher
+
+// NewPublishers returns a Publisher that passes every event to each of ps in
+// turn. It fails at the first of them that fails, so the event is published
+// to all of them again.
+func NewPublishers(ps ...Publisher) Publisher {
+	return publishers(ps)
+}
+
+func (ps publishers) Publish(ctx context.Context, event model.OutboxEvent) error {
+	for _, p := range ps {
+		if err := p.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type logPublisher struct{}
+
+// NewLogPublisher returns a Publisher that only logs the events. It is the
+// default until a message broker is plugged in.
+func NewLogPublisher() Publisher {
+	return &logPublisher{}
+}
+
+func (p *logPublisher) Publish(ctx context.Context, event model.OutboxEvent) error {
+	log.WithFields(log.Fields{
+		"event_id": event.ID,
+		"type":     event.Type,
+		"user_id":  event.UserID,
+	}).Info("user event published")
+	return nil
+}
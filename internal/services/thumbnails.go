@@ -0,0 +1,69 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"path"
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// avatarSizes are the sides, in pixels, of the thumbnails of every avatar.
+var avatarSizes = []int{32, 64, 128, 256}
+
+// avatarJPEGQuality is the quality JPEG avatars are re-encoded with.
+const avatarJPEGQuality = 85
+
+// avatarVariantKey returns the key of the size px thumbnail; only JPEGs stay JPEGs.
+func avatarVariantKey(key string, size int) string {
+	ext := path.Ext(key)
+	variantExt := ".png"
+	if ext == ".jpg" {
+		variantExt = ".jpg"
+	}
+
+	return fmt.Sprintf("%s_%d%s", strings.TrimSuffix(key, ext), size, variantExt)
+}
+
+func isAvatarSize(size int) bool {
+	for _, s := range avatarSizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+// thumbnail scales the largest centered square of src to size x size pixels.
+func thumbnail(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(b.Min.X+(b.Dx()-side)/2, b.Min.Y+(b.Dy()-side)/2))
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, draw.Src, nil)
+	return dst
+}
+
+// encodeAvatar encodes img in the format of the avatar or thumbnail key.
+func encodeAvatar(key string, img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var err error
+	if path.Ext(key) == ".jpg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: avatarJPEGQuality})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+// stripes is a w x h image split into three equal stripes, red, green and
+// blue, side by side when it is wide and one above another when it is tall.
+func stripes(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	colors := []color.RGBA{{R: 255, A: 255}, {G: 255, A: 255}, {B: 255, A: 255}}
+	for i, c := range colors {
+		r := image.Rect(i*w/3, 0, (i+1)*w/3, h)
+		if h > w {
+			r = image.Rect(0, i*h/3, w, (i+1)*h/3)
+		}
+		draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+	}
+	return img
+}
+
+func isGreen(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	return g > 0xf000 && r < 0x1000 && b < 0x1000
+}
+
+func TestThumbnail(t *testing.T) {
+	tests := []struct {
+		name string
+		src  image.Image
+		size int
+	}{
+		{name: "wide", src: stripes(300, 100), size: 32},
+		{name: "tall", src: stripes(100, 300), size: 32},
+		{name: "upscaled", src: stripes(30, 10), size: 64},
+		{name: "not at the origin", src: stripes(600, 200).SubImage(image.Rect(150, 50, 450, 150)), size: 16},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := thumbnail(tt.src, tt.size)
+
+			if b := got.Bounds(); b != image.Rect(0, 0, tt.size, tt.size) {
+				t.Fatalf("bounds = %v, want %dx%d", b, tt.size, tt.size)
+			}
+			// Only the green middle stripe is left of the source once it is
+			// cropped to a centered square.
+			for _, p := range []image.Point{{0, 0}, {tt.size - 1, 0}, {tt.size / 2, tt.size / 2}, {0, tt.size - 1}, {tt.size - 1, tt.size - 1}} {
+				if c := got.At(p.X, p.Y); !isGreen(c) {
+					t.Errorf("pixel %v = %v, want green", p, c)
+				}
+			}
+		})
+	}
+}
+
+func TestAvatarVariantKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		size int
+		want string
+	}{
+		{key: "avatars/0f/abc.jpg", size: 64, want: "avatars/0f/abc_64.jpg"},
+		{key: "avatars/0f/abc.png", size: 32, want: "avatars/0f/abc_32.png"},
+		{key: "avatars/0f/abc.gif", size: 128, want: "avatars/0f/abc_128.png"},
+		{key: "avatars/0f/abc.webp", size: 256, want: "avatars/0f/abc_256.png"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := avatarVariantKey(tt.key, tt.size); got != tt.want {
+				t.Errorf("avatarVariantKey(%q, %d) = %q, want %q", tt.key, tt.size, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAvatarSize(t *testing.T) {
+	for _, size := range avatarSizes {
+		if !isAvatarSize(size) {
+			t.Errorf("isAvatarSize(%d) = false, want true", size)
+		}
+	}
+	for _, size := range []int{0, -32, 48, 512} {
+		if isAvatarSize(size) {
+			t.Errorf("isAvatarSize(%d) = true, want false", size)
+		}
+	}
+}
+
+func TestEncodeAvatar(t *testing.T) {
+	tests := []struct {
+		key    string
+		format string
+	}{
+		{key: "avatars/0f/abc.jpg", format: "jpeg"},
+		{key: "avatars/0f/abc_64.jpg", format: "jpeg"},
+		{key: "avatars/0f/abc.png", format: "png"},
+		{key: "avatars/0f/abc_64.png", format: "png"},
+	}
+
+	src := thumbnail(stripes(90, 30), 16)
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			data, err := encodeAvatar(tt.key, src)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			img, format, err := image.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("decoding the avatar: %v", err)
+			}
+			if format != tt.format {
+				t.Errorf("format = %q, want %q", format, tt.format)
+			}
+			if b := img.Bounds(); b != src.Bounds() {
+				t.Errorf("bounds = %v, want %v", b, src.Bounds())
+			}
+			if c := img.At(8, 8); !isGreen(c) {
+				t.Errorf("pixel (8, 8) = %v, want green", c)
+			}
+		})
+	}
+}
@@ -1,9 +1,21 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"image"
+	"io"
+	"math"
+	"mime"
 	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
 
 	model "github.com/demkowo/users/internal/models"
 	"github.com/demkowo/utils/resp"
@@ -13,50 +25,189 @@ import (
 
 type UsersRepo interface {
 	Add(ctx context.Context, user model.User) (model.User, *resp.Err)
+	AddBatch(ctx context.Context, users []model.User) ([]model.User, []*resp.Err, *resp.Err)
+	Export(ctx context.Context, filter model.UserFilter, fn func(model.User) error) *resp.Err
 	Find(ctx context.Context) ([]model.User, *resp.Err)
+	FindInCities(ctx context.Context, cityIDs []string, limit, offset int32) ([]model.User, int64, *resp.Err)
 	List(ctx context.Context, limit, offset int32) ([]model.User, *resp.Err)
-	GetImgByNickname(ctx context.Context, nickname string) (string, *resp.Err)
+	ListAfter(ctx context.Context, created time.Time, id uuid.UUID, limit int32) ([]model.User, *resp.Err)
+	Search(ctx context.Context, filter model.UserFilter, limit, offset int32) ([]model.User, int64, *resp.Err)
 	GetByID(ctx context.Context, id uuid.UUID) (model.User, *resp.Err)
+	GetByNickname(ctx context.Context, nickname string) (model.User, *resp.Err)
+	GetByPreviousNickname(ctx context.Context, nickname string, since time.Time) (model.User, *resp.Err)
+	NicknameConflict(ctx context.Context, nickname string, userID uuid.UUID, since time.Time) (string, *resp.Err)
+	ChangeNickname(ctx context.Context, userID uuid.UUID, nickname string, since time.Time) (model.User, *resp.Err)
 	Update(ctx context.Context, user model.User) (model.User, *resp.Err)
+	Patch(ctx context.Context, user model.User, fields []string) (model.User, *resp.Err)
 	UpdateImg(ctx context.Context, userID uuid.UUID, img string) (model.User, *resp.Err)
-	Delete(ctx context.Context, userID uuid.UUID) (model.User, *resp.Err)
+	Delete(ctx context.Context, userID uuid.UUID) (*model.User, *resp.Err)
+	Restore(ctx context.Context, userID uuid.UUID, since time.Time) (model.User, *resp.Err)
+	Purge(ctx context.Context, userID uuid.UUID) (model.User, *resp.Err)
+	ListAuditEntries(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]model.AuditEntry, int64, *resp.Err)
+	Follow(ctx context.Context, followerID, followeeID uuid.UUID) (model.Follow, *resp.Err)
+	Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) *resp.Err
+	ListFollowers(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]model.FollowUser, int64, *resp.Err)
+	ListFollowing(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]model.FollowUser, int64, *resp.Err)
+}
+
+// BlobStore keeps the uploaded avatars under slash-separated keys.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) *resp.Err
+	Get(ctx context.Context, key string) (io.ReadCloser, *resp.Err)
+	Delete(ctx context.Context, key string) *resp.Err
+}
+
+// avatarKeyPrefix starts the blob keys of uploaded avatars.
+const avatarKeyPrefix = "avatars/"
+
+// avatarContentTypes are the accepted avatar formats.
+var avatarContentTypes = map[string]bool{
+	"image/gif":  true,
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
 }
 
 type Users interface {
 	Add(ctx context.Context, user *model.User) *resp.Err
+	ChangeNickname(ctx context.Context, id uuid.UUID, nickname string) (*model.User, *resp.Err)
 	Delete(ctx context.Context, id string) *resp.Err
+	Export(ctx context.Context, filter model.UserFilter, fn func(model.User) error) *resp.Err
 	Find(ctx context.Context) ([]model.User, *resp.Err)
-	GetAvatarByNickname(ctx context.Context, nickname string) (string, *resp.Err)
+	FindNearby(ctx context.Context, lat, lon, radiusKm float64, limit, offset int32) ([]model.NearbyUser, int64, *resp.Err)
+	Follow(ctx context.Context, followerID, followeeID uuid.UUID) (*model.Follow, *resp.Err)
+	GetAvatarByNickname(ctx context.Context, nickname string) (*model.Avatar, *resp.Err)
 	GetByID(ctx context.Context, id uuid.UUID) (*model.User, *resp.Err)
+	GetByNickname(ctx context.Context, nickname string) (*model.User, *resp.Err)
+	Import(ctx context.Context, rows []model.User) (*model.ImportReport, *resp.Err)
 	List(ctx context.Context, limit, offset int32) ([]model.User, *resp.Err)
+	ListAuditEntries(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]model.AuditEntry, int64, *resp.Err)
+	ListFollowers(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]model.FollowUser, int64, *resp.Err)
+	ListFollowing(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]model.FollowUser, int64, *resp.Err)
+	ListPage(ctx context.Context, limit int32, pageToken string) ([]model.User, string, *resp.Err)
+	OpenAvatar(ctx context.Context, key string) (io.ReadCloser, string, *resp.Err)
+	OpenAvatarByNickname(ctx context.Context, nickname string, size int) (io.ReadCloser, string, *resp.Err)
+	Patch(ctx context.Context, user *model.User, fields []string) *resp.Err
+	Purge(ctx context.Context, id uuid.UUID) *resp.Err
+	Restore(ctx context.Context, id uuid.UUID) (*model.User, *resp.Err)
+	Search(ctx context.Context, filter model.UserFilter, limit, offset int32) ([]model.User, int64, *resp.Err)
+	Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) *resp.Err
 	Update(ctx context.Context, user *model.User) *resp.Err
 	UpdateImg(ctx context.Context, id uuid.UUID, path string) *resp.Err
+	UploadAvatar(ctx context.Context, id uuid.UUID, contentType string, r io.Reader) (*model.User, *resp.Err)
+	Watch(ctx context.Context, afterID uint64) (<-chan model.UserEvent, *resp.Err)
+}
+
+// UsersConfig holds the tunables of the users service.
+type UsersConfig struct {
+	// NicknameGracePeriod is how long a previous nickname keeps resolving
+	// to its user (and stays reserved for them) after a rename.
+	NicknameGracePeriod time.Duration
+	// ImportBatchSize is the number of rows saved per transaction by Import.
+	ImportBatchSize int
+	// ImportMaxRows caps the number of rows accepted by a single Import.
+	ImportMaxRows int
+	// AvatarMaxSize is the largest avatar upload accepted, in bytes.
+	AvatarMaxSize int64
+	// AvatarMaxDimension is the largest width and height of an avatar, in
+	// pixels.
+	AvatarMaxDimension int
+	// AvatarBaseURL is prepended to the blob keys of uploaded avatars to make
+	// their download URLs.
+	AvatarBaseURL string
+	// NearbyMaxRadiusKm is the largest radius, in kilometers, FindNearby
+	// searches.
+	NearbyMaxRadiusKm float64
+	// Nickname is the policy new nicknames must follow.
+	Nickname NicknamePolicy
 }
 
 type users struct {
-	repo UsersRepo
+	repo      UsersRepo
+	blobs     BlobStore
+	events    Events
+	conf      UsersConfig
+	nicknames *nicknamePolicy
 }
 
-func NewUsers(repo UsersRepo) Users {
+func NewUsers(repo UsersRepo, blobs BlobStore, events Events, conf UsersConfig) Users {
 	log.Trace()
+
+	if conf.AvatarMaxSize <= 0 {
+		conf.AvatarMaxSize = 5 << 20
+	}
+	if conf.AvatarMaxDimension <= 0 {
+		conf.AvatarMaxDimension = 4096
+	}
+	if conf.AvatarBaseURL == "" {
+		conf.AvatarBaseURL = "/api/v1/avatars/"
+	}
+	if conf.NearbyMaxRadiusKm <= 0 {
+		conf.NearbyMaxRadiusKm = 500
+	}
+
+	nicknames, err := newNicknamePolicy(conf.Nickname)
+	if err != nil {
+		log.Panicf("invalid nickname policy: %v", err)
+	}
+
 	return &users{
-		repo: repo,
+		repo:      repo,
+		blobs:     blobs,
+		events:    events,
+		conf:      conf,
+		nicknames: nicknames,
 	}
 }
 
 func (s *users) Add(ctx context.Context, user *model.User) *resp.Err {
 	log.Trace()
 
-	fmt.Println(user.Clubs)
+	user.Nickname = strings.TrimSpace(user.Nickname)
+	errs := s.nicknames.validate(user.Nickname)
+	country, countryErrs := normalizeCountry(strings.TrimSpace(user.Country))
+	if errs = append(errs, countryErrs...); errs != nil {
+		return resp.Error(http.StatusBadRequest, "failed to add user", fieldCauses(errs))
+	}
+	user.Country = country
 
-	_, err := s.repo.Add(ctx, *user)
+	conflict, err := s.repo.NicknameConflict(ctx, user.Nickname, user.ID, s.nicknameCutoff())
 	if err != nil {
 		return err
 	}
+	if conflict != "" {
+		return resp.Error(http.StatusConflict, "failed to add user", []interface{}{nicknameError(conflict)})
+	}
 
+	u, err := s.repo.Add(ctx, *user)
+	if err != nil {
+		return err
+	}
+
+	s.publish(model.UserEventCreated, u)
+	localizeUser(ctx, &u)
+	*user = u
 	return nil
 }
 
+func (s *users) ChangeNickname(ctx context.Context, id uuid.UUID, nickname string) (*model.User, *resp.Err) {
+	log.Trace()
+
+	nickname = strings.TrimSpace(nickname)
+	if errs := s.nicknames.validate(nickname); errs != nil {
+		return nil, resp.Error(http.StatusBadRequest, "failed to change nickname", fieldCauses(errs))
+	}
+
+	u, err := s.repo.ChangeNickname(ctx, id, nickname, s.nicknameCutoff())
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(model.UserEventUpdated, u)
+	localizeUser(ctx, &u)
+	return &u, nil
+}
+
 func (s *users) Delete(ctx context.Context, id string) *resp.Err {
 	log.Trace()
 
@@ -68,14 +219,30 @@ func (s *users) Delete(ctx context.Context, id string) *resp.Err {
 		return resp.Error(http.StatusBadRequest, "failed to delete user", []interface{}{"invalid uuid format", err.Error()})
 	}
 
-	_, e := s.repo.Delete(ctx, uid)
+	u, e := s.repo.Delete(ctx, uid)
 	if e != nil {
 		return e
 	}
 
+	// an already deleted user has been announced as deleted
+	if u != nil {
+		s.publish(model.UserEventDeleted, *u)
+	}
 	return nil
 }
 
+// Export streams every user matching filter to fn, newest first, without
+// loading them all into memory. It stops at the first error returned by fn.
+func (s *users) Export(ctx context.Context, filter model.UserFilter, fn func(model.User) error) *resp.Err {
+	log.Trace()
+
+	if err := normalizeUserFilter(&filter); err != nil {
+		return resp.Error(http.StatusBadRequest, "failed to export users", []interface{}{err.Error()})
+	}
+
+	return s.repo.Export(ctx, filter, fn)
+}
+
 func (s *users) Find(ctx context.Context) ([]model.User, *resp.Err) {
 	log.Trace()
 
@@ -89,16 +256,105 @@ func (s *users) Find(ctx context.Context) ([]model.User, *resp.Err) {
 		u := us[i]
 		result[i] = u
 	}
+	localizeUsers(ctx, result)
 	return result, nil
 }
 
-func (s *users) GetAvatarByNickname(ctx context.Context, nickname string) (string, *resp.Err) {
+// FindNearby returns a page of the active users whose city is within radiusKm of the point,
+// nearest first, and how many there are in all.
+func (s *users) FindNearby(ctx context.Context, lat, lon, radiusKm float64, limit, offset int32) ([]model.NearbyUser, int64, *resp.Err) {
 	log.Trace()
 
-	avatar, err := s.repo.GetImgByNickname(ctx, nickname)
+	var errs []model.FieldError
+	if !(lat >= -90 && lat <= 90) {
+		errs = append(errs, model.FieldError{Field: "lat", Message: "lat must be between -90 and 90"})
+	}
+	if !(lon >= -180 && lon <= 180) {
+		errs = append(errs, model.FieldError{Field: "lon", Message: "lon must be between -180 and 180"})
+	}
+	if !(radiusKm > 0 && radiusKm <= s.conf.NearbyMaxRadiusKm) {
+		errs = append(errs, model.FieldError{Field: "radius_km", Message: fmt.Sprintf("radius_km must be greater than 0 and at most %g", s.conf.NearbyMaxRadiusKm)})
+	}
+	if errs != nil {
+		return nil, 0, resp.Error(http.StatusBadRequest, "failed to find nearby users", fieldCauses(errs))
+	}
+
+	cities := model.CitiesNear(lat, lon, radiusKm)
+	if len(cities) == 0 {
+		return []model.NearbyUser{}, 0, nil
+	}
+
+	ids := make([]string, len(cities))
+	distances := make(map[string]float64, len(cities))
+	for i, c := range cities {
+		ids[i] = c.ID
+		distances[c.ID] = math.Round(c.DistanceKm*10) / 10
+	}
+
+	us, total, err := s.repo.FindInCities(ctx, ids, limit, offset)
 	if err != nil {
-		return "", err
+		return nil, 0, err
 	}
+
+	localizeUsers(ctx, us)
+	nearby := make([]model.NearbyUser, len(us))
+	for i, u := range us {
+		nearby[i] = model.NearbyUser{User: u, DistanceKm: distances[u.CityID]}
+	}
+
+	return nearby, total, nil
+}
+
+// Follow makes the follower follow the followee. Following a user again
+// returns the existing follow.
+func (s *users) Follow(ctx context.Context, followerID, followeeID uuid.UUID) (*model.Follow, *resp.Err) {
+	log.Trace()
+
+	if followerID == followeeID {
+		return nil, resp.Error(http.StatusBadRequest, "failed to follow user", fieldCauses([]model.FieldError{{
+			Field:   "followee_id",
+			Message: "users can't follow themselves",
+		}}))
+	}
+
+	f, err := s.repo.Follow(ctx, followerID, followeeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &f, nil
+}
+
+// GetAvatarByNickname returns the avatar of the user using nickname, or who gave it up
+// within the grace period.
+func (s *users) GetAvatarByNickname(ctx context.Context, nickname string) (*model.Avatar, *resp.Err) {
+	log.Trace()
+
+	u, err := s.userByNickname(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+
+	avatar := &model.Avatar{
+		Img: u.Img,
+		URL: u.Img,
+	}
+	switch {
+	case u.Img == "":
+		avatar.URL = s.conf.AvatarBaseURL + identiconKey(u.ID, 0)
+		avatar.Generated = true
+		avatar.Variants = make(map[int]string, len(avatarSizes))
+		for _, size := range avatarSizes {
+			avatar.Variants[size] = s.conf.AvatarBaseURL + identiconKey(u.ID, size)
+		}
+	case isAvatarKey(u.Img):
+		avatar.URL = s.conf.AvatarBaseURL + u.Img
+		avatar.Variants = make(map[int]string, len(avatarSizes))
+		for _, size := range avatarSizes {
+			avatar.Variants[size] = s.conf.AvatarBaseURL + avatarVariantKey(u.Img, size)
+		}
+	}
+
 	return avatar, nil
 }
 
@@ -110,9 +366,139 @@ func (s *users) GetByID(ctx context.Context, id uuid.UUID) (*model.User, *resp.E
 		return nil, err
 	}
 
+	localizeUser(ctx, &u)
 	return &u, nil
 }
 
+// GetByNickname returns the user currently using nickname or, failing that,
+// the user who gave it up within the nickname grace period.
+func (s *users) GetByNickname(ctx context.Context, nickname string) (*model.User, *resp.Err) {
+	log.Trace()
+
+	u, err := s.userByNickname(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+
+	localizeUser(ctx, &u)
+	return &u, nil
+}
+
+func (s *users) userByNickname(ctx context.Context, nickname string) (model.User, *resp.Err) {
+	u, err := s.repo.GetByNickname(ctx, nickname)
+	if err != nil {
+		if err.Code != http.StatusNotFound {
+			return model.User{}, err
+		}
+		return s.repo.GetByPreviousNickname(ctx, nickname, s.nicknameCutoff())
+	}
+	return u, nil
+}
+
+// Import adds the users in batches, reporting invalid or taken rows without aborting.
+func (s *users) Import(ctx context.Context, rows []model.User) (*model.ImportReport, *resp.Err) {
+	log.Trace()
+
+	if len(rows) == 0 {
+		return nil, resp.Error(http.StatusBadRequest, "failed to import users", []interface{}{"no rows to import"})
+	}
+	if s.conf.ImportMaxRows > 0 && len(rows) > s.conf.ImportMaxRows {
+		return nil, resp.Error(http.StatusBadRequest, "failed to import users", []interface{}{fmt.Sprintf("at most %d rows can be imported at once", s.conf.ImportMaxRows)})
+	}
+
+	batchSize := s.conf.ImportBatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	report := &model.ImportReport{
+		Total:   len(rows),
+		Results: make([]model.ImportResult, len(rows)),
+	}
+	seen := make(map[string]int, len(rows))
+	batch := make([]model.User, 0, batchSize)
+	batchRows := make([]int, 0, batchSize)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+
+		saved, rowErrs, err := s.repo.AddBatch(ctx, batch)
+		for i, row := range batchRows {
+			switch {
+			case err != nil:
+				report.Results[row].Error = err.Error
+			case rowErrs[i] != nil && rowErrs[i].Code == http.StatusConflict:
+				report.Results[row].Error = "nickname is already taken"
+			case rowErrs[i] != nil:
+				report.Results[row].Error = rowErrs[i].Error
+			default:
+				report.Results[row].UserID = saved[i].ID
+				s.publish(model.UserEventCreated, saved[i])
+			}
+		}
+
+		batch = batch[:0]
+		batchRows = batchRows[:0]
+	}
+
+	for i, user := range rows {
+		user.Nickname = strings.TrimSpace(user.Nickname)
+		user.City = strings.TrimSpace(user.City)
+		if user.ID == uuid.Nil {
+			user.ID = uuid.New()
+		}
+
+		report.Results[i] = model.ImportResult{Row: i + 1, Nickname: user.Nickname}
+
+		if errs := s.nicknames.validate(user.Nickname); errs != nil {
+			report.Results[i].Error = errs[0].Message
+			continue
+		}
+		country, errs := normalizeCountry(strings.TrimSpace(user.Country))
+		if errs != nil {
+			report.Results[i].Error = errs[0].Message
+			continue
+		}
+		user.Country = country
+		// Rows are told apart by skeleton, which also catches nicknames that
+		// only differ in case or in look-alike characters.
+		skeleton := model.NicknameSkeleton(user.Nickname)
+		if prev, ok := seen[skeleton]; ok {
+			report.Results[i].Error = fmt.Sprintf("nickname duplicates row %d", prev)
+			continue
+		}
+		seen[skeleton] = i + 1
+
+		conflict, err := s.repo.NicknameConflict(ctx, user.Nickname, user.ID, s.nicknameCutoff())
+		if err != nil {
+			return nil, err
+		}
+		if conflict != "" {
+			report.Results[i].Error = conflict
+			continue
+		}
+
+		batch = append(batch, user)
+		batchRows = append(batchRows, i)
+		if len(batch) == batchSize {
+			flush()
+		}
+	}
+	flush()
+
+	for _, r := range report.Results {
+		if r.Error != "" {
+			report.Failed++
+		} else {
+			report.Imported++
+		}
+	}
+
+	return report, nil
+}
+
 func (s *users) List(ctx context.Context, limit, offset int32) ([]model.User, *resp.Err) {
 	log.Trace()
 
@@ -126,27 +512,473 @@ func (s *users) List(ctx context.Context, limit, offset int32) ([]model.User, *r
 		u := us[i]
 		result[i] = u
 	}
+	localizeUsers(ctx, result)
 	return result, nil
 }
 
+// ListAuditEntries returns who changed the user and how, newest first. The
+// trail outlives the user, so it can still be listed after a purge.
+func (s *users) ListAuditEntries(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]model.AuditEntry, int64, *resp.Err) {
+	log.Trace()
+
+	return s.repo.ListAuditEntries(ctx, userID, limit, offset)
+}
+
+// ListFollowers returns the active users following the user, most recent
+// follow first.
+func (s *users) ListFollowers(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]model.FollowUser, int64, *resp.Err) {
+	log.Trace()
+
+	us, total, err := s.repo.ListFollowers(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	localizeFollowUsers(ctx, us)
+	return us, total, nil
+}
+
+// ListFollowing returns the active users the user follows, most recent follow
+// first.
+func (s *users) ListFollowing(ctx context.Context, userID uuid.UUID, limit, offset int32) ([]model.FollowUser, int64, *resp.Err) {
+	log.Trace()
+
+	us, total, err := s.repo.ListFollowing(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	localizeFollowUsers(ctx, us)
+	return us, total, nil
+}
+
+// ListPage returns up to limit users after pageToken and the token of the next page, empty
+// after the last one.
+func (s *users) ListPage(ctx context.Context, limit int32, pageToken string) ([]model.User, string, *resp.Err) {
+	log.Trace()
+
+	var (
+		us  []model.User
+		err *resp.Err
+	)
+
+	if pageToken == "" {
+		us, err = s.repo.List(ctx, limit, 0)
+	} else {
+		created, id, e := decodePageToken(pageToken)
+		if e != nil {
+			return nil, "", resp.Error(http.StatusBadRequest, "failed to list users", []interface{}{"invalid page token"})
+		}
+		us, err = s.repo.ListAfter(ctx, created, id, limit)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	localizeUsers(ctx, us)
+
+	next := ""
+	if len(us) > 0 && int32(len(us)) == limit {
+		last := us[len(us)-1]
+		next = encodePageToken(last.Created, last.ID)
+	}
+
+	return us, next, nil
+}
+
+// OpenAvatar returns the uploaded avatar stored under key, or the identicon
+// named by key, and its content type. The caller must close it.
+func (s *users) OpenAvatar(ctx context.Context, key string) (io.ReadCloser, string, *resp.Err) {
+	log.Trace()
+
+	if id, size, ok := parseIdenticonKey(key); ok {
+		return openIdenticon(id, size)
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if !isAvatarKey(key) || contentType == "" {
+		return nil, "", resp.Error(http.StatusNotFound, "failed to get avatar", []interface{}{"avatar not found"})
+	}
+
+	rc, err := s.blobs.Get(ctx, key)
+	if err != nil {
+		if err.Code == http.StatusNotFound || err.Code == http.StatusBadRequest {
+			return nil, "", resp.Error(http.StatusNotFound, "failed to get avatar", []interface{}{"avatar not found"})
+		}
+		return nil, "", err
+	}
+
+	return rc, contentType, nil
+}
+
+// OpenAvatarByNickname opens the size px avatar of the user using nickname and returns its
+// content type. The caller must close it.
+func (s *users) OpenAvatarByNickname(ctx context.Context, nickname string, size int) (io.ReadCloser, string, *resp.Err) {
+	log.Trace()
+
+	if !isAvatarSize(size) {
+		return nil, "", resp.Error(http.StatusBadRequest, "failed to get avatar", []interface{}{fmt.Sprintf("size must be one of %v", avatarSizes)})
+	}
+
+	u, err := s.userByNickname(ctx, nickname)
+	if err != nil {
+		return nil, "", err
+	}
+	if u.Img == "" {
+		return openIdenticon(u.ID, size)
+	}
+	if !isAvatarKey(u.Img) {
+		return nil, "", resp.Error(http.StatusNotFound, "failed to get avatar", []interface{}{"avatar has no thumbnails"})
+	}
+
+	return s.OpenAvatar(ctx, avatarVariantKey(u.Img, size))
+}
+
+func openIdenticon(id uuid.UUID, size int) (io.ReadCloser, string, *resp.Err) {
+	data, e := identicon(id, size)
+	if e != nil {
+		return nil, "", resp.Error(http.StatusInternalServerError, "failed to get avatar", []interface{}{e.Error()})
+	}
+	return io.NopCloser(bytes.NewReader(data)), "image/png", nil
+}
+
+// Patch changes only the listed fields of the user; see model.UserField* for
+// the accepted names. Like Update, a non-zero user.Version is the expected one.
+func (s *users) Patch(ctx context.Context, user *model.User, fields []string) *resp.Err {
+	log.Trace()
+
+	if len(fields) == 0 {
+		return resp.Error(http.StatusBadRequest, "failed to update user", []interface{}{"no fields to update"})
+	}
+
+	var invalid []interface{}
+	for _, f := range fields {
+		switch f {
+		case model.UserFieldCountry, model.UserFieldCity, model.UserFieldClubs:
+		default:
+			invalid = append(invalid, fmt.Sprintf("field %q can't be updated", f))
+		}
+	}
+	if len(invalid) > 0 {
+		return resp.Error(http.StatusBadRequest, "failed to update user", invalid)
+	}
+
+	if slices.Contains(fields, model.UserFieldCountry) {
+		country, errs := normalizeCountry(strings.TrimSpace(user.Country))
+		if errs != nil {
+			return resp.Error(http.StatusBadRequest, "failed to update user", fieldCauses(errs))
+		}
+		user.Country = country
+	}
+
+	u, err := s.repo.Patch(ctx, *user, fields)
+	if err != nil {
+		return err
+	}
+
+	s.publish(model.UserEventUpdated, u)
+	localizeUser(ctx, &u)
+	*user = u
+	return nil
+}
+
+func (s *users) Purge(ctx context.Context, id uuid.UUID) *resp.Err {
+	log.Trace()
+
+	u, err := s.repo.Purge(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// a soft-deleted user has already been announced as deleted
+	if !u.Deleted {
+		s.publish(model.UserEventDeleted, u)
+	}
+	return nil
+}
+
+func (s *users) Restore(ctx context.Context, id uuid.UUID) (*model.User, *resp.Err) {
+	log.Trace()
+
+	u, err := s.repo.Restore(ctx, id, s.nicknameCutoff())
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(model.UserEventUpdated, u)
+	localizeUser(ctx, &u)
+	return &u, nil
+}
+
+func (s *users) Search(ctx context.Context, filter model.UserFilter, limit, offset int32) ([]model.User, int64, *resp.Err) {
+	log.Trace()
+
+	if err := normalizeUserFilter(&filter); err != nil {
+		return nil, 0, resp.Error(http.StatusBadRequest, "failed to search users", []interface{}{err.Error()})
+	}
+
+	us, total, err := s.repo.Search(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	localizeUsers(ctx, us)
+	return us, total, nil
+}
+
+// Unfollow makes the follower stop following the followee. Unfollowing a user
+// who isn't followed is not an error.
+func (s *users) Unfollow(ctx context.Context, followerID, followeeID uuid.UUID) *resp.Err {
+	log.Trace()
+
+	if followerID == followeeID {
+		return resp.Error(http.StatusBadRequest, "failed to unfollow user", fieldCauses([]model.FieldError{{
+			Field:   "followee_id",
+			Message: "users can't follow themselves",
+		}}))
+	}
+
+	return s.repo.Unfollow(ctx, followerID, followeeID)
+}
+
+// Update replaces the user's details. A non-zero user.Version makes the update
+// conditional on the stored version, failing with 409 Conflict when it moved on.
 func (s *users) Update(ctx context.Context, user *model.User) *resp.Err {
 	log.Trace()
 
-	_, err := s.repo.Update(ctx, *user)
+	country, errs := normalizeCountry(strings.TrimSpace(user.Country))
+	if errs != nil {
+		return resp.Error(http.StatusBadRequest, "failed to update user", fieldCauses(errs))
+	}
+	user.Country = country
+
+	u, err := s.repo.Update(ctx, *user)
 	if err != nil {
 		return err
 	}
 
+	s.publish(model.UserEventUpdated, u)
+	localizeUser(ctx, &u)
+	*user = u
 	return nil
 }
 
+// UpdateImg points the user's img at path, which must be an http(s) URL or one
+// of the user's uploaded avatars; an empty path removes the image.
 func (s *users) UpdateImg(ctx context.Context, id uuid.UUID, path string) *resp.Err {
 	log.Trace()
 
-	_, err := s.repo.UpdateImg(ctx, id, path)
+	if !isValidImg(id, path) {
+		return resp.Error(http.StatusBadRequest, "failed to update image", []interface{}{model.FieldError{Field: "img", Message: "img must be an http(s) URL or an avatar uploaded for the user"}})
+	}
+
+	u, err := s.repo.UpdateImg(ctx, id, path)
 	if err != nil {
 		return err
 	}
 
+	s.publish(model.UserEventImageChanged, u)
 	return nil
 }
+
+// UploadAvatar re-encodes the image read from r, without its metadata, and stores it with
+// its thumbnails as the user's avatar.
+func (s *users) UploadAvatar(ctx context.Context, id uuid.UUID, contentType string, r io.Reader) (*model.User, *resp.Err) {
+	log.Trace()
+
+	// Clients that don't know the type may send none or a generic one.
+	if contentType != "" {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		if !avatarContentTypes[mediaType] && mediaType != "application/octet-stream" {
+			return nil, resp.Error(http.StatusUnsupportedMediaType, "failed to upload avatar", []interface{}{model.FieldError{Field: "content_type", Message: "avatar must be a GIF, JPEG, PNG or WebP image"}})
+		}
+	}
+
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, e := io.ReadAll(io.LimitReader(r, s.conf.AvatarMaxSize+1))
+	if e != nil {
+		return nil, resp.Error(http.StatusBadRequest, "failed to upload avatar", []interface{}{e.Error()})
+	}
+	if len(data) == 0 {
+		return nil, resp.Error(http.StatusBadRequest, "failed to upload avatar", []interface{}{model.FieldError{Field: "avatar", Message: "avatar is empty"}})
+	}
+	if int64(len(data)) > s.conf.AvatarMaxSize {
+		return nil, resp.Error(http.StatusRequestEntityTooLarge, "failed to upload avatar", []interface{}{model.FieldError{Field: "avatar", Message: fmt.Sprintf("avatar must not be larger than %d bytes", s.conf.AvatarMaxSize)}})
+	}
+
+	sniffed := http.DetectContentType(data)
+	if !avatarContentTypes[sniffed] {
+		return nil, resp.Error(http.StatusUnsupportedMediaType, "failed to upload avatar", []interface{}{model.FieldError{Field: "avatar", Message: "avatar content is not a GIF, JPEG, PNG or WebP image"}})
+	}
+
+	// The header is checked before decoding so that huge images are never
+	// allocated.
+	cfg, _, e := image.DecodeConfig(bytes.NewReader(data))
+	if e != nil {
+		return nil, resp.Error(http.StatusBadRequest, "failed to upload avatar", []interface{}{model.FieldError{Field: "avatar", Message: "avatar is not a valid image"}})
+	}
+	if cfg.Width > s.conf.AvatarMaxDimension || cfg.Height > s.conf.AvatarMaxDimension {
+		return nil, resp.Error(http.StatusBadRequest, "failed to upload avatar", []interface{}{model.FieldError{Field: "avatar", Message: fmt.Sprintf("avatar must not be larger than %dx%d pixels", s.conf.AvatarMaxDimension, s.conf.AvatarMaxDimension)}})
+	}
+
+	img, _, e := image.Decode(bytes.NewReader(data))
+	if e != nil {
+		return nil, resp.Error(http.StatusBadRequest, "failed to upload avatar", []interface{}{model.FieldError{Field: "avatar", Message: "avatar is not a valid image"}})
+	}
+
+	ext := ".png"
+	if sniffed == "image/jpeg" {
+		img = orient(img, jpegOrientation(data))
+		ext = ".jpg"
+	}
+
+	key := avatarKeyPrefix + id.String() + "/" + uuid.NewString() + ext
+	data, e = encodeAvatar(key, img)
+	if e != nil {
+		return nil, resp.Error(http.StatusInternalServerError, "failed to upload avatar", []interface{}{e.Error()})
+	}
+
+	if err := s.storeAvatar(ctx, key, data, img); err != nil {
+		return nil, err
+	}
+
+	u, err := s.repo.UpdateImg(ctx, id, key)
+	if err != nil {
+		s.removeAvatar(ctx, key)
+		return nil, err
+	}
+
+	if isAvatarKey(current.Img) {
+		s.removeAvatar(ctx, current.Img)
+	}
+
+	s.publish(model.UserEventImageChanged, u)
+	localizeUser(ctx, &u)
+	return &u, nil
+}
+
+// Watch streams the changes of users made after the event afterID, or from now
+// on when it is 0, until ctx is done.
+func (s *users) Watch(ctx context.Context, afterID uint64) (<-chan model.UserEvent, *resp.Err) {
+	log.Trace()
+
+	return s.events.Subscribe(ctx, afterID)
+}
+
+func (s *users) publish(eventType string, u model.User) {
+	s.events.Publish(model.UserEvent{
+		Type:   eventType,
+		UserID: u.ID,
+		User:   u,
+	})
+}
+
+// storeAvatar saves the avatar and its thumbnails, leaving nothing behind on failure.
+func (s *users) storeAvatar(ctx context.Context, key string, data []byte, img image.Image) *resp.Err {
+	if err := s.blobs.Put(ctx, key, bytes.NewReader(data)); err != nil {
+		return err
+	}
+
+	for _, size := range avatarSizes {
+		variantKey := avatarVariantKey(key, size)
+
+		thumb, e := encodeAvatar(variantKey, thumbnail(img, size))
+		if e != nil {
+			s.removeAvatar(ctx, key)
+			return resp.Error(http.StatusInternalServerError, "failed to upload avatar", []interface{}{e.Error()})
+		}
+
+		if err := s.blobs.Put(ctx, variantKey, bytes.NewReader(thumb)); err != nil {
+			s.removeAvatar(ctx, key)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeAvatar deletes the avatar and its thumbnails, only logging failures.
+func (s *users) removeAvatar(ctx context.Context, key string) {
+	ctx = context.WithoutCancel(ctx)
+
+	keys := []string{key}
+	for _, size := range avatarSizes {
+		keys = append(keys, avatarVariantKey(key, size))
+	}
+
+	for _, k := range keys {
+		if err := s.blobs.Delete(ctx, k); err != nil {
+			log.Errorf("Failed to remove avatar %s: %v", k, err)
+		}
+	}
+}
+
+// isValidImg tells whether img can be set as the image of the user.
+func isValidImg(id uuid.UUID, img string) bool {
+	if img == "" {
+		return true
+	}
+	if isAvatarKey(img) {
+		return strings.HasPrefix(img, avatarKeyPrefix+id.String()+"/")
+	}
+
+	u, err := url.Parse(img)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func isAvatarKey(img string) bool {
+	return strings.HasPrefix(img, avatarKeyPrefix)
+}
+
+func (s *users) nicknameCutoff() time.Time {
+	return time.Now().Add(-s.conf.NicknameGracePeriod)
+}
+
+// normalizeUserFilter trims the text filters, normalizes the country and checks the range.
+func normalizeUserFilter(filter *model.UserFilter) error {
+	if !filter.CreatedFrom.IsZero() && !filter.CreatedTo.IsZero() && filter.CreatedFrom.After(filter.CreatedTo) {
+		return errors.New("created_from must not be after created_to")
+	}
+
+	filter.Country = strings.TrimSpace(filter.Country)
+	if code, ok := model.ParseCountry(filter.Country); ok {
+		filter.Country = code
+	}
+	filter.City = strings.TrimSpace(filter.City)
+	filter.ClubName = strings.TrimSpace(filter.ClubName)
+	filter.NicknamePrefix = strings.TrimSpace(filter.NicknamePrefix)
+
+	return nil
+}
+
+func encodePageToken(created time.Time, id uuid.UUID) string {
+	raw := created.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodePageToken(token string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+
+	createdStr, idStr, found := strings.Cut(string(raw), "|")
+	if !found {
+		return time.Time{}, uuid.Nil, fmt.Errorf("malformed page token")
+	}
+
+	created, err := time.Parse(time.RFC3339Nano, createdStr)
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return time.Time{}, uuid.Nil, err
+	}
+
+	return created, id, nil
+}
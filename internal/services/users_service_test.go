@@ -0,0 +1,381 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	model "github.com/demkowo/users/internal/models"
+	"github.com/demkowo/utils/resp"
+	"github.com/google/uuid"
+)
+
+// fakeUsersRepo implements the parts of UsersRepo the tests exercise. Calling
+// any other method panics on the nil embedded interface.
+type fakeUsersRepo struct {
+	UsersRepo
+
+	// conflicts maps nicknames to the conflict NicknameConflict reports.
+	conflicts map[string]string
+	// rowErrs maps nicknames to the error AddBatch reports for their row.
+	rowErrs map[string]*resp.Err
+	// failBatch is the 1-based AddBatch call that fails as a whole.
+	failBatch int
+	// batches are the nicknames passed to each AddBatch call.
+	batches [][]string
+	// listed are the users List and ListAfter page through, newest first.
+	listed []model.User
+}
+
+func (r *fakeUsersRepo) NicknameConflict(ctx context.Context, nickname string, userID uuid.UUID, since time.Time) (string, *resp.Err) {
+	return r.conflicts[nickname], nil
+}
+
+func (r *fakeUsersRepo) AddBatch(ctx context.Context, users []model.User) ([]model.User, []*resp.Err, *resp.Err) {
+	nicknames := make([]string, len(users))
+	for i, u := range users {
+		nicknames[i] = u.Nickname
+	}
+	r.batches = append(r.batches, nicknames)
+
+	if len(r.batches) == r.failBatch {
+		return nil, nil, resp.Error(http.StatusInternalServerError, "failed to import users", []interface{}{"connection reset"})
+	}
+
+	saved := make([]model.User, len(users))
+	rowErrs := make([]*resp.Err, len(users))
+	for i, u := range users {
+		if err := r.rowErrs[u.Nickname]; err != nil {
+			rowErrs[i] = err
+			continue
+		}
+		saved[i] = u
+	}
+	return saved, rowErrs, nil
+}
+
+func (r *fakeUsersRepo) List(ctx context.Context, limit, offset int32) ([]model.User, *resp.Err) {
+	us := r.listed[min(int(offset), len(r.listed)):]
+	return slices.Clone(us[:min(int(limit), len(us))]), nil
+}
+
+func (r *fakeUsersRepo) ListAfter(ctx context.Context, created time.Time, id uuid.UUID, limit int32) ([]model.User, *resp.Err) {
+	i := slices.IndexFunc(r.listed, func(u model.User) bool {
+		return u.Created.Before(created) || u.Created.Equal(created) && bytes.Compare(u.ID[:], id[:]) < 0
+	})
+	if i < 0 {
+		return nil, nil
+	}
+	return r.List(ctx, limit, int32(i))
+}
+
+type fakeEvents struct {
+	published []model.UserEvent
+}
+
+func (e *fakeEvents) Publish(event model.UserEvent) model.UserEvent {
+	event.ID = uint64(len(e.published) + 1)
+	e.published = append(e.published, event)
+	return event
+}
+
+func (e *fakeEvents) Subscribe(ctx context.Context, afterID uint64) (<-chan model.UserEvent, *resp.Err) {
+	return nil, nil
+}
+
+func importUsers(nicknames ...string) []model.User {
+	us := make([]model.User, len(nicknames))
+	for i, n := range nicknames {
+		us[i] = model.User{Nickname: n}
+	}
+	return us
+}
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		name        string
+		conf        UsersConfig
+		repo        fakeUsersRepo
+		rows        []model.User
+		wantErr     string
+		wantErrors  []string
+		wantBatches [][]string
+	}{
+		{
+			name:    "no rows",
+			rows:    nil,
+			wantErr: "no rows to import",
+		},
+		{
+			name:    "too many rows",
+			conf:    UsersConfig{ImportMaxRows: 2},
+			rows:    importUsers("john", "anna", "mark"),
+			wantErr: "at most 2 rows can be imported at once",
+		},
+		{
+			name:        "rows up to the limit",
+			conf:        UsersConfig{ImportMaxRows: 2},
+			rows:        importUsers("john", "anna"),
+			wantErrors:  []string{"", ""},
+			wantBatches: [][]string{{"john", "anna"}},
+		},
+		{
+			name: "invalid rows are skipped",
+			rows: []model.User{
+				{Nickname: " john "},
+				{Nickname: ""},
+				{Nickname: "jo"},
+				{Nickname: "admin"},
+				{Nickname: "anna", Country: "Atlantis"},
+				{Nickname: "mark", Country: "poland"},
+			},
+			wantErrors: []string{
+				"",
+				"nickname is required",
+				"nickname must be 3 to 32 characters long",
+				"nickname is reserved",
+				"country must be an ISO 3166-1 country code or name",
+				"",
+			},
+			wantBatches: [][]string{{"john", "mark"}},
+		},
+		{
+			name: "duplicate rows",
+			rows: importUsers("john", "anna", "JOHN", "john", "ａｎｎａ"),
+			wantErrors: []string{
+				"",
+				"",
+				"nickname duplicates row 1",
+				"nickname duplicates row 1",
+				"nickname duplicates row 2",
+			},
+			wantBatches: [][]string{{"john", "anna"}},
+		},
+		{
+			name:        "taken nickname",
+			repo:        fakeUsersRepo{conflicts: map[string]string{"anna": "nickname is already taken"}},
+			rows:        importUsers("john", "anna"),
+			wantErrors:  []string{"", "nickname is already taken"},
+			wantBatches: [][]string{{"john"}},
+		},
+		{
+			name:        "rows are saved in batches",
+			conf:        UsersConfig{ImportBatchSize: 2},
+			rows:        importUsers("john", "anna", "mark", "kate", "adam"),
+			wantErrors:  []string{"", "", "", "", ""},
+			wantBatches: [][]string{{"john", "anna"}, {"mark", "kate"}, {"adam"}},
+		},
+		{
+			name: "failing row inside a batch",
+			conf: UsersConfig{ImportBatchSize: 3},
+			repo: fakeUsersRepo{rowErrs: map[string]*resp.Err{
+				"anna": resp.Error(http.StatusConflict, "failed to import users", []interface{}{"nickname is already taken"}),
+				"kate": resp.Error(http.StatusForbidden, "club is invite-only", nil),
+			}},
+			rows:        importUsers("john", "anna", "mark", "kate"),
+			wantErrors:  []string{"", "nickname is already taken", "", "club is invite-only"},
+			wantBatches: [][]string{{"john", "anna", "mark"}, {"kate"}},
+		},
+		{
+			name:        "failing batch",
+			conf:        UsersConfig{ImportBatchSize: 2},
+			repo:        fakeUsersRepo{failBatch: 2},
+			rows:        importUsers("john", "anna", "mark", "kate", "adam"),
+			wantErrors:  []string{"", "", "failed to import users", "failed to import users", ""},
+			wantBatches: [][]string{{"john", "anna"}, {"mark", "kate"}, {"adam"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := tt.repo
+			events := &fakeEvents{}
+			s := NewUsers(&repo, nil, events, tt.conf)
+
+			report, err := s.Import(context.Background(), tt.rows)
+			if tt.wantErr != "" {
+				if err == nil || err.Code != http.StatusBadRequest || !strings.Contains(strings.Join(causes(err), ";"), tt.wantErr) {
+					t.Fatalf("err = %+v, want 400 with %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %+v", err)
+			}
+
+			gotErrors := make([]string, len(report.Results))
+			imported := 0
+			for i, r := range report.Results {
+				if r.Row != i+1 {
+					t.Errorf("results[%d].Row = %d, want %d", i, r.Row, i+1)
+				}
+				if (r.Error == "") != (r.UserID != uuid.Nil) {
+					t.Errorf("results[%d] = %+v, want a user id exactly when there is no error", i, r)
+				}
+				if r.Error == "" {
+					imported++
+				}
+				gotErrors[i] = r.Error
+			}
+			if !reflect.DeepEqual(gotErrors, tt.wantErrors) {
+				t.Errorf("row errors = %q, want %q", gotErrors, tt.wantErrors)
+			}
+			if !reflect.DeepEqual(repo.batches, tt.wantBatches) {
+				t.Errorf("batches = %q, want %q", repo.batches, tt.wantBatches)
+			}
+			if report.Total != len(tt.rows) || report.Imported != imported || report.Failed != len(tt.rows)-imported {
+				t.Errorf("report = %d total, %d imported, %d failed, want %d, %d, %d",
+					report.Total, report.Imported, report.Failed, len(tt.rows), imported, len(tt.rows)-imported)
+			}
+			if len(events.published) != imported {
+				t.Errorf("%d events published, want %d", len(events.published), imported)
+			}
+		})
+	}
+}
+
+func causes(err *resp.Err) []string {
+	cs := make([]string, len(err.Causes))
+	for i, c := range err.Causes {
+		cs[i], _ = c.(string)
+	}
+	return cs
+}
+
+func TestPageToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		created time.Time
+		id      uuid.UUID
+	}{
+		{name: "nanoseconds", created: time.Date(2025, 3, 14, 15, 9, 26, 535897932, time.UTC), id: uuid.New()},
+		{name: "whole seconds", created: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), id: uuid.New()},
+		{name: "other time zone", created: time.Date(2025, 6, 30, 23, 59, 59, 1, time.FixedZone("CEST", 2*60*60)), id: uuid.New()},
+		{name: "nil id", created: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), id: uuid.Nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := encodePageToken(tt.created, tt.id)
+			if strings.ContainsAny(token, "+/=") {
+				t.Errorf("token %q is not URL safe", token)
+			}
+
+			created, id, err := decodePageToken(token)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !created.Equal(tt.created) || id != tt.id {
+				t.Errorf("decoded %s, %s, want %s, %s", created, id, tt.created, tt.id)
+			}
+		})
+	}
+}
+
+func TestDecodePageTokenRejects(t *testing.T) {
+	created := time.Date(2025, 3, 14, 15, 9, 26, 535897932, time.UTC)
+	id := uuid.MustParse("6f1c1a5e-7a0b-4c7e-9a39-2f1f4b1f9d2a")
+	token := encodePageToken(created, id)
+	raw := created.Format(time.RFC3339Nano) + "|" + id.String()
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "not base64", token: "not a token!"},
+		{name: "padded base64", token: base64.URLEncoding.EncodeToString([]byte(raw))},
+		{name: "standard base64", token: base64.RawStdEncoding.EncodeToString([]byte(raw + "??"))},
+		{name: "character outside the alphabet", token: "*" + token[1:]},
+		{name: "truncated", token: token[:len(token)-4]},
+		{name: "trailing garbage", token: token + "A"},
+		{name: "no separator", token: encode(strings.Replace(raw, "|", ";", 1))},
+		{name: "tampered time", token: encode(strings.Replace(raw, "2025-03-14T", "2025-13-14T", 1))},
+		{name: "local time", token: encode(strings.Replace(raw, "Z|", "|", 1))},
+		{name: "tampered id", token: encode(strings.Replace(raw, "6f1c", "6g1c", 1))},
+		{name: "extra field", token: encode(raw + "|" + id.String())},
+		{name: "missing id", token: encode(created.Format(time.RFC3339Nano) + "|")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if created, id, err := decodePageToken(tt.token); err == nil {
+				t.Errorf("decodePageToken(%q) = %s, %s, want an error", tt.token, created, id)
+			}
+		})
+	}
+}
+
+func TestListPage(t *testing.T) {
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	// listed are newest first; anna and mark were created at the same time
+	// and are told apart by id.
+	listed := []model.User{
+		{ID: uuid.MustParse("00000000-0000-0000-0000-000000000005"), Nickname: "john", Created: base.Add(3 * time.Second)},
+		{ID: uuid.MustParse("00000000-0000-0000-0000-000000000004"), Nickname: "anna", Created: base.Add(2 * time.Second)},
+		{ID: uuid.MustParse("00000000-0000-0000-0000-000000000003"), Nickname: "mark", Created: base.Add(2 * time.Second)},
+		{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002"), Nickname: "kate", Created: base.Add(time.Second + 1)},
+		{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001"), Nickname: "adam", Created: base},
+	}
+
+	tests := []struct {
+		name      string
+		listed    []model.User
+		limit     int32
+		wantPages [][]string
+	}{
+		{name: "partial last page", listed: listed, limit: 2, wantPages: [][]string{{"john", "anna"}, {"mark", "kate"}, {"adam"}}},
+		{name: "full last page", listed: listed[:4], limit: 2, wantPages: [][]string{{"john", "anna"}, {"mark", "kate"}, {}}},
+		{name: "single page", listed: listed, limit: 10, wantPages: [][]string{{"john", "anna", "mark", "kate", "adam"}}},
+		{name: "no users", listed: nil, limit: 2, wantPages: [][]string{{}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewUsers(&fakeUsersRepo{listed: tt.listed}, nil, nil, UsersConfig{})
+
+			var pages [][]string
+			token := ""
+			for len(pages) <= len(listed) {
+				us, next, err := s.ListPage(context.Background(), tt.limit, token)
+				if err != nil {
+					t.Fatalf("unexpected error: %+v", err)
+				}
+				page := []string{}
+				for _, u := range us {
+					page = append(page, u.Nickname)
+				}
+				pages = append(pages, page)
+				if next == "" {
+					break
+				}
+				token = next
+			}
+
+			if !reflect.DeepEqual(pages, tt.wantPages) {
+				t.Errorf("pages = %q, want %q", pages, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestListPageInvalidToken(t *testing.T) {
+	s := NewUsers(&fakeUsersRepo{}, nil, nil, UsersConfig{})
+	token := encodePageToken(time.Now(), uuid.New())
+
+	for _, pageToken := range []string{"not a token!", token[:len(token)-4], "*" + token[1:]} {
+		t.Run(pageToken, func(t *testing.T) {
+			_, _, err := s.ListPage(context.Background(), 10, pageToken)
+			if err == nil || err.Code != http.StatusBadRequest || !reflect.DeepEqual(causes(err), []string{"invalid page token"}) {
+				t.Errorf("err = %+v, want 400 invalid page token", err)
+			}
+		})
+	}
+}
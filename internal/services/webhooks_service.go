@@ -0,0 +1,452 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	model "github.com/demkowo/users/internal/models"
+	"github.com/demkowo/utils/resp"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// Headers sent with every webhook delivery. The signature is
+// "sha256=" + hex(HMAC-SHA256(secret, body)).
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookIDHeader        = "X-Webhook-Id"
+)
+
+// webhookEventTypes are the event types a webhook can subscribe to.
+var webhookEventTypes = []string{
+	model.UserEventCreated,
+	model.UserEventUpdated,
+	model.UserEventImageChanged,
+	model.UserEventDeleted,
+}
+
+type WebhooksRepo interface {
+	Add(ctx context.Context, webhook model.Webhook) (model.Webhook, *resp.Err)
+	Delete(ctx context.Context, id uuid.UUID) (model.Webhook, *resp.Err)
+	GetByID(ctx context.Context, id uuid.UUID) (model.Webhook, *resp.Err)
+	List(ctx context.Context, limit, offset int32) ([]model.Webhook, int64, *resp.Err)
+	ListForEvent(ctx context.Context, eventType string) ([]model.Webhook, *resp.Err)
+	Update(ctx context.Context, webhook model.Webhook) (model.Webhook, *resp.Err)
+	AddDelivery(ctx context.Context, delivery model.WebhookDelivery) (model.WebhookDelivery, *resp.Err)
+	ClaimDeliveries(ctx context.Context, limit int32, leaseUntil time.Time) ([]model.WebhookDelivery, *resp.Err)
+	GetDelivery(ctx context.Context, id uuid.UUID) (model.WebhookDelivery, *resp.Err)
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit, offset int32) ([]model.WebhookDelivery, int64, *resp.Err)
+	RecordAttempt(ctx context.Context, delivery model.WebhookDelivery) *resp.Err
+}
+
+type Webhooks interface {
+	Add(ctx context.Context, webhook *model.Webhook) *resp.Err
+	Delete(ctx context.Context, id uuid.UUID) *resp.Err
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Webhook, *resp.Err)
+	List(ctx context.Context, limit, offset int32) ([]model.Webhook, int64, *resp.Err)
+	Update(ctx context.Context, webhook *model.Webhook) *resp.Err
+	ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit, offset int32) ([]model.WebhookDelivery, int64, *resp.Err)
+	Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (*model.WebhookDelivery, *resp.Err)
+	Publish(ctx context.Context, event model.OutboxEvent) error
+	Run(ctx context.Context)
+}
+
+// WebhooksConfig holds the tunables of the webhook dispatcher.
+type WebhooksConfig struct {
+	// PollInterval is how long the dispatcher sleeps when nothing is due.
+	PollInterval time.Duration
+	// BatchSize is the number of deliveries sent at once.
+	BatchSize int32
+	// Timeout limits a single delivery request.
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts after which a delivery fails.
+	MaxAttempts int32
+	// RetryBackoff is the delay before the first retry of a delivery; it
+	// doubles with every further attempt up to MaxBackoff.
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+}
+
+type webhooks struct {
+	repo   WebhooksRepo
+	client *http.Client
+	conf   WebhooksConfig
+}
+
+func NewWebhooks(repo WebhooksRepo, conf WebhooksConfig) Webhooks {
+	log.Trace()
+
+	if conf.PollInterval <= 0 {
+		conf.PollInterval = time.Second
+	}
+	if conf.BatchSize <= 0 {
+		conf.BatchSize = 20
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = 10 * time.Second
+	}
+	if conf.MaxAttempts <= 0 {
+		conf.MaxAttempts = 8
+	}
+	if conf.RetryBackoff <= 0 {
+		conf.RetryBackoff = 30 * time.Second
+	}
+	if conf.MaxBackoff < conf.RetryBackoff {
+		conf.MaxBackoff = conf.RetryBackoff
+	}
+
+	return &webhooks{
+		repo:   repo,
+		client: &http.Client{Timeout: conf.Timeout},
+		conf:   conf,
+	}
+}
+
+// Add registers the webhook. When no secret is given a random one is
+// generated; the secret is only returned here.
+func (s *webhooks) Add(ctx context.Context, webhook *model.Webhook) *resp.Err {
+	log.Trace()
+
+	if err := normalizeWebhook(webhook); err != nil {
+		return resp.Error(http.StatusBadRequest, "failed to add webhook", []interface{}{err.Error()})
+	}
+	if webhook.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			return resp.Error(http.StatusInternalServerError, "failed to add webhook", []interface{}{err.Error()})
+		}
+		webhook.Secret = secret
+	}
+
+	w, err := s.repo.Add(ctx, *webhook)
+	if err != nil {
+		return err
+	}
+
+	*webhook = w
+	return nil
+}
+
+func (s *webhooks) Delete(ctx context.Context, id uuid.UUID) *resp.Err {
+	log.Trace()
+
+	if _, err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *webhooks) GetByID(ctx context.Context, id uuid.UUID) (*model.Webhook, *resp.Err) {
+	log.Trace()
+
+	w, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	w.Secret = ""
+	return &w, nil
+}
+
+func (s *webhooks) List(ctx context.Context, limit, offset int32) ([]model.Webhook, int64, *resp.Err) {
+	log.Trace()
+
+	ws, total, err := s.repo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i := range ws {
+		ws[i].Secret = ""
+	}
+	return ws, total, nil
+}
+
+// Update replaces the webhook's url, event types and active flag. The secret is
+// rotated only when a new one is given.
+func (s *webhooks) Update(ctx context.Context, webhook *model.Webhook) *resp.Err {
+	log.Trace()
+
+	if err := normalizeWebhook(webhook); err != nil {
+		return resp.Error(http.StatusBadRequest, "failed to update webhook", []interface{}{err.Error()})
+	}
+
+	w, err := s.repo.Update(ctx, *webhook)
+	if err != nil {
+		return err
+	}
+
+	w.Secret = ""
+	*webhook = w
+	return nil
+}
+
+// ListDeliveries returns the deliveries of the webhook, newest first.
+func (s *webhooks) ListDeliveries(ctx context.Context, webhookID uuid.UUID, limit, offset int32) ([]model.WebhookDelivery, int64, *resp.Err) {
+	log.Trace()
+
+	if _, err := s.repo.GetByID(ctx, webhookID); err != nil {
+		return nil, 0, err
+	}
+
+	return s.repo.ListDeliveries(ctx, webhookID, limit, offset)
+}
+
+// Redeliver queues a new delivery of the same event as deliveryID, whatever
+// the outcome of the original one.
+func (s *webhooks) Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) (*model.WebhookDelivery, *resp.Err) {
+	log.Trace()
+
+	d, err := s.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if d.WebhookID != webhookID {
+		return nil, resp.Error(http.StatusNotFound, "failed to redeliver webhook", []interface{}{"delivery not found"})
+	}
+
+	d, err = s.repo.AddDelivery(ctx, model.WebhookDelivery{
+		ID:        uuid.New(),
+		WebhookID: d.WebhookID,
+		EventType: d.EventType,
+		Payload:   d.Payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+// Publish queues a delivery of the outbox event to every webhook subscribed to it. Delivery
+// ids are derived from the event, so queueing it again is a no-op.
+func (s *webhooks) Publish(ctx context.Context, event model.OutboxEvent) error {
+	log.Trace()
+
+	ws, err := s.repo.ListForEvent(ctx, event.Type)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks for %s event: %v", event.Type, err)
+	}
+	if len(ws) == 0 {
+		return nil
+	}
+
+	payload, e := webhookPayload(event)
+	if e != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event.Type, e)
+	}
+
+	for _, w := range ws {
+		_, err := s.repo.AddDelivery(ctx, model.WebhookDelivery{
+			ID:        uuid.NewSHA1(w.ID, []byte(strconv.FormatInt(event.ID, 10))),
+			WebhookID: w.ID,
+			EventType: event.Type,
+			Payload:   payload,
+		})
+		if err != nil && err.Code != http.StatusConflict {
+			return fmt.Errorf("failed to queue delivery to webhook %s: %v", w.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// webhookPayload is the body delivered for the outbox event.
+func webhookPayload(event model.OutboxEvent) ([]byte, error) {
+	var user model.User
+	if err := json.Unmarshal(event.Payload, &user); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(model.UserEvent{
+		ID:     uint64(event.ID),
+		Type:   event.Type,
+		UserID: event.UserID,
+		User:   user,
+		Time:   event.Created,
+	})
+}
+
+// Run sends due deliveries until ctx is done. Full batches are followed by the
+// next one right away; otherwise it waits PollInterval.
+func (s *webhooks) Run(ctx context.Context) {
+	log.Trace()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		wait := s.conf.PollInterval
+		if n := s.dispatch(ctx); n == int(s.conf.BatchSize) {
+			wait = 0
+		}
+
+		timer.Reset(wait)
+	}
+}
+
+// dispatch sends a batch of due deliveries and returns how many it claimed.
+func (s *webhooks) dispatch(ctx context.Context) int {
+	// the lease outlives every request of the batch, so a delivery is only
+	// picked up again if the dispatcher died before recording its attempt
+	ds, err := s.repo.ClaimDeliveries(ctx, s.conf.BatchSize, time.Now().Add(2*s.conf.Timeout))
+	if err != nil {
+		log.Errorf("Failed to claim webhook deliveries: %v", err)
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	for _, d := range ds {
+		wg.Add(1)
+		go func(d model.WebhookDelivery) {
+			defer wg.Done()
+			s.deliver(ctx, d)
+		}(d)
+	}
+	wg.Wait()
+
+	return len(ds)
+}
+
+func (s *webhooks) deliver(ctx context.Context, d model.WebhookDelivery) {
+	w, err := s.repo.GetByID(ctx, d.WebhookID)
+	if err != nil {
+		log.Errorf("Failed to get webhook %s: %v", d.WebhookID, err)
+		return
+	}
+
+	var (
+		code int
+		e    error
+	)
+	if w.Active {
+		code, e = s.send(ctx, w, d)
+	} else {
+		e = fmt.Errorf("webhook is inactive")
+	}
+
+	d.Attempts++
+	d.LastStatusCode = int32(code)
+	d.LastError = ""
+	d.NextAttempt = time.Now()
+	switch {
+	case !w.Active:
+		d.Status = model.WebhookDeliveryFailed
+		d.LastError = e.Error()
+	case e == nil:
+		d.Status = model.WebhookDeliverySucceeded
+	case d.Attempts >= s.conf.MaxAttempts:
+		d.Status = model.WebhookDeliveryFailed
+		d.LastError = e.Error()
+	default:
+		d.Status = model.WebhookDeliveryPending
+		d.LastError = e.Error()
+		d.NextAttempt = time.Now().Add(backoff(s.conf.RetryBackoff, s.conf.MaxBackoff, d.Attempts))
+	}
+
+	if err := s.repo.RecordAttempt(context.WithoutCancel(ctx), d); err != nil {
+		log.Errorf("Failed to record delivery %s: %v", d.ID, err)
+	}
+}
+
+// send posts the delivery and returns the status code; anything but 2xx is an error.
+func (s *webhooks) send(ctx context.Context, w model.Webhook, d model.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(w.Secret, d.Payload))
+	req.Header.Set(WebhookEventHeader, d.EventType)
+	req.Header.Set(WebhookDeliveryHeader, d.ID.String())
+	req.Header.Set(WebhookIDHeader, w.ID.String())
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("unexpected response status %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
+
+// SignWebhookPayload returns the value of the signature header of a delivery
+// with the given payload.
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// normalizeWebhook checks the url and dedupes the event types, defaulting to all.
+func normalizeWebhook(webhook *model.Webhook) error {
+	webhook.URL = strings.TrimSpace(webhook.URL)
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https url")
+	}
+
+	if len(webhook.EventTypes) == 0 {
+		webhook.EventTypes = append([]string(nil), webhookEventTypes...)
+		return nil
+	}
+
+	types := make([]string, 0, len(webhook.EventTypes))
+	seen := make(map[string]bool, len(webhook.EventTypes))
+	for _, t := range webhook.EventTypes {
+		t = strings.TrimSpace(t)
+		if !isWebhookEventType(t) {
+			return fmt.Errorf("unknown event type %q", t)
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	webhook.EventTypes = types
+
+	return nil
+}
+
+func isWebhookEventType(t string) bool {
+	for _, et := range webhookEventTypes {
+		if t == et {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	model "github.com/demkowo/users/internal/models"
+	"github.com/demkowo/utils/resp"
+	"github.com/google/uuid"
+)
+
+// fakeWebhooksRepo implements the parts of WebhooksRepo the dispatcher and
+// Publish use. Calling any other method panics on the nil embedded interface.
+type fakeWebhooksRepo struct {
+	WebhooksRepo
+
+	mu       sync.Mutex
+	webhooks map[uuid.UUID]model.Webhook
+	claimed  []model.WebhookDelivery
+	recorded []model.WebhookDelivery
+	// queued deliveries are kept by id, like the unique key of the table.
+	queued map[uuid.UUID]model.WebhookDelivery
+}
+
+func (r *fakeWebhooksRepo) ListForEvent(ctx context.Context, eventType string) ([]model.Webhook, *resp.Err) {
+	var ws []model.Webhook
+	for _, w := range r.webhooks {
+		if w.Active && (len(w.EventTypes) == 0 || slices.Contains(w.EventTypes, eventType)) {
+			ws = append(ws, w)
+		}
+	}
+	return ws, nil
+}
+
+func (r *fakeWebhooksRepo) AddDelivery(ctx context.Context, delivery model.WebhookDelivery) (model.WebhookDelivery, *resp.Err) {
+	if _, ok := r.queued[delivery.ID]; ok {
+		return model.WebhookDelivery{}, resp.Error(http.StatusConflict, "failed to add webhook delivery", []interface{}{"delivery already exists"})
+	}
+	if r.queued == nil {
+		r.queued = make(map[uuid.UUID]model.WebhookDelivery)
+	}
+	r.queued[delivery.ID] = delivery
+	return delivery, nil
+}
+
+func (r *fakeWebhooksRepo) GetByID(ctx context.Context, id uuid.UUID) (model.Webhook, *resp.Err) {
+	w, ok := r.webhooks[id]
+	if !ok {
+		return model.Webhook{}, resp.Error(http.StatusNotFound, "failed to get webhook", []interface{}{"webhook not found"})
+	}
+	return w, nil
+}
+
+func (r *fakeWebhooksRepo) ClaimDeliveries(ctx context.Context, limit int32, leaseUntil time.Time) ([]model.WebhookDelivery, *resp.Err) {
+	ds := r.claimed[:min(int(limit), len(r.claimed))]
+	r.claimed = r.claimed[len(ds):]
+	return ds, nil
+}
+
+func (r *fakeWebhooksRepo) RecordAttempt(ctx context.Context, delivery model.WebhookDelivery) *resp.Err {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.recorded = append(r.recorded, delivery)
+	return nil
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	// The HMAC-SHA256 example from RFC 4231, test case 2.
+	got := SignWebhookPayload("Jefe", []byte("what do ya want for nothing?"))
+	want := "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if got != want {
+		t.Errorf("SignWebhookPayload = %q, want %q", got, want)
+	}
+
+	format := regexp.MustCompile(`^sha256=[0-9a-f]{64}$`)
+	for _, payload := range []string{"", "{}", `{"type":"created"}`} {
+		if sig := SignWebhookPayload("secret", []byte(payload)); !format.MatchString(sig) {
+			t.Errorf("SignWebhookPayload(%q) = %q, want it to match %s", payload, sig, format)
+		}
+	}
+
+	if SignWebhookPayload("a", []byte("{}")) == SignWebhookPayload("b", []byte("{}")) {
+		t.Error("payloads signed with different secrets have the same signature")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		base, max time.Duration
+		attempts  int32
+		want      time.Duration
+	}{
+		{base: 30 * time.Second, max: 5 * time.Minute, attempts: 0, want: 30 * time.Second},
+		{base: 30 * time.Second, max: 5 * time.Minute, attempts: 1, want: 30 * time.Second},
+		{base: 30 * time.Second, max: 5 * time.Minute, attempts: 2, want: time.Minute},
+		{base: 30 * time.Second, max: 5 * time.Minute, attempts: 3, want: 2 * time.Minute},
+		{base: 30 * time.Second, max: 5 * time.Minute, attempts: 4, want: 4 * time.Minute},
+		{base: 30 * time.Second, max: 5 * time.Minute, attempts: 5, want: 5 * time.Minute},
+		{base: 30 * time.Second, max: 5 * time.Minute, attempts: 1000, want: 5 * time.Minute},
+		{base: time.Minute, max: time.Minute, attempts: 3, want: time.Minute},
+		{base: 10 * time.Minute, max: 5 * time.Minute, attempts: 1, want: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.base, tt.max, tt.attempts); got != tt.want {
+			t.Errorf("backoff(%s, %s, %d) = %s, want %s", tt.base, tt.max, tt.attempts, got, tt.want)
+		}
+	}
+}
+
+// sentRequest is what a webhook endpoint received.
+type sentRequest struct {
+	method, path string
+	header       http.Header
+	body         []byte
+}
+
+func TestDeliver(t *testing.T) {
+	const retryBackoff = time.Minute
+
+	tests := []struct {
+		name     string
+		status   int
+		inactive bool
+		down     bool
+		slow     bool
+		attempts int32
+
+		wantStatus   string
+		wantCode     int32
+		wantError    string
+		wantRequest  bool
+		wantNextWait time.Duration
+	}{
+		{
+			name:        "succeeded",
+			status:      http.StatusOK,
+			wantStatus:  model.WebhookDeliverySucceeded,
+			wantCode:    http.StatusOK,
+			wantRequest: true,
+		},
+		{
+			name:        "succeeded without content",
+			status:      http.StatusNoContent,
+			attempts:    2,
+			wantStatus:  model.WebhookDeliverySucceeded,
+			wantCode:    http.StatusNoContent,
+			wantRequest: true,
+		},
+		{
+			name:         "retried after an error status",
+			status:       http.StatusInternalServerError,
+			wantStatus:   model.WebhookDeliveryPending,
+			wantCode:     http.StatusInternalServerError,
+			wantError:    "unexpected response status 500 Internal Server Error",
+			wantRequest:  true,
+			wantNextWait: retryBackoff,
+		},
+		{
+			name:         "retried later after further errors",
+			status:       http.StatusNotFound,
+			attempts:     2,
+			wantStatus:   model.WebhookDeliveryPending,
+			wantCode:     http.StatusNotFound,
+			wantError:    "unexpected response status 404 Not Found",
+			wantRequest:  true,
+			wantNextWait: 4 * retryBackoff,
+		},
+		{
+			name:        "failed after the last attempt",
+			status:      http.StatusBadGateway,
+			attempts:    4,
+			wantStatus:  model.WebhookDeliveryFailed,
+			wantCode:    http.StatusBadGateway,
+			wantError:   "unexpected response status 502 Bad Gateway",
+			wantRequest: true,
+		},
+		{
+			name:         "retried when the endpoint is down",
+			down:         true,
+			wantStatus:   model.WebhookDeliveryPending,
+			wantError:    "connection refused",
+			wantNextWait: retryBackoff,
+		},
+		{
+			name:         "retried after a timeout",
+			slow:         true,
+			wantStatus:   model.WebhookDeliveryPending,
+			wantError:    "Client.Timeout exceeded",
+			wantRequest:  true,
+			wantNextWait: retryBackoff,
+		},
+		{
+			name:       "failed when the webhook is inactive",
+			status:     http.StatusOK,
+			inactive:   true,
+			attempts:   1,
+			wantStatus: model.WebhookDeliveryFailed,
+			wantError:  "webhook is inactive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := make(chan sentRequest, 1)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				requests <- sentRequest{method: r.Method, path: r.URL.Path, header: r.Header, body: body}
+				if tt.slow {
+					select {
+					case <-r.Context().Done():
+					case <-time.After(time.Second):
+					}
+					return
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+			if tt.down {
+				srv.Close()
+			}
+
+			webhook := model.Webhook{ID: uuid.New(), URL: srv.URL + "/hook", Secret: "secret", Active: !tt.inactive}
+			repo := &fakeWebhooksRepo{webhooks: map[uuid.UUID]model.Webhook{webhook.ID: webhook}}
+			s := NewWebhooks(repo, WebhooksConfig{
+				Timeout:      100 * time.Millisecond,
+				MaxAttempts:  5,
+				RetryBackoff: retryBackoff,
+				MaxBackoff:   time.Hour,
+			}).(*webhooks)
+
+			d := model.WebhookDelivery{
+				ID:        uuid.New(),
+				WebhookID: webhook.ID,
+				EventType: model.UserEventCreated,
+				Payload:   []byte(`{"type":"created"}`),
+				Status:    model.WebhookDeliveryPending,
+				Attempts:  tt.attempts,
+			}
+
+			before := time.Now()
+			s.deliver(context.Background(), d)
+			after := time.Now()
+
+			if len(repo.recorded) != 1 {
+				t.Fatalf("%d attempts recorded, want 1", len(repo.recorded))
+			}
+			got := repo.recorded[0]
+			if got.Status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", got.Status, tt.wantStatus)
+			}
+			if got.Attempts != tt.attempts+1 {
+				t.Errorf("attempts = %d, want %d", got.Attempts, tt.attempts+1)
+			}
+			if got.LastStatusCode != tt.wantCode {
+				t.Errorf("last status code = %d, want %d", got.LastStatusCode, tt.wantCode)
+			}
+			if !strings.Contains(got.LastError, tt.wantError) || (tt.wantError == "") != (got.LastError == "") {
+				t.Errorf("last error = %q, want %q", got.LastError, tt.wantError)
+			}
+			if got.NextAttempt.Before(before.Add(tt.wantNextWait)) || got.NextAttempt.After(after.Add(tt.wantNextWait)) {
+				t.Errorf("next attempt in %s, want %s", got.NextAttempt.Sub(before), tt.wantNextWait)
+			}
+
+			if !tt.wantRequest {
+				if len(requests) != 0 {
+					t.Errorf("a request was sent, want none")
+				}
+				return
+			}
+			if len(requests) != 1 {
+				t.Fatalf("no request was sent, want one")
+			}
+			req := <-requests
+			if req.method != http.MethodPost || req.path != "/hook" {
+				t.Errorf("request = %s %s, want POST /hook", req.method, req.path)
+			}
+			if string(req.body) != string(d.Payload) {
+				t.Errorf("body = %s, want %s", req.body, d.Payload)
+			}
+			headers := map[string]string{
+				"Content-Type":         "application/json",
+				WebhookSignatureHeader: SignWebhookPayload(webhook.Secret, d.Payload),
+				WebhookEventHeader:     d.EventType,
+				WebhookDeliveryHeader:  d.ID.String(),
+				WebhookIDHeader:        webhook.ID.String(),
+			}
+			for h, want := range headers {
+				if got := req.header.Get(h); got != want {
+					t.Errorf("%s header = %q, want %q", h, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDeliverUnknownWebhook(t *testing.T) {
+	repo := &fakeWebhooksRepo{}
+	s := NewWebhooks(repo, WebhooksConfig{}).(*webhooks)
+
+	s.deliver(context.Background(), model.WebhookDelivery{ID: uuid.New(), WebhookID: uuid.New()})
+
+	if len(repo.recorded) != 0 {
+		t.Errorf("recorded %+v, want nothing", repo.recorded)
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	webhook := model.Webhook{ID: uuid.New(), URL: srv.URL, Active: true}
+	repo := &fakeWebhooksRepo{webhooks: map[uuid.UUID]model.Webhook{webhook.ID: webhook}}
+	for i := 0; i < 3; i++ {
+		repo.claimed = append(repo.claimed, model.WebhookDelivery{ID: uuid.New(), WebhookID: webhook.ID, Status: model.WebhookDeliveryPending})
+	}
+	s := NewWebhooks(repo, WebhooksConfig{BatchSize: 2}).(*webhooks)
+
+	for i, want := range []int{2, 1, 0} {
+		if n := s.dispatch(context.Background()); n != want {
+			t.Errorf("dispatch %d claimed %d deliveries, want %d", i+1, n, want)
+		}
+	}
+
+	if len(repo.recorded) != 3 {
+		t.Fatalf("%d attempts recorded, want 3", len(repo.recorded))
+	}
+	for _, d := range repo.recorded {
+		if d.Status != model.WebhookDeliverySucceeded {
+			t.Errorf("delivery %s status = %q, want %q", d.ID, d.Status, model.WebhookDeliverySucceeded)
+		}
+	}
+}
+
+func TestPublish(t *testing.T) {
+	all := model.Webhook{ID: uuid.New(), Active: true}
+	deleted := model.Webhook{ID: uuid.New(), EventTypes: []string{model.UserEventDeleted}, Active: true}
+	inactive := model.Webhook{ID: uuid.New(), Active: false}
+	repo := &fakeWebhooksRepo{webhooks: map[uuid.UUID]model.Webhook{all.ID: all, deleted.ID: deleted, inactive.ID: inactive}}
+	s := NewWebhooks(repo, WebhooksConfig{})
+
+	user := model.User{ID: uuid.New(), Nickname: "john", Version: 3}
+	payload, _ := json.Marshal(user)
+	event := model.OutboxEvent{ID: 42, UserID: user.ID, Type: model.UserEventUpdated, Payload: payload, Created: time.Now().UTC()}
+
+	// the relay publishes an event again when its success isn't recorded
+	for i := 0; i < 2; i++ {
+		if err := s.Publish(context.Background(), event); err != nil {
+			t.Fatalf("publish %d: unexpected error: %v", i+1, err)
+		}
+	}
+
+	if len(repo.queued) != 1 {
+		t.Fatalf("%d deliveries queued, want 1", len(repo.queued))
+	}
+	for _, d := range repo.queued {
+		if d.WebhookID != all.ID || d.EventType != event.Type {
+			t.Errorf("queued %s event to %s, want %s event to %s", d.EventType, d.WebhookID, event.Type, all.ID)
+		}
+		var got model.UserEvent
+		if err := json.Unmarshal(d.Payload, &got); err != nil {
+			t.Fatalf("decoding the payload: %v", err)
+		}
+		want := model.UserEvent{ID: 42, Type: event.Type, UserID: user.ID, User: user, Time: event.Created}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("payload = %+v, want %+v", got, want)
+		}
+	}
+
+	if err := s.Publish(context.Background(), model.OutboxEvent{ID: 43, Type: model.UserEventCreated, Payload: []byte("{")}); err == nil {
+		t.Error("published an event with a broken payload, want an error")
+	}
+}